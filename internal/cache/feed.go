@@ -19,6 +19,10 @@ const (
 
 	// FeedCacheTTL is the TTL for feed cache (7 days)
 	FeedCacheTTL = 7 * 24 * time.Hour
+
+	// FeedRepostPrefix is the key prefix for the hash of repost attributions
+	// (postID -> reposterID) that goes with each user's feed
+	FeedRepostPrefix = "feed:reposts:user:"
 )
 
 // PostScore represents a post with its timestamp score for caching
@@ -27,6 +31,13 @@ type PostScore struct {
 	Timestamp int64 // Unix timestamp
 }
 
+// RepostScore is a post that entered a feed through a repost.
+type RepostScore struct {
+	PostID     int64
+	ReposterID int64
+	Timestamp  int64 // Unix timestamp of the repost
+}
+
 // FeedCache defines the interface for feed cache operations.
 // Using an interface enables testing with mocks and potential future backends.
 type FeedCache interface {
@@ -54,10 +65,28 @@ type FeedCache interface {
 	// Size returns the number of posts in a user's feed cache.
 	Size(ctx context.Context, userID int64) (int64, error)
 
+	// AddRepost adds a reposted post to a user's feed with "reposted by" attribution.
+	// No-op if the post is already in the feed (ZADD NX). Returns whether it was added.
+	AddRepost(ctx context.Context, userID, postID, reposterID int64, timestamp int64) (bool, error)
+
+	// RemoveRepost removes a post from a user's feed only if it got there through
+	// reposterID's repost (the original, or another repost, is left alone).
+	RemoveRepost(ctx context.Context, userID, postID, reposterID int64) error
+
+	// WarmReposts bulk-inserts reposts after WarmCache. Posts already present are kept as-is.
+	WarmReposts(ctx context.Context, userID int64, reposts []RepostScore) error
+
+	// GetRepostAttributions returns postID -> reposterID for the given posts
+	// (posts in the feed as originals are omitted).
+	GetRepostAttributions(ctx context.Context, userID int64, postIDs []int64) (map[int64]int64, error)
+
 	// Exists checks if a user has a feed cache entry.
 	// Returns false if the key doesn't exist (new user or TTL expired).
 	// Service layer should warm the cache when this returns false.
 	Exists(ctx context.Context, userID int64) (bool, error)
+
+	// Clear deletes a user's feed cache along with its repost attributions.
+	Clear(ctx context.Context, userID int64) error
 }
 
 // RedisFeedCache implements FeedCache using Redis Sorted Sets.
@@ -75,6 +104,11 @@ func feedKey(userID int64) string {
 	return fmt.Sprintf("%s%d", FeedCachePrefix, userID)
 }
 
+// repostKey returns the Redis key for a user's repost attributions.
+func repostKey(userID int64) string {
+	return fmt.Sprintf("%s%d", FeedRepostPrefix, userID)
+}
+
 // AddPost adds a post to a user's feed cache using a pipeline.
 // Pipeline: ZADD + ZREMRANGEBYRANK (trim to cap) + EXPIRE (refresh TTL)
 func (c *RedisFeedCache) AddPost(ctx context.Context, userID, postID int64, timestamp int64) error {
@@ -97,6 +131,9 @@ func (c *RedisFeedCache) AddPost(ctx context.Context, userID, postID int64, time
 	// Refresh TTL
 	pipe.Expire(ctx, key, FeedCacheTTL)
 
+	// The original takes precedence over an earlier repost of the same post
+	pipe.HDel(ctx, repostKey(userID), strconv.FormatInt(postID, 10))
+
 	_, err := pipe.Exec(ctx)
 	if err != nil {
 		log.Printf("[FeedCache] AddPost FAILED: user=%d post=%d err=%v", userID, postID, err)
@@ -114,7 +151,11 @@ func (c *RedisFeedCache) RemovePost(ctx context.Context, userID, postID int64) e
 	startTime := time.Now()
 	member := strconv.FormatInt(postID, 10)
 
-	removed, err := c.client.ZRem(ctx, key, member).Result()
+	pipe := c.client.Pipeline()
+	zrem := pipe.ZRem(ctx, key, member)
+	pipe.HDel(ctx, repostKey(userID), member)
+	_, err := pipe.Exec(ctx)
+	removed := zrem.Val()
 	if err != nil {
 		log.Printf("[FeedCache] RemovePost FAILED: user=%d post=%d err=%v", userID, postID, err)
 		return fmt.Errorf("remove post from feed: %w", err)
@@ -125,6 +166,125 @@ func (c *RedisFeedCache) RemovePost(ctx context.Context, userID, postID int64) e
 	return nil
 }
 
+// AddRepost adds a reposted post if it isn't in the feed yet, then records the attribution.
+func (c *RedisFeedCache) AddRepost(ctx context.Context, userID, postID, reposterID int64, timestamp int64) (bool, error) {
+	key := feedKey(userID)
+	member := strconv.FormatInt(postID, 10)
+
+	added, err := c.client.ZAddNX(ctx, key, redis.Z{Score: float64(timestamp), Member: member}).Result()
+	if err != nil {
+		log.Printf("[FeedCache] AddRepost FAILED: user=%d post=%d err=%v", userID, postID, err)
+		return false, fmt.Errorf("add repost to feed: %w", err)
+	}
+	if added == 0 {
+		log.Printf("[FeedCache] AddRepost SKIP: user=%d post=%d already in feed", userID, postID)
+		return false, nil
+	}
+
+	pipe := c.client.Pipeline()
+	pipe.HSet(ctx, repostKey(userID), member, reposterID)
+	pipe.ZRemRangeByRank(ctx, key, 0, int64(-FeedCacheCap-1))
+	pipe.Expire(ctx, key, FeedCacheTTL)
+	pipe.Expire(ctx, repostKey(userID), FeedCacheTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[FeedCache] AddRepost FAILED: user=%d post=%d err=%v", userID, postID, err)
+		return false, fmt.Errorf("add repost attribution: %w", err)
+	}
+
+	log.Printf("[FeedCache] AddRepost OK: user=%d post=%d reposter=%d", userID, postID, reposterID)
+	return true, nil
+}
+
+// RemoveRepost removes a post added by reposterID's repost.
+func (c *RedisFeedCache) RemoveRepost(ctx context.Context, userID, postID, reposterID int64) error {
+	member := strconv.FormatInt(postID, 10)
+
+	attributed, err := c.client.HGet(ctx, repostKey(userID), member).Int64()
+	if err == redis.Nil {
+		return nil // Not in the feed through a repost
+	}
+	if err != nil {
+		log.Printf("[FeedCache] RemoveRepost FAILED: user=%d post=%d err=%v", userID, postID, err)
+		return fmt.Errorf("get repost attribution: %w", err)
+	}
+	if attributed != reposterID {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	pipe.ZRem(ctx, feedKey(userID), member)
+	pipe.HDel(ctx, repostKey(userID), member)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[FeedCache] RemoveRepost FAILED: user=%d post=%d err=%v", userID, postID, err)
+		return fmt.Errorf("remove repost from feed: %w", err)
+	}
+
+	log.Printf("[FeedCache] RemoveRepost OK: user=%d post=%d reposter=%d", userID, postID, reposterID)
+	return nil
+}
+
+// WarmReposts bulk-inserts reposts with ZADD NX so posts already warmed as originals win.
+func (c *RedisFeedCache) WarmReposts(ctx context.Context, userID int64, reposts []RepostScore) error {
+	if len(reposts) == 0 {
+		return nil
+	}
+
+	key := feedKey(userID)
+	members := make([]redis.Z, len(reposts))
+	attributions := make(map[string]interface{}, len(reposts))
+	for i, r := range reposts {
+		member := strconv.FormatInt(r.PostID, 10)
+		members[i] = redis.Z{Score: float64(r.Timestamp), Member: member}
+		attributions[member] = r.ReposterID
+	}
+
+	pipe := c.client.Pipeline()
+	pipe.ZAddNX(ctx, key, members...)
+	pipe.HSet(ctx, repostKey(userID), attributions)
+	pipe.ZRemRangeByRank(ctx, key, 0, int64(-FeedCacheCap-1))
+	pipe.Expire(ctx, key, FeedCacheTTL)
+	pipe.Expire(ctx, repostKey(userID), FeedCacheTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[FeedCache] WarmReposts FAILED: user=%d reposts=%d err=%v", userID, len(reposts), err)
+		return fmt.Errorf("warm reposts: %w", err)
+	}
+
+	log.Printf("[FeedCache] WarmReposts OK: user=%d reposts=%d", userID, len(reposts))
+	return nil
+}
+
+// GetRepostAttributions reads the reposter of each post from the attribution hash.
+func (c *RedisFeedCache) GetRepostAttributions(ctx context.Context, userID int64, postIDs []int64) (map[int64]int64, error) {
+	result := make(map[int64]int64)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	fields := make([]string, len(postIDs))
+	for i, id := range postIDs {
+		fields[i] = strconv.FormatInt(id, 10)
+	}
+
+	values, err := c.client.HMGet(ctx, repostKey(userID), fields...).Result()
+	if err != nil {
+		log.Printf("[FeedCache] GetRepostAttributions FAILED: user=%d err=%v", userID, err)
+		return nil, fmt.Errorf("get repost attributions: %w", err)
+	}
+
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue // nil: not a repost
+		}
+		reposterID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			continue
+		}
+		result[postIDs[i]] = reposterID
+	}
+	return result, nil
+}
+
 // GetFeed retrieves post IDs from a user's feed cache.
 // If cursorScore is nil, returns the newest posts (ZREVRANGE).
 // If cursorScore is provided, returns posts with score < cursorScore (ZREVRANGEBYSCORE).
@@ -264,3 +424,14 @@ func (c *RedisFeedCache) Exists(ctx context.Context, userID int64) (bool, error)
 	log.Printf("[FeedCache] Exists: user=%d found=%t", userID, found)
 	return found, nil
 }
+
+// Clear deletes a user's feed cache and repost attributions (used when an account is purged).
+func (c *RedisFeedCache) Clear(ctx context.Context, userID int64) error {
+	if err := c.client.Del(ctx, feedKey(userID), repostKey(userID)).Err(); err != nil {
+		log.Printf("[FeedCache] Clear FAILED: user=%d err=%v", userID, err)
+		return fmt.Errorf("clear feed cache: %w", err)
+	}
+
+	log.Printf("[FeedCache] Clear OK: user=%d", userID)
+	return nil
+}
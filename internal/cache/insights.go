@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// InsightsCounterPrefix is the key prefix for a post's pending counters (hash)
+	InsightsCounterPrefix = "insights:post:"
+
+	// InsightsReachPrefix is the key prefix for a post's unique viewers (HyperLogLog)
+	InsightsReachPrefix = "insights:reach:post:"
+
+	// InsightsDirtyKey is the set of posts with counters waiting to be rolled up
+	InsightsDirtyKey = "insights:dirty"
+
+	// InsightsCounterTTL expires pending counters if the rollup job stops running
+	InsightsCounterTTL = 7 * 24 * time.Hour
+
+	// InsightsReachTTL expires the reach HLL of posts nobody has seen in a while
+	InsightsReachTTL = 90 * 24 * time.Hour
+)
+
+// Counter hash fields
+const (
+	fieldImpressionsFeed    = "impressions_feed"
+	fieldImpressionsProfile = "impressions_profile"
+	fieldImpressionsOther   = "impressions_other"
+	fieldProfileVisits      = "profile_visits"
+)
+
+// InsightCounters are counter increments for one post.
+type InsightCounters struct {
+	ImpressionsFeed    int64
+	ImpressionsProfile int64
+	ImpressionsOther   int64
+	ProfileVisits      int64
+}
+
+// Impressions returns the total impressions across sources.
+func (c InsightCounters) Impressions() int64 {
+	return c.ImpressionsFeed + c.ImpressionsProfile + c.ImpressionsOther
+}
+
+// IsZero reports whether there is nothing to record.
+func (c InsightCounters) IsZero() bool {
+	return c.Impressions() == 0 && c.ProfileVisits == 0
+}
+
+// InsightsCache buffers post insight counters in Redis until they are rolled up into Postgres.
+type InsightsCache interface {
+	// Record adds counters for posts seen by viewerID and marks the posts dirty.
+	// The viewer is added to the reach of every post with impressions.
+	Record(ctx context.Context, viewerID int64, counters map[int64]InsightCounters) error
+
+	// PopDirty removes and returns up to count dirty post IDs.
+	PopDirty(ctx context.Context, count int) ([]int64, error)
+
+	// TakeCounters atomically reads and clears a post's pending counters.
+	TakeCounters(ctx context.Context, postID int64) (InsightCounters, error)
+
+	// Restore adds counters back (after a failed rollup) and marks the post dirty again.
+	Restore(ctx context.Context, postID int64, counters InsightCounters) error
+
+	// GetReach returns the estimated number of unique viewers of a post.
+	GetReach(ctx context.Context, postID int64) (int64, error)
+}
+
+// RedisInsightsCache implements InsightsCache using Redis hashes, HyperLogLogs and a set.
+type RedisInsightsCache struct {
+	client *redis.Client
+}
+
+// NewInsightsCache creates a new InsightsCache backed by Redis.
+func NewInsightsCache(client *redis.Client) InsightsCache {
+	return &RedisInsightsCache{client: client}
+}
+
+func insightsCounterKey(postID int64) string {
+	return fmt.Sprintf("%s%d", InsightsCounterPrefix, postID)
+}
+
+func insightsReachKey(postID int64) string {
+	return fmt.Sprintf("%s%d", InsightsReachPrefix, postID)
+}
+
+// Record increments counters in a single pipeline.
+func (c *RedisInsightsCache) Record(ctx context.Context, viewerID int64, counters map[int64]InsightCounters) error {
+	if len(counters) == 0 {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	dirty := make([]interface{}, 0, len(counters))
+	for postID, counts := range counters {
+		if counts.IsZero() {
+			continue
+		}
+		incrCounters(ctx, pipe, postID, counts)
+		if counts.Impressions() > 0 {
+			reachKey := insightsReachKey(postID)
+			pipe.PFAdd(ctx, reachKey, viewerID)
+			pipe.Expire(ctx, reachKey, InsightsReachTTL)
+		}
+		dirty = append(dirty, strconv.FormatInt(postID, 10))
+	}
+	if len(dirty) == 0 {
+		return nil
+	}
+	pipe.SAdd(ctx, InsightsDirtyKey, dirty...)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[InsightsCache] Record FAILED: viewer=%d posts=%d err=%v", viewerID, len(dirty), err)
+		return fmt.Errorf("record insights: %w", err)
+	}
+	return nil
+}
+
+// PopDirty pops dirty post IDs with SPOP.
+func (c *RedisInsightsCache) PopDirty(ctx context.Context, count int) ([]int64, error) {
+	members, err := c.client.SPopN(ctx, InsightsDirtyKey, int64(count)).Result()
+	if err != nil {
+		log.Printf("[InsightsCache] PopDirty FAILED: err=%v", err)
+		return nil, fmt.Errorf("pop dirty posts: %w", err)
+	}
+
+	postIDs := make([]int64, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		postIDs = append(postIDs, id)
+	}
+	return postIDs, nil
+}
+
+// TakeCounters reads and deletes the counter hash in a MULTI so no increment is lost.
+func (c *RedisInsightsCache) TakeCounters(ctx context.Context, postID int64) (InsightCounters, error) {
+	key := insightsCounterKey(postID)
+
+	var getAll *redis.MapStringStringCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		getAll = pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		log.Printf("[InsightsCache] TakeCounters FAILED: post=%d err=%v", postID, err)
+		return InsightCounters{}, fmt.Errorf("take insight counters: %w", err)
+	}
+
+	values := getAll.Val()
+	parse := func(field string) int64 {
+		n, _ := strconv.ParseInt(values[field], 10, 64)
+		return n
+	}
+	return InsightCounters{
+		ImpressionsFeed:    parse(fieldImpressionsFeed),
+		ImpressionsProfile: parse(fieldImpressionsProfile),
+		ImpressionsOther:   parse(fieldImpressionsOther),
+		ProfileVisits:      parse(fieldProfileVisits),
+	}, nil
+}
+
+// Restore puts counters back after a failed rollup.
+func (c *RedisInsightsCache) Restore(ctx context.Context, postID int64, counters InsightCounters) error {
+	if counters.IsZero() {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	incrCounters(ctx, pipe, postID, counters)
+	pipe.SAdd(ctx, InsightsDirtyKey, strconv.FormatInt(postID, 10))
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[InsightsCache] Restore FAILED: post=%d err=%v", postID, err)
+		return fmt.Errorf("restore insight counters: %w", err)
+	}
+	return nil
+}
+
+// GetReach returns PFCOUNT of the post's viewers.
+func (c *RedisInsightsCache) GetReach(ctx context.Context, postID int64) (int64, error) {
+	n, err := c.client.PFCount(ctx, insightsReachKey(postID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("get reach: %w", err)
+	}
+	return n, nil
+}
+
+// incrCounters queues HINCRBY for every non-zero counter and refreshes the TTL.
+func incrCounters(ctx context.Context, pipe redis.Pipeliner, postID int64, counts InsightCounters) {
+	key := insightsCounterKey(postID)
+	for field, n := range map[string]int64{
+		fieldImpressionsFeed:    counts.ImpressionsFeed,
+		fieldImpressionsProfile: counts.ImpressionsProfile,
+		fieldImpressionsOther:   counts.ImpressionsOther,
+		fieldProfileVisits:      counts.ProfileVisits,
+	} {
+		if n != 0 {
+			pipe.HIncrBy(ctx, key, field, n)
+		}
+	}
+	pipe.Expire(ctx, key, InsightsCounterTTL)
+}
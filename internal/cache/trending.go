@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// TrendingHashtagsKey holds the latest trending hashtags as a sorted set (name -> score)
+	TrendingHashtagsKey = "trending:hashtags"
+
+	// TrendingTTL expires the list if the scheduler stops refreshing it
+	TrendingTTL = 2 * time.Hour
+)
+
+// HashtagScore is a hashtag name with its trending score.
+type HashtagScore struct {
+	Name  string
+	Score float64
+}
+
+// TrendingCache stores the periodically computed trending lists.
+type TrendingCache interface {
+	// SetTrendingHashtags atomically replaces the trending hashtag list.
+	SetTrendingHashtags(ctx context.Context, scores []HashtagScore) error
+
+	// GetTrendingHashtags returns the top hashtags, highest score first.
+	GetTrendingHashtags(ctx context.Context, limit int) ([]HashtagScore, error)
+}
+
+// RedisTrendingCache implements TrendingCache using a Redis Sorted Set.
+type RedisTrendingCache struct {
+	client *redis.Client
+}
+
+// NewTrendingCache creates a new TrendingCache backed by Redis.
+func NewTrendingCache(client *redis.Client) TrendingCache {
+	return &RedisTrendingCache{client: client}
+}
+
+// SetTrendingHashtags replaces the list in a MULTI so readers never see a partial list.
+func (c *RedisTrendingCache) SetTrendingHashtags(ctx context.Context, scores []HashtagScore) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, TrendingHashtagsKey)
+		if len(scores) == 0 {
+			return nil
+		}
+		members := make([]redis.Z, len(scores))
+		for i, s := range scores {
+			members[i] = redis.Z{Score: s.Score, Member: s.Name}
+		}
+		pipe.ZAdd(ctx, TrendingHashtagsKey, members...)
+		pipe.Expire(ctx, TrendingHashtagsKey, TrendingTTL)
+		return nil
+	})
+	if err != nil {
+		log.Printf("[TrendingCache] SetTrendingHashtags FAILED: count=%d err=%v", len(scores), err)
+		return fmt.Errorf("set trending hashtags: %w", err)
+	}
+
+	log.Printf("[TrendingCache] SetTrendingHashtags OK: count=%d", len(scores))
+	return nil
+}
+
+// GetTrendingHashtags returns the top hashtags by score.
+func (c *RedisTrendingCache) GetTrendingHashtags(ctx context.Context, limit int) ([]HashtagScore, error) {
+	results, err := c.client.ZRevRangeWithScores(ctx, TrendingHashtagsKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		log.Printf("[TrendingCache] GetTrendingHashtags FAILED: err=%v", err)
+		return nil, fmt.Errorf("get trending hashtags: %w", err)
+	}
+
+	scores := make([]HashtagScore, len(results))
+	for i, z := range results {
+		name, _ := z.Member.(string)
+		scores[i] = HashtagScore{Name: name, Score: z.Score}
+	}
+	return scores, nil
+}
@@ -32,6 +32,22 @@ type Config struct {
 	DefaultAvatarKey string
 
 	RedisURL string
+
+	FFmpegPath  string
+	FFprobePath string
+
+	CommentEditWindow int // Seconds after posting during which a comment can be edited (0 = no limit)
+
+	// Outgoing mail. Without SMTPHost, mail is only logged (and written to MailDir if set).
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+	MailDir      string
+
+	PasswordResetURL     string // Link in reset emails; the token is appended as ?token=
+	EmailVerificationURL string // Link in verification emails; the token is appended as ?token=
 }
 
 func LoadConfig() (*Config, error) {
@@ -69,6 +85,38 @@ func LoadConfig() (*Config, error) {
 		redisURL = "redis://localhost:6379"
 	}
 
+	ffmpegPath := os.Getenv("FFMPEG_PATH")
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	ffprobePath := os.Getenv("FFPROBE_PATH")
+	if ffprobePath == "" {
+		ffprobePath = "ffprobe"
+	}
+
+	commentEditWindow, err := strconv.Atoi(os.Getenv("COMMENT_EDIT_WINDOW"))
+	if err != nil || commentEditWindow < 0 {
+		commentEditWindow = 900
+	}
+
+	smtpPort := os.Getenv("SMTP_PORT")
+	if smtpPort == "" {
+		smtpPort = "587"
+	}
+	mailFrom := os.Getenv("MAIL_FROM")
+	if mailFrom == "" {
+		mailFrom = "Iamstagram <no-reply@iamstagram.local>"
+	}
+	passwordResetURL := os.Getenv("PASSWORD_RESET_URL")
+	if passwordResetURL == "" {
+		passwordResetURL = "iamstagram://reset-password"
+	}
+
+	emailVerificationURL := os.Getenv("EMAIL_VERIFICATION_URL")
+	if emailVerificationURL == "" {
+		emailVerificationURL = "iamstagram://verify-email"
+	}
+
 	return &Config{
 		DBHost:     os.Getenv("DB_HOST"),
 		DBPort:     os.Getenv("DB_PORT"),
@@ -93,5 +141,20 @@ func LoadConfig() (*Config, error) {
 		DefaultAvatarKey: defaultAvatarKey,
 
 		RedisURL: redisURL,
+
+		FFmpegPath:  ffmpegPath,
+		FFprobePath: ffprobePath,
+
+		CommentEditWindow: commentEditWindow,
+
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     smtpPort,
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		MailFrom:     mailFrom,
+		MailDir:      os.Getenv("MAIL_DIR"),
+
+		PasswordResetURL:     passwordResetURL,
+		EmailVerificationURL: emailVerificationURL,
 	}, nil
 }
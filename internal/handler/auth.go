@@ -16,19 +16,30 @@ import (
 
 // AuthHandler groups auth-related HTTP endpoints and their dependencies.
 type AuthHandler struct {
-	userService  *service.UserService
-	authService  *service.AuthService
-	mediaService *service.MediaService
-	config       *config.Config
+	userService              *service.UserService
+	authService              *service.AuthService
+	passwordResetService     *service.PasswordResetService
+	emailVerificationService *service.EmailVerificationService
+	mediaService             *service.MediaService
+	config                   *config.Config
 }
 
 // NewAuthHandler wires dependencies for authentication endpoints.
-func NewAuthHandler(userService *service.UserService, authService *service.AuthService, mediaService *service.MediaService, cfg *config.Config) *AuthHandler {
+func NewAuthHandler(
+	userService *service.UserService,
+	authService *service.AuthService,
+	passwordResetService *service.PasswordResetService,
+	emailVerificationService *service.EmailVerificationService,
+	mediaService *service.MediaService,
+	cfg *config.Config,
+) *AuthHandler {
 	return &AuthHandler{
-		userService:  userService,
-		authService:  authService,
-		mediaService: mediaService,
-		config:       cfg,
+		userService:              userService,
+		authService:              authService,
+		passwordResetService:     passwordResetService,
+		emailVerificationService: emailVerificationService,
+		mediaService:             mediaService,
+		config:                   cfg,
 	}
 }
 
@@ -52,6 +63,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	username := strings.TrimSpace(r.FormValue("username"))
 	password := r.FormValue("password")
 	displayName := strings.TrimSpace(r.FormValue("display_name"))
+	email := strings.TrimSpace(r.FormValue("email"))
 
 	if username == "" {
 		httputil.WriteBadRequest(w, "Username is required")
@@ -105,6 +117,9 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		AvatarURL:   avatarURL,
 		AvatarKey:   avatarKey,
 	}
+	if email != "" {
+		req.Email = &email
+	}
 
 	user, err := h.userService.Register(r.Context(), &req)
 	if err != nil {
@@ -112,11 +127,24 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 			httputil.WriteConflict(w, "Username already exists")
 			return
 		}
+		if writeUsernameError(w, err) {
+			return
+		}
+		if writeEmailError(w, err) {
+			return
+		}
 		log.Printf("[ERROR] Register - user creation: %v", err)
 		httputil.WriteInternalError(w, err.Error())
 		return
 	}
 
+	// The account exists either way; the user can ask for a new link later
+	if user.Email != nil {
+		if err := h.emailVerificationService.SendVerification(r.Context(), user.ID); err != nil {
+			log.Printf("[ERROR] Register - send verification: user=%d err=%v", user.ID, err)
+		}
+	}
+
 	httputil.WriteJSON(w, http.StatusCreated, user)
 }
 
@@ -131,7 +159,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	// Basic validation
 	if req.Username == "" {
-		httputil.WriteBadRequest(w, "Username is required")
+		httputil.WriteBadRequest(w, "Username or email is required")
 		return
 	}
 	if req.Password == "" {
@@ -143,7 +171,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	user, err := h.userService.Login(r.Context(), &req)
 	if err != nil {
 		if errors.Is(err, model.ErrInvalidCredentials) {
-			httputil.WriteUnauthorized(w, "Invalid username or password")
+			httputil.WriteUnauthorized(w, "Invalid username/email or password")
 			return
 		}
 		// TODO: Replace with proper logger (slog/zap) in production
@@ -274,6 +302,267 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ChangePassword handles password change for the current user
+// POST /me/password
+// All refresh tokens are revoked and a fresh pair is issued for this device,
+// so every other session has to log in again.
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Not authenticated")
+		return
+	}
+
+	var req model.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		httputil.WriteBadRequest(w, "Current password and new password are required")
+		return
+	}
+
+	err := h.userService.ChangePassword(r.Context(), userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if writePasswordPolicyError(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, model.ErrIncorrectPassword):
+			httputil.WriteForbidden(w, "Current password is incorrect")
+		case errors.Is(err, model.ErrPasswordUnchanged):
+			httputil.WriteBadRequest(w, "New password must be different from the current password")
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, "User not found")
+		default:
+			log.Printf("[ERROR] ChangePassword - update: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to change password")
+		}
+		return
+	}
+
+	// The password is already changed: failing here only means other sessions survive
+	// until their refresh token expires, so report it instead of hiding it
+	if err := h.authService.RevokeAllUserTokens(r.Context(), userID); err != nil {
+		log.Printf("[ERROR] ChangePassword - revoke tokens: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Password changed but failed to sign out other devices")
+		return
+	}
+
+	tokenPair, err := h.authService.GenerateTokenPair(r.Context(), userID, r.Header.Get("User-Agent"), h.getClientIP(r))
+	if err != nil {
+		log.Printf("[ERROR] ChangePassword - token generation: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Failed to generate tokens")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, tokenPair)
+}
+
+// DeleteAccount handles DELETE /me
+// Schedules the account for deletion after the grace period and signs out every session.
+// Logging in again before then cancels the deletion.
+func (h *AuthHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Not authenticated")
+		return
+	}
+
+	var req model.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if req.Password == "" {
+		httputil.WriteBadRequest(w, "Password is required")
+		return
+	}
+
+	scheduledAt, err := h.userService.ScheduleDeletion(r.Context(), userID, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrIncorrectPassword):
+			httputil.WriteForbidden(w, "Password is incorrect")
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, "User not found")
+		default:
+			log.Printf("[ERROR] DeleteAccount - schedule: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to delete account")
+		}
+		return
+	}
+
+	// Without this, a still-signed-in device would keep the account alive by refreshing
+	if err := h.authService.RevokeAllUserTokens(r.Context(), userID); err != nil {
+		log.Printf("[ERROR] DeleteAccount - revoke tokens: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Account deletion scheduled but failed to sign out devices")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, model.DeleteAccountResponse{
+		Message:             "Account scheduled for deletion. Log in before the deletion date to cancel.",
+		DeletionScheduledAt: scheduledAt,
+	})
+}
+
+// RequestPasswordReset handles "forgot password"
+// POST /auth/password-reset
+// Always answers the same way so it can't be used to find out which accounts exist.
+func (h *AuthHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req model.PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if strings.TrimSpace(req.Login) == "" {
+		httputil.WriteBadRequest(w, "Username or email is required")
+		return
+	}
+
+	if err := h.passwordResetService.RequestReset(r.Context(), req.Login); err != nil {
+		log.Printf("[ERROR] RequestPasswordReset: %v", err)
+		httputil.WriteInternalError(w, "Failed to request password reset")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "If the account has a verified email, a reset link has been sent",
+	})
+}
+
+// ConfirmPasswordReset sets a new password with a reset token
+// POST /auth/password-reset/confirm
+func (h *AuthHandler) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req model.PasswordResetConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if req.Token == "" || req.NewPassword == "" {
+		httputil.WriteBadRequest(w, "Token and new password are required")
+		return
+	}
+
+	err := h.passwordResetService.ConfirmReset(r.Context(), req.Token, req.NewPassword)
+	if err != nil {
+		if writePasswordPolicyError(w, err) {
+			return
+		}
+		if errors.Is(err, model.ErrResetTokenInvalid) {
+			httputil.WriteBadRequestWithCode(w, model.CodeResetTokenInvalid, "Reset link is invalid or has expired")
+			return
+		}
+		log.Printf("[ERROR] ConfirmPasswordReset: %v", err)
+		httputil.WriteInternalError(w, "Failed to reset password")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Password has been reset. Please log in with your new password",
+	})
+}
+
+// UpdateEmail sets, changes or removes the current user's email
+// PUT /me/email
+// A new address starts unverified and gets a verification link.
+func (h *AuthHandler) UpdateEmail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Not authenticated")
+		return
+	}
+
+	var req model.UpdateEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	user, err := h.emailVerificationService.UpdateEmail(r.Context(), userID, req.Email)
+	if err != nil {
+		if writeEmailError(w, err) {
+			return
+		}
+		if errors.Is(err, model.ErrUserNotFound) {
+			httputil.WriteNotFound(w, "User not found")
+			return
+		}
+		log.Printf("[ERROR] UpdateEmail: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Failed to update email")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, user)
+}
+
+// ResendVerification mails a new verification link for the current email
+// POST /me/email/verification
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Not authenticated")
+		return
+	}
+
+	err := h.emailVerificationService.SendVerification(r.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrEmailNotSet):
+			httputil.WriteBadRequest(w, "No email on this account")
+		case errors.Is(err, model.ErrEmailAlreadyVerified):
+			httputil.WriteConflict(w, "Email is already verified")
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, "User not found")
+		default:
+			log.Printf("[ERROR] ResendVerification: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to send verification email")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Verification email sent",
+	})
+}
+
+// VerifyEmail confirms an email with the token from the verification link
+// POST /auth/verify-email
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req model.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if req.Token == "" {
+		httputil.WriteBadRequest(w, "Token is required")
+		return
+	}
+
+	if err := h.emailVerificationService.Verify(r.Context(), req.Token); err != nil {
+		if errors.Is(err, model.ErrVerificationTokenInvalid) {
+			httputil.WriteBadRequestWithCode(w, model.CodeVerificationTokenInvalid, "Verification link is invalid or has expired")
+			return
+		}
+		if writeEmailError(w, err) {
+			return
+		}
+		log.Printf("[ERROR] VerifyEmail: %v", err)
+		httputil.WriteInternalError(w, "Failed to verify email")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Email verified",
+	})
+}
+
 // LogoutAll handles logout from all devices
 // POST /auth/logout-all
 func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
@@ -322,3 +611,33 @@ func (h *AuthHandler) getClientIP(r *http.Request) string {
 	}
 	return addr
 }
+
+// writePasswordPolicyError writes the response for passwords rejected by the policy.
+// Returns false if err is not a policy error.
+func writePasswordPolicyError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, model.ErrPasswordTooShort):
+		httputil.WriteBadRequestWithCode(w, model.CodeWeakPassword, "Password must be at least 8 characters")
+	case errors.Is(err, model.ErrPasswordTooLong):
+		httputil.WriteBadRequestWithCode(w, model.CodeWeakPassword, "Password must be at most 72 bytes")
+	case errors.Is(err, model.ErrPasswordTooCommon):
+		httputil.WriteBadRequestWithCode(w, model.CodeWeakPassword, "Password is too common, choose a less guessable one")
+	default:
+		return false
+	}
+	return true
+}
+
+// writeEmailError writes the response for invalid or taken email addresses.
+// Returns false if err is neither.
+func writeEmailError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, model.ErrInvalidEmail):
+		httputil.WriteBadRequest(w, "Invalid email address")
+	case errors.Is(err, model.ErrEmailExists):
+		httputil.WriteConflict(w, "Email is already used by another account")
+	default:
+		return false
+	}
+	return true
+}
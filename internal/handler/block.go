@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"iamstagram_22520060/internal/httputil"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/service"
+	"iamstagram_22520060/internal/transport/http/middleware"
+)
+
+type BlockHandler struct {
+	blockService *service.BlockService
+}
+
+func NewBlockHandler(blockService *service.BlockService) *BlockHandler {
+	return &BlockHandler{
+		blockService: blockService,
+	}
+}
+
+// Block handles POST /users/:id/block
+// Also removes follows and follow requests between the two users in both directions.
+func (h *BlockHandler) Block(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	blockedID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.blockService.Block(r.Context(), userID, blockedID); err != nil {
+		switch {
+		case errors.Is(err, model.ErrCannotBlockSelf):
+			httputil.WriteBadRequest(w, err.Error())
+		case errors.Is(err, model.ErrAlreadyBlocked):
+			httputil.WriteConflict(w, err.Error())
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, err.Error())
+		default:
+			log.Printf("[ERROR] Block handler: user=%d blocked=%d err=%v", userID, blockedID, err)
+			httputil.WriteInternalError(w, "Failed to block user")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Successfully blocked user",
+	})
+}
+
+// Unblock handles DELETE /users/:id/block
+func (h *BlockHandler) Unblock(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	blockedID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.blockService.Unblock(r.Context(), userID, blockedID); err != nil {
+		switch {
+		case errors.Is(err, model.ErrNotBlocked):
+			httputil.WriteNotFound(w, err.Error())
+		default:
+			log.Printf("[ERROR] Unblock handler: user=%d blocked=%d err=%v", userID, blockedID, err)
+			httputil.WriteInternalError(w, "Failed to unblock user")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Successfully unblocked user",
+	})
+}
+
+// GetBlocked handles GET /me/blocks
+// Query params: cursor (RFC3339), limit (default 20, max 100)
+func (h *BlockHandler) GetBlocked(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	cursorStr := r.URL.Query().Get("cursor")
+	var cursor *time.Time
+	if cursorStr != "" {
+		parsed, err := time.Parse(time.RFC3339, cursorStr)
+		if err != nil {
+			httputil.WriteBadRequest(w, "Invalid cursor format")
+			return
+		}
+		cursor = &parsed
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 20
+	if limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit < 1 || parsedLimit > 100 {
+			httputil.WriteBadRequest(w, "Limit must be between 1 and 100")
+			return
+		}
+		limit = parsedLimit
+	}
+
+	result, err := h.blockService.GetBlocked(r.Context(), userID, cursor, limit)
+	if err != nil {
+		log.Printf("[ERROR] GetBlocked handler: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Failed to fetch blocked users")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, result)
+}
@@ -52,6 +52,8 @@ func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, model.ErrPostNotFound):
 			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrCommentsDisabled):
+			httputil.WriteForbiddenWithCode(w, model.CodeCommentsDisabled, "Comments are turned off for this post")
 		case errors.Is(err, model.ErrCommentNotFound):
 			httputil.WriteNotFound(w, "Parent comment not found")
 		case errors.Is(err, model.ErrContentRequired):
@@ -132,6 +134,8 @@ func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
 			httputil.WriteNotFound(w, "Comment not found")
 		case errors.Is(err, model.ErrNotCommentOwner):
 			httputil.WriteForbidden(w, "You can only edit your own comments")
+		case errors.Is(err, model.ErrEditWindowExpired):
+			httputil.WriteForbiddenWithCode(w, model.CodeEditWindowExpired, "Comment can no longer be edited")
 		case errors.Is(err, model.ErrContentRequired):
 			httputil.WriteBadRequest(w, "Comment content is required")
 		case errors.Is(err, model.ErrContentTooLong):
@@ -146,8 +150,111 @@ func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteJSON(w, http.StatusOK, comment)
 }
 
+// EditHistory handles GET /posts/:id/comments/:commentId/edits
+// Returns the previous versions of a comment (post author only).
+func (h *CommentHandler) EditHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, commentID, ok := parsePostCommentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.commentService.GetEditHistory(r.Context(), postID, commentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrCommentNotFound):
+			httputil.WriteNotFound(w, "Comment not found")
+		case errors.Is(err, model.ErrNotPostOwner):
+			httputil.WriteForbidden(w, "Only the post author can view edit history")
+		default:
+			log.Printf("[ERROR] Comment edit history handler: user=%d comment=%d err=%v", userID, commentID, err)
+			httputil.WriteInternalError(w, "Failed to get edit history")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, history)
+}
+
+// Like handles POST /posts/:id/comments/:commentId/likes
+// Likes a comment for the authenticated user.
+func (h *CommentHandler) Like(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, commentID, ok := parsePostCommentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.Like(r.Context(), postID, commentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrCommentNotFound):
+			httputil.WriteNotFound(w, "Comment not found")
+		case errors.Is(err, model.ErrCommentAlreadyLiked):
+			httputil.WriteConflict(w, "Already liked this comment")
+		default:
+			log.Printf("[ERROR] Like comment handler: user=%d comment=%d err=%v", userID, commentID, err)
+			httputil.WriteInternalError(w, "Failed to like comment")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, map[string]string{
+		"message": "Comment liked successfully",
+	})
+}
+
+// Unlike handles DELETE /posts/:id/comments/:commentId/likes
+// Removes the authenticated user's like from a comment.
+func (h *CommentHandler) Unlike(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, commentID, ok := parsePostCommentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.Unlike(r.Context(), postID, commentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrCommentNotFound):
+			httputil.WriteNotFound(w, "Comment not found")
+		case errors.Is(err, model.ErrCommentNotLiked):
+			httputil.WriteNotFound(w, "Have not liked this comment")
+		default:
+			log.Printf("[ERROR] Unlike comment handler: user=%d comment=%d err=%v", userID, commentID, err)
+			httputil.WriteInternalError(w, "Failed to unlike comment")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Comment unliked successfully",
+	})
+}
+
 // List handles GET /posts/:id/comments
-// Returns paginated comments for a post.
+// Returns paginated top-level comments for a post, each with its first replies.
 func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
 	postIDStr := chi.URLParam(r, "id")
 	postID, err := strconv.ParseInt(postIDStr, 10, 64)
@@ -172,7 +279,12 @@ func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
 		limit = parsed
 	}
 
-	comments, err := h.commentService.GetByPostID(r.Context(), postID, cursor, limit)
+	var viewerID *int64
+	if userID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		viewerID = &userID
+	}
+
+	comments, err := h.commentService.GetByPostID(r.Context(), postID, viewerID, cursor, limit)
 	if err != nil {
 		if errors.Is(err, model.ErrPostNotFound) {
 			httputil.WriteNotFound(w, "Post not found")
@@ -185,3 +297,257 @@ func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	httputil.WriteJSON(w, http.StatusOK, comments)
 }
+
+// Pin handles POST /posts/:id/comments/:commentId/pin
+// Pins a comment on the user's own post, replacing any previous pin.
+func (h *CommentHandler) Pin(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, commentID, ok := parsePostCommentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.Pin(r.Context(), postID, commentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrCommentNotFound):
+			httputil.WriteNotFound(w, "Comment not found")
+		case errors.Is(err, model.ErrNotPostOwner):
+			httputil.WriteForbidden(w, "Only the post author can pin comments")
+		case errors.Is(err, model.ErrCannotPinReply):
+			httputil.WriteBadRequest(w, "Only top-level comments can be pinned")
+		default:
+			log.Printf("[ERROR] Pin comment handler: user=%d comment=%d err=%v", userID, commentID, err)
+			httputil.WriteInternalError(w, "Failed to pin comment")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Comment pinned successfully",
+	})
+}
+
+// Unpin handles DELETE /posts/:id/comments/:commentId/pin
+func (h *CommentHandler) Unpin(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, commentID, ok := parsePostCommentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.Unpin(r.Context(), postID, commentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrCommentNotPinned):
+			httputil.WriteNotFound(w, "Comment is not pinned")
+		case errors.Is(err, model.ErrNotPostOwner):
+			httputil.WriteForbidden(w, "Only the post author can unpin comments")
+		default:
+			log.Printf("[ERROR] Unpin comment handler: user=%d comment=%d err=%v", userID, commentID, err)
+			httputil.WriteInternalError(w, "Failed to unpin comment")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Comment unpinned successfully",
+	})
+}
+
+// ListReplies handles GET /posts/:id/comments/:commentId/replies
+// Returns paginated replies of a comment, oldest first.
+func (h *CommentHandler) ListReplies(w http.ResponseWriter, r *http.Request) {
+	postID, commentID, ok := parsePostCommentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var cursor *string
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		cursor = &c
+	}
+
+	limit, ok := parseLimit(w, r, 10)
+	if !ok {
+		return
+	}
+
+	var viewerID *int64
+	if userID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		viewerID = &userID
+	}
+
+	replies, err := h.commentService.GetReplies(r.Context(), postID, commentID, viewerID, cursor, limit)
+	if err != nil {
+		if errors.Is(err, model.ErrPostNotFound) {
+			httputil.WriteNotFound(w, "Post not found")
+			return
+		}
+		if errors.Is(err, model.ErrCommentNotFound) {
+			httputil.WriteNotFound(w, "Comment not found")
+			return
+		}
+		log.Printf("[ERROR] List replies handler: post=%d comment=%d err=%v", postID, commentID, err)
+		httputil.WriteInternalError(w, "Failed to get replies")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, replies)
+}
+
+// ListHidden handles GET /posts/:id/comments/hidden
+// Returns the hidden comments on the user's own post for review.
+func (h *CommentHandler) ListHidden(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return
+	}
+
+	var cursor *string
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		cursor = &c
+	}
+
+	limit, ok := parseLimit(w, r, 10)
+	if !ok {
+		return
+	}
+
+	comments, err := h.commentService.ListHidden(r.Context(), postID, userID, cursor, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrNotPostOwner):
+			httputil.WriteForbidden(w, "Only the post author can review hidden comments")
+		default:
+			log.Printf("[ERROR] List hidden comments handler: user=%d post=%d err=%v", userID, postID, err)
+			httputil.WriteInternalError(w, "Failed to get hidden comments")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, comments)
+}
+
+// Unhide handles POST /posts/:id/comments/:commentId/unhide
+// Makes a hidden comment on the user's own post visible.
+func (h *CommentHandler) Unhide(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, commentID, ok := parsePostCommentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.commentService.Unhide(r.Context(), postID, commentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrCommentNotHidden):
+			httputil.WriteNotFound(w, "Comment is not hidden")
+		case errors.Is(err, model.ErrNotPostOwner):
+			httputil.WriteForbidden(w, "Only the post author can unhide comments")
+		default:
+			log.Printf("[ERROR] Unhide comment handler: user=%d comment=%d err=%v", userID, commentID, err)
+			httputil.WriteInternalError(w, "Failed to unhide comment")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Comment unhidden successfully",
+	})
+}
+
+// GetFilters handles GET /me/comment-filters
+func (h *CommentHandler) GetFilters(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	filters, err := h.commentService.GetFilters(r.Context(), userID)
+	if err != nil {
+		log.Printf("[ERROR] Get comment filters handler: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Failed to get comment filters")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, filters)
+}
+
+// UpdateFilters handles PUT /me/comment-filters
+// Replaces the hidden word list and the offensive-terms option.
+func (h *CommentHandler) UpdateFilters(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	var req model.UpdateCommentFiltersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	filters, err := h.commentService.UpdateFilters(r.Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrTooManyHiddenWords):
+			httputil.WriteBadRequest(w, "Too many hidden words (max 100)")
+		case errors.Is(err, model.ErrHiddenWordTooLong):
+			httputil.WriteBadRequest(w, "Hidden word too long (max 50 characters)")
+		default:
+			log.Printf("[ERROR] Update comment filters handler: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to update comment filters")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, filters)
+}
+
+// parsePostCommentIDs reads the :id and :commentId URL params.
+func parsePostCommentIDs(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	postID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return 0, 0, false
+	}
+	commentID, err := strconv.ParseInt(chi.URLParam(r, "commentId"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid comment ID")
+		return 0, 0, false
+	}
+	return postID, commentID, true
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"iamstagram_22520060/internal/httputil"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/service"
+	"iamstagram_22520060/internal/transport/http/middleware"
+)
+
+type ExportHandler struct {
+	exportService *service.ExportService
+}
+
+func NewExportHandler(exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// RequestExport handles POST /me/export
+// Queues a ZIP of the user's data; the user gets a notification when it's ready.
+func (h *ExportHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	export, err := h.exportService.RequestExport(r.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrExportInProgress):
+			httputil.WriteConflict(w, "A data export is already being prepared")
+		default:
+			log.Printf("[ERROR] Request export handler: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to request data export")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusAccepted, export)
+}
+
+// GetExport handles GET /me/export
+// Returns the latest export; download_url is set once it's ready.
+func (h *ExportHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	export, err := h.exportService.GetLatest(r.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrExportNotFound):
+			httputil.WriteNotFound(w, "No data export requested")
+		default:
+			log.Printf("[ERROR] Get export handler: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to get data export")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, export)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
@@ -39,11 +40,12 @@ func (h *FollowHandler) Follow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.followService.Follow(r.Context(), followerID, followeeID); err != nil {
+	status, err := h.followService.Follow(r.Context(), followerID, followeeID)
+	if err != nil {
 		switch {
 		case errors.Is(err, model.ErrCannotFollowSelf):
 			httputil.WriteBadRequest(w, err.Error())
-		case errors.Is(err, model.ErrAlreadyFollowing):
+		case errors.Is(err, model.ErrAlreadyFollowing), errors.Is(err, model.ErrFollowRequestExists):
 			httputil.WriteConflict(w, err.Error())
 		case errors.Is(err, model.ErrUserNotFound):
 			httputil.WriteNotFound(w, err.Error())
@@ -55,8 +57,14 @@ func (h *FollowHandler) Follow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	message := "Successfully followed user"
+	if status == model.FollowStatusRequested {
+		message = "Follow request sent"
+	}
+
 	httputil.WriteJSON(w, http.StatusOK, map[string]string{
-		"message": "Successfully followed user",
+		"message": message,
+		"status":  string(status),
 	})
 }
 
@@ -128,9 +136,16 @@ func (h *FollowHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.followService.GetFollowers(r.Context(), userID, cursor, limit, viewerID)
 	if err != nil {
-		// TODO: Replace with proper logger (slog/zap) in production
-		log.Printf("[ERROR] GetFollowers handler: %v", err)
-		httputil.WriteInternalError(w, "Failed to fetch followers")
+		switch {
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, err.Error())
+		case errors.Is(err, model.ErrPrivateAccount):
+			httputil.WriteForbidden(w, "This account is private")
+		default:
+			// TODO: Replace with proper logger (slog/zap) in production
+			log.Printf("[ERROR] GetFollowers handler: %v", err)
+			httputil.WriteInternalError(w, "Failed to fetch followers")
+		}
 		return
 	}
 
@@ -174,11 +189,156 @@ func (h *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.followService.GetFollowing(r.Context(), userID, cursor, limit, viewerID)
 	if err != nil {
-		// TODO: Replace with proper logger (slog/zap) in production
-		log.Printf("[ERROR] GetFollowing handler: %v", err)
-		httputil.WriteInternalError(w, "Failed to fetch following")
+		switch {
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, err.Error())
+		case errors.Is(err, model.ErrPrivateAccount):
+			httputil.WriteForbidden(w, "This account is private")
+		default:
+			// TODO: Replace with proper logger (slog/zap) in production
+			log.Printf("[ERROR] GetFollowing handler: %v", err)
+			httputil.WriteInternalError(w, "Failed to fetch following")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, result)
+}
+
+// UpdatePrivacy handles PUT /me/privacy
+// Switches the account between public and private. Going public approves all pending requests.
+func (h *FollowHandler) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	var req model.UpdatePrivacyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+	if req.IsPrivate == nil {
+		httputil.WriteBadRequest(w, "is_private is required")
+		return
+	}
+
+	if err := h.followService.SetPrivate(r.Context(), userID, *req.IsPrivate); err != nil {
+		switch {
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, err.Error())
+		default:
+			log.Printf("[ERROR] UpdatePrivacy handler: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to update privacy")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]bool{
+		"is_private": *req.IsPrivate,
+	})
+}
+
+// GetRequests handles GET /me/follow-requests
+// Returns users waiting for approval, newest first.
+func (h *FollowHandler) GetRequests(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	cursorStr := r.URL.Query().Get("cursor")
+	var cursor *time.Time
+	if cursorStr != "" {
+		parsed, err := time.Parse(time.RFC3339, cursorStr)
+		if err != nil {
+			httputil.WriteBadRequest(w, "Invalid cursor format")
+			return
+		}
+		cursor = &parsed
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 20
+	if limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit < 1 || parsedLimit > 100 {
+			httputil.WriteBadRequest(w, "Limit must be between 1 and 100")
+			return
+		}
+		limit = parsedLimit
+	}
+
+	result, err := h.followService.GetRequests(r.Context(), userID, cursor, limit)
+	if err != nil {
+		log.Printf("[ERROR] GetRequests handler: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Failed to fetch follow requests")
 		return
 	}
 
 	httputil.WriteJSON(w, http.StatusOK, result)
 }
+
+// ApproveRequest handles POST /me/follow-requests/:id/approve
+// :id is the requester. They become a follower and get the usual feed backfill.
+func (h *FollowHandler) ApproveRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	requesterID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.followService.ApproveRequest(r.Context(), userID, requesterID); err != nil {
+		switch {
+		case errors.Is(err, model.ErrFollowRequestNotFound):
+			httputil.WriteNotFound(w, err.Error())
+		default:
+			log.Printf("[ERROR] ApproveRequest handler: user=%d requester=%d err=%v", userID, requesterID, err)
+			httputil.WriteInternalError(w, "Failed to approve follow request")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Follow request approved",
+	})
+}
+
+// DeclineRequest handles DELETE /me/follow-requests/:id
+// :id is the requester.
+func (h *FollowHandler) DeclineRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	requesterID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.followService.DeclineRequest(r.Context(), userID, requesterID); err != nil {
+		switch {
+		case errors.Is(err, model.ErrFollowRequestNotFound):
+			httputil.WriteNotFound(w, err.Error())
+		default:
+			log.Printf("[ERROR] DeclineRequest handler: user=%d requester=%d err=%v", userID, requesterID, err)
+			httputil.WriteInternalError(w, "Failed to decline follow request")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Follow request declined",
+	})
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"iamstagram_22520060/internal/httputil"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/service"
+	"iamstagram_22520060/internal/transport/http/middleware"
+)
+
+type HashtagHandler struct {
+	hashtagService *service.HashtagService
+}
+
+func NewHashtagHandler(hashtagService *service.HashtagService) *HashtagHandler {
+	return &HashtagHandler{
+		hashtagService: hashtagService,
+	}
+}
+
+// GetPosts handles GET /hashtags/:tag/posts
+// Returns the hashtag with a page of its posts, newest first.
+func (h *HashtagHandler) GetPosts(w http.ResponseWriter, r *http.Request) {
+	tag := chi.URLParam(r, "tag")
+
+	var cursor *string
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		cursor = &c
+	}
+
+	limit, ok := parseLimit(w, r, 12)
+	if !ok {
+		return
+	}
+
+	var viewerID *int64
+	if id, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		viewerID = &id
+	}
+
+	resp, err := h.hashtagService.GetPosts(r.Context(), tag, viewerID, cursor, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrHashtagNotFound):
+			httputil.WriteNotFound(w, "Hashtag not found")
+		default:
+			log.Printf("[ERROR] Get hashtag posts handler: tag=%s err=%v", tag, err)
+			httputil.WriteInternalError(w, "Failed to get hashtag posts")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// Search handles GET /hashtags/search?q=
+// Returns hashtags starting with the query.
+func (h *HashtagHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		httputil.WriteBadRequest(w, "Query parameter 'q' is required")
+		return
+	}
+
+	limit, ok := parseLimit(w, r, 20)
+	if !ok {
+		return
+	}
+
+	resp, err := h.hashtagService.Search(r.Context(), query, limit)
+	if err != nil {
+		log.Printf("[ERROR] Search hashtags handler: q=%s err=%v", query, err)
+		httputil.WriteInternalError(w, "Failed to search hashtags")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetTrending handles GET /hashtags/trending
+// Returns hashtags whose usage is spiking compared to their usual volume.
+func (h *HashtagHandler) GetTrending(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(w, r, 10)
+	if !ok {
+		return
+	}
+
+	resp, err := h.hashtagService.GetTrending(r.Context(), limit)
+	if err != nil {
+		log.Printf("[ERROR] Get trending hashtags handler: %v", err)
+		httputil.WriteInternalError(w, "Failed to get trending hashtags")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// parseLimit reads the optional "limit" query parameter.
+// Writes a 400 and returns false if it is not a positive integer.
+func parseLimit(w http.ResponseWriter, r *http.Request, defaultLimit int) (int, bool) {
+	l := r.URL.Query().Get("limit")
+	if l == "" {
+		return defaultLimit, true
+	}
+	parsed, err := strconv.Atoi(l)
+	if err != nil || parsed <= 0 {
+		httputil.WriteBadRequest(w, "Invalid limit parameter")
+		return 0, false
+	}
+	return parsed, true
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"iamstagram_22520060/internal/httputil"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/service"
+	"iamstagram_22520060/internal/transport/http/middleware"
+)
+
+type InsightsHandler struct {
+	insightsService *service.InsightsService
+}
+
+func NewInsightsHandler(insightsService *service.InsightsService) *InsightsHandler {
+	return &InsightsHandler{
+		insightsService: insightsService,
+	}
+}
+
+// Ingest handles POST /posts/impressions
+// Accepts a batch of impression / profile visit events from the client.
+func (h *InsightsHandler) Ingest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	var req model.IngestInsightsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	err := h.insightsService.Ingest(r.Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrNoInsightEvents):
+			httputil.WriteBadRequest(w, "At least one event is required")
+		case errors.Is(err, model.ErrTooManyInsightEvents):
+			httputil.WriteBadRequest(w, "Too many events (max 100 per request)")
+		case errors.Is(err, model.ErrInvalidInsightEvent):
+			httputil.WriteBadRequest(w, "Each event needs a post_id and a type of impression or profile_visit")
+		default:
+			log.Printf("[ERROR] Ingest impressions handler: user=%d events=%d err=%v", userID, len(req.Events), err)
+			httputil.WriteInternalError(w, "Failed to record impressions")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusAccepted, map[string]string{
+		"message": "Events recorded",
+	})
+}
+
+// GetPostInsights handles GET /posts/:id/insights
+// Only the post author can see insights.
+func (h *InsightsHandler) GetPostInsights(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return
+	}
+
+	insights, err := h.insightsService.GetPostInsights(r.Context(), postID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrNotPostOwner):
+			httputil.WriteForbidden(w, "Only the author can see post insights")
+		default:
+			log.Printf("[ERROR] Get post insights handler: user=%d post=%d err=%v", userID, postID, err)
+			httputil.WriteInternalError(w, "Failed to get post insights")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, insights)
+}
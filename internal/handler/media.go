@@ -24,7 +24,7 @@ func NewMediaHandler(mediaService *service.MediaService) *MediaHandler {
 // PresignPostUpload handles POST /media/posts/presign
 // Returns a presigned URL for uploading post media directly to R2.
 func (h *MediaHandler) PresignPostUpload(w http.ResponseWriter, r *http.Request) {
-	_, ok := middleware.GetUserIDFromContext(r.Context())
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
 		httputil.WriteUnauthorized(w, "Authentication required")
 		return
@@ -42,16 +42,16 @@ func (h *MediaHandler) PresignPostUpload(w http.ResponseWriter, r *http.Request)
 		httputil.WriteBadRequest(w, "content_type is required")
 		return
 	}
-	if req.FileSize > 0 && req.FileSize > model.MaxPostMediaSize {
-		httputil.WriteBadRequestWithCode(w, model.CodeFileTooLarge, "Media exceeds 10MB limit")
+	if req.FileSize > 0 && req.FileSize > model.MaxPostMediaSizeFor(req.ContentType) {
+		httputil.WriteBadRequestWithCode(w, model.CodeFileTooLarge, "Media exceeds size limit (10MB images, 100MB videos)")
 		return
 	}
 
-	res, err := h.mediaService.PresignPostUpload(r.Context(), req.ContentType)
+	res, err := h.mediaService.PresignPostUpload(r.Context(), userID, req.ContentType)
 	if err != nil {
 		switch {
-		case errors.Is(err, model.ErrInvalidImageType):
-			httputil.WriteBadRequestWithCode(w, model.CodeInvalidImageType, "Unsupported image type. Allowed: jpeg, png, gif, webp")
+		case errors.Is(err, model.ErrInvalidMediaType):
+			httputil.WriteBadRequestWithCode(w, model.CodeInvalidMediaType, "Unsupported media type. Allowed: jpeg, png, gif, webp, mp4, mov")
 		default:
 			httputil.WriteInternalError(w, "Failed to create upload URL")
 		}
@@ -64,7 +64,7 @@ func (h *MediaHandler) PresignPostUpload(w http.ResponseWriter, r *http.Request)
 // PresignPostUploadBatch handles POST /media/posts/presign/batch
 // Returns presigned URLs for uploading multiple post media items directly to R2.
 func (h *MediaHandler) PresignPostUploadBatch(w http.ResponseWriter, r *http.Request) {
-	_, ok := middleware.GetUserIDFromContext(r.Context())
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
 		httputil.WriteUnauthorized(w, "Authentication required")
 		return
@@ -94,16 +94,16 @@ func (h *MediaHandler) PresignPostUploadBatch(w http.ResponseWriter, r *http.Req
 			httputil.WriteBadRequest(w, fmt.Sprintf("items[%d].content_type is required", i))
 			return
 		}
-		if item.FileSize > 0 && item.FileSize > model.MaxPostMediaSize {
-			httputil.WriteBadRequestWithCode(w, model.CodeFileTooLarge, fmt.Sprintf("items[%d] exceeds 10MB limit", i))
+		if item.FileSize > 0 && item.FileSize > model.MaxPostMediaSizeFor(item.ContentType) {
+			httputil.WriteBadRequestWithCode(w, model.CodeFileTooLarge, fmt.Sprintf("items[%d] exceeds size limit (10MB images, 100MB videos)", i))
 			return
 		}
 
-		res, err := h.mediaService.PresignPostUpload(r.Context(), item.ContentType)
+		res, err := h.mediaService.PresignPostUpload(r.Context(), userID, item.ContentType)
 		if err != nil {
 			switch {
-			case errors.Is(err, model.ErrInvalidImageType):
-				httputil.WriteBadRequestWithCode(w, model.CodeInvalidImageType, fmt.Sprintf("items[%d] unsupported image type. Allowed: jpeg, png, gif, webp", i))
+			case errors.Is(err, model.ErrInvalidMediaType):
+				httputil.WriteBadRequestWithCode(w, model.CodeInvalidMediaType, fmt.Sprintf("items[%d] unsupported media type. Allowed: jpeg, png, gif, webp, mp4, mov", i))
 			default:
 				httputil.WriteInternalError(w, "Failed to create upload URL")
 			}
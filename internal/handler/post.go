@@ -49,6 +49,28 @@ func (h *PostHandler) Create(w http.ResponseWriter, r *http.Request) {
 			httputil.WriteBadRequest(w, "Too many media items (max 10)")
 		case errors.Is(err, model.ErrCaptionTooLong):
 			httputil.WriteBadRequest(w, "Caption too long (max 2200 characters)")
+		case errors.Is(err, model.ErrTooManyHashtags):
+			httputil.WriteBadRequest(w, "Too many hashtags (max 30)")
+		case errors.Is(err, model.ErrAltTextTooLong):
+			httputil.WriteBadRequest(w, "Alt text too long (max 1000 characters)")
+		case errors.Is(err, model.ErrTooManyTags):
+			httputil.WriteBadRequest(w, "Too many tags (max 20 per media)")
+		case errors.Is(err, model.ErrInvalidTagPoint):
+			httputil.WriteBadRequest(w, "Tag coordinates must be between 0 and 1")
+		case errors.Is(err, model.ErrDuplicateTag):
+			httputil.WriteBadRequest(w, "A user can only be tagged once per media")
+		case errors.Is(err, model.ErrTaggedUserMissing):
+			httputil.WriteBadRequest(w, "Tagged user not found")
+		case errors.Is(err, model.ErrInvalidMediaURL):
+			httputil.WriteBadRequestWithCode(w, model.CodeInvalidMediaURL, "Media URLs must come from POST /media/posts/presign")
+		case errors.Is(err, model.ErrMediaNotUploaded):
+			httputil.WriteBadRequestWithCode(w, model.CodeMediaNotUploaded, "Media has not been uploaded")
+		case errors.Is(err, model.ErrMediaAlreadyUsed):
+			httputil.WriteBadRequestWithCode(w, model.CodeMediaAlreadyUsed, "Media is already used by another post")
+		case errors.Is(err, model.ErrInvalidMediaType):
+			httputil.WriteBadRequestWithCode(w, model.CodeInvalidMediaType, "Unsupported media type. Allowed: jpeg, png, gif, webp, mp4, mov")
+		case errors.Is(err, model.ErrFileTooLarge):
+			httputil.WriteBadRequestWithCode(w, model.CodeFileTooLarge, "Media exceeds size limit (10MB images, 100MB videos)")
 		default:
 			log.Printf("[ERROR] Create post handler: user=%d err=%v", userID, err)
 			httputil.WriteInternalError(w, "Failed to create post")
@@ -89,6 +111,48 @@ func (h *PostHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteJSON(w, http.StatusOK, post)
 }
 
+// Update handles PATCH /posts/:id
+// Edits a post's caption (owner only).
+func (h *PostHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return
+	}
+
+	var req model.UpdatePostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	post, err := h.postService.Update(r.Context(), postID, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrNotPostOwner):
+			httputil.WriteForbidden(w, "You can only edit your own posts")
+		case errors.Is(err, model.ErrCaptionTooLong):
+			httputil.WriteBadRequest(w, "Caption too long (max 2200 characters)")
+		case errors.Is(err, model.ErrTooManyHashtags):
+			httputil.WriteBadRequest(w, "Too many hashtags (max 30)")
+		default:
+			log.Printf("[ERROR] Update post handler: user=%d post=%d err=%v", userID, postID, err)
+			httputil.WriteInternalError(w, "Failed to update post")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, post)
+}
+
 // Delete handles DELETE /posts/:id
 // Soft-deletes a post (only owner can delete).
 func (h *PostHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -150,10 +214,22 @@ func (h *PostHandler) GetUserPosts(w http.ResponseWriter, r *http.Request) {
 		limit = parsed
 	}
 
-	posts, err := h.postService.GetUserPosts(r.Context(), userID, cursor, limit)
+	var viewerID *int64
+	if id, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		viewerID = &id
+	}
+
+	posts, err := h.postService.GetUserPosts(r.Context(), userID, viewerID, cursor, limit)
 	if err != nil {
-		log.Printf("[ERROR] Get user posts handler: user=%d err=%v", userID, err)
-		httputil.WriteInternalError(w, "Failed to get user posts")
+		switch {
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, "User not found")
+		case errors.Is(err, model.ErrPrivateAccount):
+			httputil.WriteForbidden(w, "This account is private")
+		default:
+			log.Printf("[ERROR] Get user posts handler: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to get user posts")
+		}
 		return
 	}
 
@@ -230,9 +306,88 @@ func (h *PostHandler) Unlike(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Repost handles POST /posts/:id/repost
+// Shares the post with the current user's followers.
+func (h *PostHandler) Repost(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postIDStr := chi.URLParam(r, "id")
+	postID, err := strconv.ParseInt(postIDStr, 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return
+	}
+
+	err = h.postService.Repost(r.Context(), postID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrCannotRepostOwn):
+			httputil.WriteForbidden(w, "Cannot repost your own post")
+		case errors.Is(err, model.ErrCannotRepostPrivate):
+			httputil.WriteForbidden(w, "Posts from private accounts can't be reposted")
+		case errors.Is(err, model.ErrAlreadyReposted):
+			httputil.WriteConflict(w, "Already reposted this post")
+		default:
+			log.Printf("[ERROR] Repost handler: user=%d post=%d err=%v", userID, postID, err)
+			httputil.WriteInternalError(w, "Failed to repost")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, map[string]string{
+		"message": "Post reposted successfully",
+	})
+}
+
+// Unrepost handles DELETE /posts/:id/repost
+func (h *PostHandler) Unrepost(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postIDStr := chi.URLParam(r, "id")
+	postID, err := strconv.ParseInt(postIDStr, 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return
+	}
+
+	err = h.postService.Unrepost(r.Context(), postID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrNotReposted):
+			httputil.WriteNotFound(w, "Have not reposted this post")
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		default:
+			log.Printf("[ERROR] Unrepost handler: user=%d post=%d err=%v", userID, postID, err)
+			httputil.WriteInternalError(w, "Failed to undo repost")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Repost removed successfully",
+	})
+}
+
 // GetLikes handles GET /posts/:id/likes
 // Returns paginated list of users who liked a post.
 func (h *PostHandler) GetLikes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
 	postIDStr := chi.URLParam(r, "id")
 	postID, err := strconv.ParseInt(postIDStr, 10, 64)
 	if err != nil {
@@ -256,7 +411,7 @@ func (h *PostHandler) GetLikes(w http.ResponseWriter, r *http.Request) {
 		limit = parsed
 	}
 
-	likers, err := h.postService.GetPostLikers(r.Context(), postID, cursor, limit)
+	likers, err := h.postService.GetPostLikers(r.Context(), postID, userID, cursor, limit)
 	if err != nil {
 		if errors.Is(err, model.ErrPostNotFound) {
 			httputil.WriteNotFound(w, "Post not found")
@@ -270,3 +425,135 @@ func (h *PostHandler) GetLikes(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteJSON(w, http.StatusOK, likers)
 }
 
+// UpdateMediaTags handles PUT /posts/:id/media/:mediaId/tags
+// Replaces all user tags on one media item (only the post owner can edit).
+func (h *PostHandler) UpdateMediaTags(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return
+	}
+	mediaID, err := strconv.ParseInt(chi.URLParam(r, "mediaId"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid media ID")
+		return
+	}
+
+	var req model.UpdateMediaTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	post, err := h.postService.ReplaceMediaTags(r.Context(), postID, mediaID, userID, req.Tags)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrMediaNotFound):
+			httputil.WriteNotFound(w, "Media not found")
+		case errors.Is(err, model.ErrNotPostOwner):
+			httputil.WriteForbidden(w, "You can only tag people on your own posts")
+		case errors.Is(err, model.ErrTooManyTags):
+			httputil.WriteBadRequest(w, "Too many tags (max 20 per media)")
+		case errors.Is(err, model.ErrInvalidTagPoint):
+			httputil.WriteBadRequest(w, "Tag coordinates must be between 0 and 1")
+		case errors.Is(err, model.ErrDuplicateTag):
+			httputil.WriteBadRequest(w, "A user can only be tagged once per media")
+		case errors.Is(err, model.ErrTaggedUserMissing):
+			httputil.WriteBadRequest(w, "Tagged user not found")
+		default:
+			log.Printf("[ERROR] Update media tags handler: user=%d post=%d media=%d err=%v", userID, postID, mediaID, err)
+			httputil.WriteInternalError(w, "Failed to update tags")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, post)
+}
+
+// RemoveMyTag handles DELETE /posts/:id/tags/me
+// Removes the authenticated user's tags from a post.
+func (h *PostHandler) RemoveMyTag(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return
+	}
+
+	err = h.postService.RemoveOwnTag(r.Context(), postID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrTagNotFound):
+			httputil.WriteNotFound(w, "You are not tagged in this post")
+		default:
+			log.Printf("[ERROR] Remove tag handler: user=%d post=%d err=%v", userID, postID, err)
+			httputil.WriteInternalError(w, "Failed to remove tag")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Tag removed successfully",
+	})
+}
+
+// GetTaggedPosts handles GET /users/:id/tagged
+// Returns paginated thumbnails of posts the user is tagged in.
+func (h *PostHandler) GetTaggedPosts(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid user ID")
+		return
+	}
+
+	var cursor *string
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		cursor = &c
+	}
+
+	limit := 12
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 {
+			httputil.WriteBadRequest(w, "Invalid limit parameter")
+			return
+		}
+		limit = parsed
+	}
+
+	var viewerID *int64
+	if id, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		viewerID = &id
+	}
+
+	posts, err := h.postService.GetTaggedPosts(r.Context(), userID, viewerID, cursor, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, "User not found")
+		case errors.Is(err, model.ErrPrivateAccount):
+			httputil.WriteForbidden(w, "This account is private")
+		default:
+			log.Printf("[ERROR] Get tagged posts handler: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to get tagged posts")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, posts)
+}
@@ -0,0 +1,354 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"iamstagram_22520060/internal/httputil"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/service"
+	"iamstagram_22520060/internal/transport/http/middleware"
+)
+
+type SavedHandler struct {
+	savedService *service.SavedService
+}
+
+func NewSavedHandler(savedService *service.SavedService) *SavedHandler {
+	return &SavedHandler{
+		savedService: savedService,
+	}
+}
+
+// Save handles POST /posts/:id/save
+func (h *SavedHandler) Save(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return
+	}
+
+	err = h.savedService.Save(r.Context(), postID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPostNotFound):
+			httputil.WriteNotFound(w, "Post not found")
+		case errors.Is(err, model.ErrAlreadySaved):
+			httputil.WriteConflict(w, "Already saved this post")
+		default:
+			log.Printf("[ERROR] Save post handler: user=%d post=%d err=%v", userID, postID, err)
+			httputil.WriteInternalError(w, "Failed to save post")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, map[string]string{
+		"message": "Post saved successfully",
+	})
+}
+
+// Unsave handles DELETE /posts/:id/save
+func (h *SavedHandler) Unsave(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	postID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return
+	}
+
+	err = h.savedService.Unsave(r.Context(), postID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrNotSaved):
+			httputil.WriteNotFound(w, "Have not saved this post")
+		default:
+			log.Printf("[ERROR] Unsave post handler: user=%d post=%d err=%v", userID, postID, err)
+			httputil.WriteInternalError(w, "Failed to unsave post")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Post unsaved successfully",
+	})
+}
+
+// GetSaved handles GET /me/saved
+// Returns the user's saved posts in the feed shape.
+func (h *SavedHandler) GetSaved(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	var cursor *string
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		cursor = &c
+	}
+
+	limit, ok := parseLimit(w, r, service.FeedDefaultLimit)
+	if !ok {
+		return
+	}
+
+	resp, err := h.savedService.GetSaved(r.Context(), userID, cursor, limit)
+	if err != nil {
+		log.Printf("[ERROR] Get saved posts handler: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Failed to get saved posts")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// ListCollections handles GET /me/collections
+func (h *SavedHandler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	resp, err := h.savedService.ListCollections(r.Context(), userID)
+	if err != nil {
+		log.Printf("[ERROR] List collections handler: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Failed to get collections")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// CreateCollection handles POST /me/collections
+func (h *SavedHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	var req model.CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	collection, err := h.savedService.CreateCollection(r.Context(), userID, req)
+	if err != nil {
+		if !writeCollectionError(w, err) {
+			log.Printf("[ERROR] Create collection handler: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to create collection")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, collection)
+}
+
+// RenameCollection handles PATCH /me/collections/:id
+func (h *SavedHandler) RenameCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	collectionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid collection ID")
+		return
+	}
+
+	var req model.CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	collection, err := h.savedService.RenameCollection(r.Context(), collectionID, userID, req)
+	if err != nil {
+		if !writeCollectionError(w, err) {
+			log.Printf("[ERROR] Rename collection handler: user=%d collection=%d err=%v", userID, collectionID, err)
+			httputil.WriteInternalError(w, "Failed to rename collection")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, collection)
+}
+
+// DeleteCollection handles DELETE /me/collections/:id
+func (h *SavedHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	collectionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid collection ID")
+		return
+	}
+
+	err = h.savedService.DeleteCollection(r.Context(), collectionID, userID)
+	if err != nil {
+		if !writeCollectionError(w, err) {
+			log.Printf("[ERROR] Delete collection handler: user=%d collection=%d err=%v", userID, collectionID, err)
+			httputil.WriteInternalError(w, "Failed to delete collection")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Collection deleted successfully",
+	})
+}
+
+// GetCollectionPosts handles GET /me/collections/:id/posts
+func (h *SavedHandler) GetCollectionPosts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	collectionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid collection ID")
+		return
+	}
+
+	var cursor *string
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		cursor = &c
+	}
+
+	limit, ok := parseLimit(w, r, service.FeedDefaultLimit)
+	if !ok {
+		return
+	}
+
+	resp, err := h.savedService.GetCollectionPosts(r.Context(), collectionID, userID, cursor, limit)
+	if err != nil {
+		if !writeCollectionError(w, err) {
+			log.Printf("[ERROR] Get collection posts handler: user=%d collection=%d err=%v", userID, collectionID, err)
+			httputil.WriteInternalError(w, "Failed to get collection posts")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// AddToCollection handles POST /me/collections/:id/posts/:postId
+// Saves the post if it isn't saved yet.
+func (h *SavedHandler) AddToCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	collectionID, postID, ok := parseCollectionPostIDs(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.savedService.AddToCollection(r.Context(), collectionID, postID, userID)
+	if err != nil {
+		if !writeCollectionError(w, err) {
+			log.Printf("[ERROR] Add to collection handler: user=%d collection=%d post=%d err=%v", userID, collectionID, postID, err)
+			httputil.WriteInternalError(w, "Failed to add post to collection")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, map[string]string{
+		"message": "Post added to collection",
+	})
+}
+
+// RemoveFromCollection handles DELETE /me/collections/:id/posts/:postId
+// The post stays saved.
+func (h *SavedHandler) RemoveFromCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Authentication required")
+		return
+	}
+
+	collectionID, postID, ok := parseCollectionPostIDs(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.savedService.RemoveFromCollection(r.Context(), collectionID, postID, userID)
+	if err != nil {
+		if !writeCollectionError(w, err) {
+			log.Printf("[ERROR] Remove from collection handler: user=%d collection=%d post=%d err=%v", userID, collectionID, postID, err)
+			httputil.WriteInternalError(w, "Failed to remove post from collection")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{
+		"message": "Post removed from collection",
+	})
+}
+
+// parseCollectionPostIDs reads the :id and :postId URL params.
+func parseCollectionPostIDs(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	collectionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid collection ID")
+		return 0, 0, false
+	}
+	postID, err := strconv.ParseInt(chi.URLParam(r, "postId"), 10, 64)
+	if err != nil {
+		httputil.WriteBadRequest(w, "Invalid post ID")
+		return 0, 0, false
+	}
+	return collectionID, postID, true
+}
+
+// writeCollectionError writes the response for known collection errors.
+// Returns false if err is unexpected and the caller should log it.
+func writeCollectionError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, model.ErrCollectionNotFound):
+		httputil.WriteNotFound(w, "Collection not found")
+	case errors.Is(err, model.ErrPostNotFound):
+		httputil.WriteNotFound(w, "Post not found")
+	case errors.Is(err, model.ErrNotInCollection):
+		httputil.WriteNotFound(w, "Post is not in this collection")
+	case errors.Is(err, model.ErrCollectionNameRequired):
+		httputil.WriteBadRequest(w, "Collection name is required")
+	case errors.Is(err, model.ErrCollectionNameTooLong):
+		httputil.WriteBadRequest(w, "Collection name too long (max 100 characters)")
+	case errors.Is(err, model.ErrTooManyCollections):
+		httputil.WriteBadRequest(w, "Too many collections (max 100)")
+	case errors.Is(err, model.ErrCollectionExists):
+		httputil.WriteConflict(w, "A collection with this name already exists")
+	default:
+		return false
+	}
+	return true
+}
@@ -1,24 +1,33 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
+	"iamstagram_22520060/internal/config"
 	"iamstagram_22520060/internal/httputil"
+	"iamstagram_22520060/internal/model"
 	"iamstagram_22520060/internal/service"
 	"iamstagram_22520060/internal/transport/http/middleware"
 )
 
 type UserHandler struct {
-	userService *service.UserService
+	userService  *service.UserService
+	mediaService *service.MediaService
+	config       *config.Config
 }
 
-func NewUserHandler(userService *service.UserService) *UserHandler {
+func NewUserHandler(userService *service.UserService, mediaService *service.MediaService, cfg *config.Config) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:  userService,
+		mediaService: mediaService,
+		config:       cfg,
 	}
 }
 
@@ -105,3 +114,196 @@ func (h *UserHandler) CompleteOnboarding(w http.ResponseWriter, r *http.Request)
 		"message": "Onboarding completed successfully",
 	})
 }
+
+// GetByUsername handles GET /users/by-username/:username
+// Also resolves handles the owner changed recently (redirected = true).
+func (h *UserHandler) GetByUsername(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+
+	var viewerID *int64
+	if id, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		viewerID = &id
+	}
+
+	resp, err := h.userService.GetProfileByUsername(r.Context(), username, viewerID)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			httputil.WriteNotFound(w, "User not found")
+			return
+		}
+		log.Printf("[ERROR] GetByUsername handler: username=%q err=%v", username, err)
+		httputil.WriteInternalError(w, "Failed to get user")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, resp)
+}
+
+// ChangeUsername handles PATCH /me/username
+func (h *UserHandler) ChangeUsername(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Not authenticated")
+		return
+	}
+
+	var req model.ChangeUsernameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	user, err := h.userService.ChangeUsername(r.Context(), userID, req.Username)
+	if err != nil {
+		if writeUsernameError(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, model.ErrUsernameExists):
+			httputil.WriteConflict(w, "Username already exists")
+		case errors.Is(err, model.ErrUsernameUnchanged):
+			httputil.WriteBadRequest(w, "This is already your username")
+		case errors.Is(err, model.ErrUsernameChangeLimit):
+			httputil.WriteError(w, http.StatusTooManyRequests, model.CodeUsernameChangeLimit, "You can change your username twice within 14 days")
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, "User not found")
+		default:
+			log.Printf("[ERROR] ChangeUsername handler: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to change username")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, user)
+}
+
+// UpdateProfile handles PATCH /me
+// Only the fields present in the body are changed.
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Not authenticated")
+		return
+	}
+
+	var req model.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteBadRequest(w, "Invalid request body")
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(r.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrDisplayNameRequired):
+			httputil.WriteBadRequest(w, "Display Name is required")
+		case errors.Is(err, model.ErrDisplayNameTooLong):
+			httputil.WriteBadRequest(w, "Display name too long (max 50 characters)")
+		case errors.Is(err, model.ErrBioTooLong):
+			httputil.WriteBadRequest(w, "Bio too long (max 150 characters)")
+		case errors.Is(err, model.ErrUserNotFound):
+			httputil.WriteNotFound(w, "User not found")
+		default:
+			log.Printf("[ERROR] UpdateProfile handler: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to update profile")
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, user)
+}
+
+// UpdateAvatar handles PUT /me/avatar (multipart, field "avatar")
+// The old avatar object is deleted from R2 unless it is the shared default.
+func (h *UserHandler) UpdateAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		httputil.WriteUnauthorized(w, "Not authenticated")
+		return
+	}
+
+	maxFormSize := int64(model.MaxAvatarSizeBytes) + 1024*1024 // allow form overhead
+	r.Body = http.MaxBytesReader(w, r.Body, maxFormSize)
+	if err := r.ParseMultipartForm(maxFormSize); err != nil {
+		if errors.Is(err, http.ErrNotMultipart) {
+			httputil.WriteBadRequest(w, "Content-Type must be multipart/form-data")
+			return
+		}
+		if strings.Contains(err.Error(), "request body too large") {
+			httputil.WriteBadRequestWithCode(w, model.CodeFileTooLarge, "Avatar exceeds 5MB limit")
+			return
+		}
+		httputil.WriteBadRequest(w, "Invalid form data")
+		return
+	}
+
+	file, header, err := r.FormFile("avatar")
+	if err != nil {
+		if err == http.ErrMissingFile {
+			httputil.WriteBadRequest(w, "Avatar is required")
+			return
+		}
+		httputil.WriteBadRequest(w, "Invalid avatar upload")
+		return
+	}
+	defer file.Close()
+
+	upload, err := h.mediaService.UploadAvatar(r.Context(), file, header)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrFileTooLarge):
+			httputil.WriteBadRequestWithCode(w, model.CodeFileTooLarge, "Avatar exceeds 5MB limit")
+		case errors.Is(err, model.ErrInvalidImageType):
+			httputil.WriteBadRequestWithCode(w, model.CodeInvalidImageType, "Unsupported image type. Allowed: jpeg, png, gif, webp")
+		default:
+			log.Printf("[ERROR] UpdateAvatar - upload: user=%d err=%v", userID, err)
+			httputil.WriteInternalError(w, "Failed to upload avatar")
+		}
+		return
+	}
+
+	oldKey, err := h.userService.UpdateAvatar(r.Context(), userID, upload.URL, upload.Key)
+	if err != nil {
+		// The new object is orphaned; remove it before failing
+		if delErr := h.mediaService.DeleteObject(r.Context(), upload.Key); delErr != nil {
+			log.Printf("[ERROR] UpdateAvatar - cleanup new avatar %s: %v", upload.Key, delErr)
+		}
+		if errors.Is(err, model.ErrUserNotFound) {
+			httputil.WriteNotFound(w, "User not found")
+			return
+		}
+		log.Printf("[ERROR] UpdateAvatar - save: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Failed to update avatar")
+		return
+	}
+
+	if oldKey != "" && oldKey != h.config.DefaultAvatarKey {
+		// Best effort: a leftover object only costs storage
+		if err := h.mediaService.DeleteObject(r.Context(), oldKey); err != nil {
+			log.Printf("[ERROR] UpdateAvatar - delete old avatar %s: %v", oldKey, err)
+		}
+	}
+
+	user, err := h.userService.GetByID(r.Context(), userID)
+	if err != nil {
+		log.Printf("[ERROR] UpdateAvatar - reload user: user=%d err=%v", userID, err)
+		httputil.WriteInternalError(w, "Failed to get user")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, user)
+}
+
+// writeUsernameError writes the response for username format errors.
+// Returns false if err is not one of them.
+func writeUsernameError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, model.ErrInvalidUsername):
+		httputil.WriteBadRequest(w, "Username must be 3-30 characters: letters, numbers, '.' and '_' (no leading, trailing or double dots)")
+	case errors.Is(err, model.ErrUsernameReserved):
+		httputil.WriteBadRequest(w, "This username is reserved")
+	default:
+		return false
+	}
+	return true
+}
@@ -79,6 +79,11 @@ func WriteForbidden(w http.ResponseWriter, message string) {
 	WriteError(w, http.StatusForbidden, ErrCodeForbidden, message)
 }
 
+// WriteForbiddenWithCode writes a 403 Forbidden error with a custom code
+func WriteForbiddenWithCode(w http.ResponseWriter, code string, message string) {
+	WriteError(w, http.StatusForbidden, code, message)
+}
+
 // WriteNotFound writes a 404 Not Found error
 func WriteNotFound(w http.ResponseWriter, message string) {
 	WriteError(w, http.StatusNotFound, ErrCodeNotFound, message)
@@ -0,0 +1,120 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional emails (password reset, email verification).
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPMailer sends mail through an SMTP server (STARTTLS is used when the server offers it).
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer creates a Mailer for host:port. Auth is skipped when username is empty.
+func NewSMTPMailer(host, port, username, password, from string) Mailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{
+		addr: host + ":" + port,
+		auth: auth,
+		from: from,
+	}
+}
+
+// Send delivers msg. net/smtp has no context support, so ctx is only checked up front.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(m.addr, m.auth, envelopeAddress(m.from), []string{msg.To}, buildMessage(m.from, msg)); err != nil {
+		log.Printf("[Mailer] SMTP send FAILED: to=%s subject=%q err=%v", msg.To, msg.Subject, err)
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	log.Printf("[Mailer] SMTP send OK: to=%s subject=%q", msg.To, msg.Subject)
+	return nil
+}
+
+// LogMailer is the development Mailer: it logs every message and, if dir is set,
+// also writes it there as an .eml file that can be opened with a mail client.
+type LogMailer struct {
+	from string
+	dir  string
+}
+
+// NewLogMailer creates a Mailer that never sends anything.
+func NewLogMailer(from, dir string) Mailer {
+	return &LogMailer{from: from, dir: dir}
+}
+
+// Send logs msg (including the body, which holds the token/link) and optionally saves it.
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("[Mailer] (log only) to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+
+	if m.dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("create mail dir: %w", err)
+	}
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), sanitizeFileName(msg.To))
+	if err := os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, msg), 0o644); err != nil {
+		return fmt.Errorf("write mail file: %w", err)
+	}
+	return nil
+}
+
+// buildMessage renders headers and body as an RFC 5322 message.
+func buildMessage(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// envelopeAddress extracts "addr" from "Name <addr>" for the SMTP MAIL FROM command.
+func envelopeAddress(from string) string {
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		return strings.TrimSuffix(from[i+1:], ">")
+	}
+	return from
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogMailer_WritesEML(t *testing.T) {
+	dir := t.TempDir()
+	m := NewLogMailer("Iamstagram <no-reply@example.com>", dir)
+
+	err := m.Send(context.Background(), Message{To: "john@example.com", Subject: "Hello", Body: "line 1\nline 2"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("got %d .eml files, want 1", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	for _, want := range []string{"To: john@example.com\r\n", "Subject: Hello\r\n", "\r\n\r\nline 1\r\nline 2"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("message missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestEnvelopeAddress(t *testing.T) {
+	if got := envelopeAddress("Iamstagram <no-reply@example.com>"); got != "no-reply@example.com" {
+		t.Errorf("got %q", got)
+	}
+	if got := envelopeAddress("no-reply@example.com"); got != "no-reply@example.com" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// DeleteAccountRequest is the body of DELETE /me
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// DeleteAccountResponse tells the client when the account will be purged.
+// Logging in before then cancels the deletion.
+type DeleteAccountResponse struct {
+	Message             string    `json:"message"`
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+const (
+	// AccountDeletionGracePeriod is how long a deleted account can still be restored by logging in
+	AccountDeletionGracePeriod = 30 * 24 * time.Hour
+
+	// AccountPurgeBatchSize caps how many accounts one run of the purge job removes
+	AccountPurgeBatchSize = 50
+)
@@ -0,0 +1,9 @@
+package model
+
+import "errors"
+
+var (
+	ErrCannotBlockSelf = errors.New("cannot block yourself")
+	ErrAlreadyBlocked  = errors.New("already blocked this user")
+	ErrNotBlocked      = errors.New("have not blocked this user")
+)
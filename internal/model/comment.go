@@ -12,8 +12,16 @@ type Comment struct {
 	UserID          int64        `db:"user_id" json:"-"`
 	Content         string       `db:"content" json:"content"`
 	ParentCommentID *int64       `db:"parent_comment_id" json:"parent_comment_id,omitempty"`
+	LikeCount       int          `db:"like_count" json:"like_count"`
+	ReplyCount      int          `db:"reply_count" json:"reply_count"` // Always 0 for replies
 	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
-	Author          *UserSummary `json:"author,omitempty"` // Joined field
+	EditedAt        *time.Time   `db:"edited_at" json:"edited_at,omitempty"`         // Last edit, nil if never edited
+	HiddenReason    *string      `db:"hidden_reason" json:"hidden_reason,omitempty"` // Set if filtered (only its author and the post author see it)
+	Author          *UserSummary `json:"author,omitempty"`                           // Joined field
+	IsLiked         bool         `json:"is_liked"`                                   // Viewer liked this comment
+	IsPinned        bool         `json:"is_pinned"`                                  // Pinned by the post author
+	Mentions        []Mention    `json:"mentions,omitempty"`                         // @username spans in the content
+	Replies         []Comment    `json:"replies,omitempty"`                          // First replies, top-level listing only
 }
 
 // CreateCommentRequest is the request body for creating a comment.
@@ -27,6 +35,21 @@ type UpdateCommentRequest struct {
 	Content string `json:"content"`
 }
 
+// CommentEdit is a previous version of an edited comment.
+type CommentEdit struct {
+	ID         int64     `db:"id" json:"id"`
+	CommentID  int64     `db:"comment_id" json:"comment_id"`
+	Content    string    `db:"content" json:"content"`
+	WrittenAt  time.Time `db:"written_at" json:"written_at"`   // When this version was written
+	ReplacedAt time.Time `db:"replaced_at" json:"replaced_at"` // When it was edited away
+}
+
+// CommentEditHistoryResponse is the edit history of a comment, newest version first.
+type CommentEditHistoryResponse struct {
+	Comment Comment       `json:"comment"` // Current version
+	Edits   []CommentEdit `json:"edits"`   // Previous versions, most recent first
+}
+
 // CommentListResponse is the paginated comment list response.
 type CommentListResponse struct {
 	Comments   []Comment `json:"comments"`
@@ -43,13 +66,26 @@ type LikersListResponse struct {
 
 // Comment constraints
 const (
-	MaxCommentLength = 2200 // Same as Instagram caption limit
+	MaxCommentLength         = 2200 // Same as Instagram caption limit
+	CommentReplyPreviewCount = 3    // Replies embedded under each top-level comment
 )
 
 // Comment errors
 var (
-	ErrCommentNotFound = errors.New("comment not found")
-	ErrNotCommentOwner = errors.New("not the owner of this comment")
-	ErrContentRequired = errors.New("comment content is required")
-	ErrContentTooLong  = errors.New("comment content too long")
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrNotCommentOwner     = errors.New("not the owner of this comment")
+	ErrContentRequired     = errors.New("comment content is required")
+	ErrContentTooLong      = errors.New("comment content too long")
+	ErrCommentsDisabled    = errors.New("comments are turned off for this post")
+	ErrCommentAlreadyLiked = errors.New("already liked this comment")
+	ErrCommentNotLiked     = errors.New("have not liked this comment")
+	ErrCannotPinReply      = errors.New("only top-level comments can be pinned")
+	ErrCommentNotPinned    = errors.New("comment is not pinned")
+	ErrEditWindowExpired   = errors.New("comment can no longer be edited")
+)
+
+// Error codes for HTTP responses
+const (
+	CodeCommentsDisabled  = "COMMENTS_DISABLED"
+	CodeEditWindowExpired = "EDIT_WINDOW_EXPIRED"
 )
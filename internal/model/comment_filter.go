@@ -0,0 +1,42 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Reasons a comment was hidden (post_comments.hidden_reason)
+const (
+	HiddenReasonHiddenWord = "hidden_word" // Matched one of the post author's hidden words
+	HiddenReasonOffensive  = "offensive"   // Matched the built-in offensive terms list
+	HiddenReasonSpam       = "spam"        // Same text posted on several posts in a short window
+)
+
+// CommentFilterSettings are a post author's filters for comments on their posts.
+type CommentFilterSettings struct {
+	UserID        int64    `db:"user_id" json:"-"`
+	HiddenWords   []string `db:"hidden_words" json:"hidden_words"`
+	HideOffensive bool     `db:"hide_offensive" json:"hide_offensive"`
+}
+
+// UpdateCommentFiltersRequest is the request body for PUT /me/comment-filters.
+// The hidden word list is replaced as a whole.
+type UpdateCommentFiltersRequest struct {
+	HiddenWords   []string `json:"hidden_words"`
+	HideOffensive bool     `json:"hide_offensive"`
+}
+
+// Comment filter constraints
+const (
+	MaxHiddenWords       = 100
+	MaxHiddenWordLength  = 50               // Runes per word/phrase
+	SpamRepeatWindow     = 10 * time.Minute // How far back identical comments are looked for
+	SpamRepeatOtherPosts = 2                // Identical comments on this many other posts = spam
+)
+
+// Comment filter errors
+var (
+	ErrTooManyHiddenWords = errors.New("too many hidden words")
+	ErrHiddenWordTooLong  = errors.New("hidden word too long")
+	ErrCommentNotHidden   = errors.New("comment is not hidden")
+)
@@ -9,7 +9,7 @@ import (
 type DeviceToken struct {
 	ID        int64     `db:"id" json:"id"`
 	UserID    int64     `db:"user_id" json:"-"`
-	Token     string    `db:"token" json:"-"`     // FCM token, hidden from JSON
+	Token     string    `db:"token" json:"-"`           // FCM token, hidden from JSON
 	Platform  string    `db:"platform" json:"platform"` // "ios", "android"
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
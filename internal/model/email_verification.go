@@ -0,0 +1,37 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// EmailVerificationToken is a single-use token mailed to confirm an address.
+type EmailVerificationToken struct {
+	OneTimeToken
+	Email string `db:"email"` // Address the token was sent to
+}
+
+// UpdateEmailRequest is the body of PUT /me/email. An empty email removes it.
+type UpdateEmailRequest struct {
+	Email string `json:"email"`
+}
+
+// VerifyEmailRequest is the body of POST /auth/verify-email
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+const (
+	EmailVerificationTokenTTL = 24 * time.Hour
+	MaxEmailLength            = 255
+
+	CodeVerificationTokenInvalid = "VERIFICATION_TOKEN_INVALID"
+)
+
+var (
+	ErrInvalidEmail             = errors.New("invalid email address")
+	ErrEmailExists              = errors.New("email already in use")
+	ErrEmailNotSet              = errors.New("no email on this account")
+	ErrEmailAlreadyVerified     = errors.New("email already verified")
+	ErrVerificationTokenInvalid = errors.New("email verification token is invalid or expired")
+)
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Data export statuses
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+	DataExportExpired = "expired"
+)
+
+const (
+	// DataExportRetention is how long a finished archive stays in R2
+	DataExportRetention = 3 * 24 * time.Hour
+
+	// DataExportLinkTTL is how long each presigned download URL is valid
+	DataExportLinkTTL = time.Hour
+
+	// DataExportFolder is the R2 prefix for export archives
+	DataExportFolder = "exports"
+
+	// DataExportCleanupBatchSize caps how many expired archives one cleanup run deletes
+	DataExportCleanupBatchSize = 100
+)
+
+// DataExport is a request for a ZIP of the user's data (POST /me/export).
+type DataExport struct {
+	ID          int64      `db:"id" json:"id"`
+	UserID      int64      `db:"user_id" json:"-"`
+	Status      string     `db:"status" json:"status"`
+	ObjectKey   *string    `db:"object_key" json:"-"`
+	SizeBytes   *int64     `db:"size_bytes" json:"size_bytes,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `db:"expires_at" json:"expires_at,omitempty"`
+
+	// Presigned for DataExportLinkTTL each time a ready export is fetched
+	DownloadURL *string `db:"-" json:"download_url,omitempty"`
+}
+
+// The types below are the JSON files inside the archive.
+
+// ExportPost is an entry of posts.json. Media files are stored next to it under media/.
+type ExportPost struct {
+	ID           int64         `db:"id" json:"id"`
+	Caption      *string       `db:"caption" json:"caption"`
+	LikeCount    int           `db:"like_count" json:"like_count"`
+	CommentCount int           `db:"comment_count" json:"comment_count"`
+	CreatedAt    time.Time     `db:"created_at" json:"created_at"`
+	Media        []ExportMedia `db:"-" json:"media"`
+}
+
+type ExportMedia struct {
+	PostID    int64  `db:"post_id" json:"-"`
+	MediaType string `db:"media_type" json:"media_type"`
+	MediaURL  string `db:"media_url" json:"media_url"`
+	Position  int    `db:"position" json:"position"`
+	File      string `db:"-" json:"file,omitempty"` // Path inside the archive; empty if the download failed
+}
+
+// ExportComment is an entry of comments.json (comments the user wrote)
+type ExportComment struct {
+	ID              int64     `db:"id" json:"id"`
+	PostID          int64     `db:"post_id" json:"post_id"`
+	ParentCommentID *int64    `db:"parent_comment_id" json:"parent_comment_id,omitempty"`
+	Content         string    `db:"content" json:"content"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+}
+
+// ExportLike is an entry of likes.json; exactly one of PostID and CommentID is set
+type ExportLike struct {
+	PostID    *int64    `db:"post_id" json:"post_id,omitempty"`
+	CommentID *int64    `db:"comment_id" json:"comment_id,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// ExportFollow is an entry of followers.json and following.json
+type ExportFollow struct {
+	UserID    int64     `db:"user_id" json:"user_id"`
+	Username  string    `db:"username" json:"username"`
+	CreatedAt time.Time `db:"created_at" json:"followed_at"`
+}
+
+// ExportNotification is an entry of notifications.json
+type ExportNotification struct {
+	Type          string    `db:"type" json:"type"`
+	ActorUsername string    `db:"actor_username" json:"actor_username"`
+	PostID        *int64    `db:"post_id" json:"post_id,omitempty"`
+	CommentID     *int64    `db:"comment_id" json:"comment_id,omitempty"`
+	IsRead        bool      `db:"is_read" json:"is_read"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+var (
+	ErrExportInProgress = errors.New("a data export is already being prepared")
+	ErrExportNotFound   = errors.New("data export not found")
+	// ErrExportNotPending is returned when a build finishes after the export was already failed as stale
+	ErrExportNotPending = errors.New("data export is no longer pending")
+)
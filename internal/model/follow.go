@@ -19,6 +19,19 @@ type UserSummary struct {
 	IsFollowing bool    `json:"is_following"`
 }
 
+// FollowStatus is the outcome of POST /users/:id/follow
+type FollowStatus string
+
+const (
+	FollowStatusFollowing FollowStatus = "following"
+	FollowStatusRequested FollowStatus = "requested" // Target is private; waiting for approval
+)
+
+// UpdatePrivacyRequest is the body of PUT /me/privacy
+type UpdatePrivacyRequest struct {
+	IsPrivate *bool `json:"is_private"`
+}
+
 type FollowListResponse struct {
 	Users      []UserSummary `json:"users"`
 	NextCursor *string       `json:"next_cursor,omitempty"`
@@ -29,4 +42,10 @@ var (
 	ErrAlreadyFollowing = errors.New("already following this user")
 	ErrNotFollowing     = errors.New("not following this user")
 	ErrCannotFollowSelf = errors.New("cannot follow yourself")
+
+	ErrFollowRequestExists   = errors.New("follow request already sent")
+	ErrFollowRequestNotFound = errors.New("follow request not found")
+
+	// ErrPrivateAccount is returned when a non-follower asks for a private account's posts or follow lists
+	ErrPrivateAccount = errors.New("this account is private")
 )
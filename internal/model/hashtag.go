@@ -0,0 +1,46 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Hashtag is a normalized hashtag (lowercase, without the leading '#').
+type Hashtag struct {
+	ID        int64     `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	PostCount int       `db:"post_count" json:"post_count"`
+	CreatedAt time.Time `db:"created_at" json:"-"`
+}
+
+// HashtagUsage is the raw usage count of a hashtag used to compute trending scores.
+type HashtagUsage struct {
+	Name     string `db:"name"`
+	Recent   int    `db:"recent"`   // Uses inside the recent window
+	Baseline int    `db:"baseline"` // Uses in the baseline window before it
+}
+
+// HashtagPostsResponse is the paginated hashtag page response.
+type HashtagPostsResponse struct {
+	Hashtag    Hashtag         `json:"hashtag"`
+	Posts      []PostThumbnail `json:"posts"`
+	NextCursor *string         `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
+
+// HashtagListResponse is returned by hashtag search and trending.
+type HashtagListResponse struct {
+	Hashtags []Hashtag `json:"hashtags"`
+}
+
+// Hashtag constants
+const (
+	MaxHashtagsPerPost = 30 // Instagram's limit
+	MaxHashtagLength   = 100
+)
+
+// Hashtag errors
+var (
+	ErrHashtagNotFound = errors.New("hashtag not found")
+	ErrTooManyHashtags = errors.New("too many hashtags")
+)
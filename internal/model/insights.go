@@ -0,0 +1,59 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Insight event types sent by clients
+const (
+	InsightEventImpression   = "impression"    // Post was shown on screen
+	InsightEventProfileVisit = "profile_visit" // Author's profile was opened from the post
+)
+
+// Impression sources
+const (
+	ImpressionSourceFeed    = "feed"
+	ImpressionSourceProfile = "profile"
+	ImpressionSourceOther   = "other" // Hashtag pages, saved posts, direct links...
+)
+
+// MaxInsightEventsPerBatch caps a single ingest request.
+const MaxInsightEventsPerBatch = 100
+
+// InsightEvent is a single client-side event attributed to a post.
+type InsightEvent struct {
+	PostID int64  `json:"post_id"`
+	Type   string `json:"type"`             // InsightEventImpression or InsightEventProfileVisit
+	Source string `json:"source,omitempty"` // Impressions only, defaults to "other"
+}
+
+// IngestInsightsRequest is the request body for POST /posts/impressions.
+type IngestInsightsRequest struct {
+	Events []InsightEvent `json:"events"`
+}
+
+// PostInsights is the author-only view of a post's performance.
+// Impressions and reach are rolled up periodically; likes, comments, reposts and saves are live.
+type PostInsights struct {
+	PostID             int64      `db:"post_id" json:"post_id"`
+	UserID             int64      `db:"user_id" json:"-"`
+	Impressions        int64      `db:"impressions" json:"impressions"`
+	ImpressionsFeed    int64      `db:"impressions_feed" json:"impressions_feed"`
+	ImpressionsProfile int64      `db:"impressions_profile" json:"impressions_profile"`
+	ImpressionsOther   int64      `db:"impressions_other" json:"impressions_other"`
+	Reach              int64      `db:"reach" json:"reach"`
+	Likes              int64      `db:"likes" json:"likes"`
+	Comments           int64      `db:"comments" json:"comments"`
+	Reposts            int64      `db:"reposts" json:"reposts"`
+	Saves              int64      `db:"saves" json:"saves"`
+	ProfileVisits      int64      `db:"profile_visits" json:"profile_visits"`
+	UpdatedAt          *time.Time `db:"updated_at" json:"updated_at"` // Last rollup, null if never rolled up
+}
+
+// Insights errors
+var (
+	ErrNoInsightEvents      = errors.New("no insight events")
+	ErrTooManyInsightEvents = errors.New("too many insight events")
+	ErrInvalidInsightEvent  = errors.New("invalid insight event")
+)
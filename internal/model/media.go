@@ -1,6 +1,9 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 const (
 	MaxAvatarSizeBytes = 5 * 1024 * 1024 // 5MB limit per media plan
@@ -26,18 +29,54 @@ var allowedImageTypes = map[string]struct{}{
 	ContentTypeWebP: {},
 }
 
+// Supported video content types for post uploads
+const (
+	ContentTypeMP4       = "video/mp4"
+	ContentTypeQuickTime = "video/quicktime"
+)
+
+var allowedVideoTypes = map[string]struct{}{
+	ContentTypeMP4:       {},
+	ContentTypeQuickTime: {},
+}
+
+// Media types stored in post_details.media_type
+const (
+	MediaTypeImage = "image"
+	MediaTypeVideo = "video"
+)
+
 // Error codes for HTTP responses
 const (
 	CodeFileTooLarge     = "FILE_TOO_LARGE"
 	CodeInvalidImageType = "INVALID_IMAGE_TYPE"
+	CodeInvalidMediaType = "INVALID_MEDIA_TYPE"
+	CodeInvalidMediaURL  = "INVALID_MEDIA_URL"
+	CodeMediaNotUploaded = "MEDIA_NOT_UPLOADED"
+	CodeMediaAlreadyUsed = "MEDIA_ALREADY_USED"
 )
 
 // Domain errors for media operations
 var (
 	ErrFileTooLarge     = errors.New("file too large")
 	ErrInvalidImageType = errors.New("invalid image type")
+	ErrInvalidMediaType = errors.New("invalid media type")
+	ErrMediaNotUploaded = errors.New("media was not uploaded")
+	ErrMediaAlreadyUsed = errors.New("media already attached to a post")
 )
 
+// MediaUpload records a presigned post upload issued to a user.
+// PostID/UsedAt are set once the object is attached to a post, so a key can't be reused.
+type MediaUpload struct {
+	ID          int64      `db:"id" json:"id"`
+	UserID      int64      `db:"user_id" json:"user_id"`
+	ObjectKey   string     `db:"object_key" json:"object_key"`
+	ContentType string     `db:"content_type" json:"content_type"`
+	PostID      *int64     `db:"post_id" json:"post_id,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UsedAt      *time.Time `db:"used_at" json:"used_at,omitempty"`
+}
+
 // UploadResult represents the uploaded object location
 // URL is the public-facing URL (using R2 public endpoint)
 // Key is the object key inside the bucket (useful for future deletes)
@@ -76,3 +115,30 @@ func IsAllowedImageType(contentType string) bool {
 	_, ok := allowedImageTypes[contentType]
 	return ok
 }
+
+// IsAllowedVideoType reports if the provided content type is a supported video
+func IsAllowedVideoType(contentType string) bool {
+	_, ok := allowedVideoTypes[contentType]
+	return ok
+}
+
+// IsAllowedPostMediaType reports if the content type can be used for post media (image or video)
+func IsAllowedPostMediaType(contentType string) bool {
+	return IsAllowedImageType(contentType) || IsAllowedVideoType(contentType)
+}
+
+// MediaTypeFromContentType maps an upload content type to the post_details media_type
+func MediaTypeFromContentType(contentType string) string {
+	if IsAllowedVideoType(contentType) {
+		return MediaTypeVideo
+	}
+	return MediaTypeImage
+}
+
+// MaxPostMediaSizeFor returns the upload size cap for the given content type
+func MaxPostMediaSizeFor(contentType string) int64 {
+	if IsAllowedVideoType(contentType) {
+		return MaxPostVideoSize
+	}
+	return MaxPostMediaSize
+}
@@ -0,0 +1,20 @@
+package model
+
+// Mention is a resolved "@username" reference in a caption or comment.
+// Offset and Length are in Unicode code points (not bytes or UTF-16 units)
+// and cover the leading '@', so clients can highlight and link the span.
+type Mention struct {
+	UserID   int64  `db:"mentioned_user_id" json:"user_id"`
+	Username string `db:"username" json:"username"`
+	Offset   int    `db:"start_offset" json:"offset"`
+	Length   int    `db:"length" json:"length"`
+
+	// Owner of the mention (for grouping batched lookups)
+	PostID    int64  `db:"post_id" json:"-"`
+	CommentID *int64 `db:"comment_id" json:"-"`
+}
+
+// Mention constants
+const (
+	MaxMentionsPerText = 20 // Distinct users; extra mentions stay plain text
+)
@@ -6,17 +6,48 @@ import (
 
 // Notification types
 const (
-	NotificationTypeFollow  = "follow"
-	NotificationTypeLike    = "like"
-	NotificationTypeComment = "comment"
+	NotificationTypeFollow       = "follow"
+	NotificationTypeLike         = "like"
+	NotificationTypeComment      = "comment"
+	NotificationTypeTag          = "tag"
+	NotificationTypeMention      = "mention"
+	NotificationTypeCommentLike  = "comment_like"
+	NotificationTypeCommentReply = "comment_replied"
+	NotificationTypeDataExport   = "data_export" // Sent to the user by themselves: their export is ready
 )
 
+// IndividualNotificationTypes are returned one by one in the notification list.
+var IndividualNotificationTypes = []string{
+	NotificationTypeFollow,
+	NotificationTypeDataExport,
+}
+
+// AggregatedNotificationTypes are grouped per post in the notification list.
+// Every other type (IndividualNotificationTypes) is returned individually.
+var AggregatedNotificationTypes = []string{
+	NotificationTypeLike,
+	NotificationTypeComment,
+	NotificationTypeTag,
+	NotificationTypeMention,
+	NotificationTypeCommentLike,
+	NotificationTypeCommentReply,
+}
+
+// PerCommentNotificationTypes are aggregated per comment (comment_id) instead of per post,
+// so the client can tell which comment was liked, replied to or mentioned the user.
+// Mentions in a caption have no comment_id and stay grouped per post.
+var PerCommentNotificationTypes = []string{
+	NotificationTypeMention,
+	NotificationTypeCommentLike,
+	NotificationTypeCommentReply,
+}
+
 // Notification represents a single notification record in the database.
 type Notification struct {
 	ID        int64     `db:"id" json:"id"`
-	UserID    int64     `db:"user_id" json:"-"`          // Recipient
-	ActorID   int64     `db:"actor_id" json:"actor_id"`  // Who triggered it
-	Type      string    `db:"type" json:"type"`          // follow, like, comment
+	UserID    int64     `db:"user_id" json:"-"`         // Recipient
+	ActorID   int64     `db:"actor_id" json:"actor_id"` // Who triggered it
+	Type      string    `db:"type" json:"type"`         // follow, like, comment
 	PostID    *int64    `db:"post_id" json:"post_id,omitempty"`
 	CommentID *int64    `db:"comment_id" json:"comment_id,omitempty"`
 	IsRead    bool      `db:"is_read" json:"is_read"`
@@ -29,20 +60,22 @@ type Notification struct {
 // AggregatedNotification groups likes/comments on the same post.
 // Used for "user1 and 5 others liked your post" display.
 type AggregatedNotification struct {
-	Type       string        `json:"type"`                    // like, comment
-	PostID     *int64        `json:"post_id,omitempty"`       // For navigation to post
-	ActorID    *int64        `json:"actor_id,omitempty"`      // For follow navigation
-	Actors     []UserSummary `json:"actors"`                  // First 2-3 actors
-	TotalCount int           `json:"total_count"`             // Total actors (for "and X others")
-	LatestAt   time.Time     `json:"latest_at"`               // Most recent activity
-	IsRead     bool          `json:"is_read"`                 // True if ALL in group are read
+	Type       string        `json:"type"`                 // like, comment
+	PostID     *int64        `json:"post_id,omitempty"`    // For navigation to post
+	CommentID  *int64        `json:"comment_id,omitempty"` // Parent comment, for per-comment types
+	ActorID    *int64        `json:"actor_id,omitempty"`   // For follow navigation
+	Actors     []UserSummary `json:"actors"`               // First 2-3 actors
+	TotalCount int           `json:"total_count"`          // Total actors (for "and X others")
+	LatestAt   time.Time     `json:"latest_at"`            // Most recent activity
+	IsRead     bool          `json:"is_read"`              // True if ALL in group are read
 }
 
 // NotificationListResponse is the paginated notification list response.
 type NotificationListResponse struct {
-	// Follows are not aggregated - shown individually
+	// Follows are not aggregated - shown individually.
+	// Also holds the other IndividualNotificationTypes (data_export).
 	Follows []Notification `json:"follows"`
-	// Likes and comments are aggregated by post
+	// Likes, comments, tags and mentions are aggregated by post
 	Aggregated []AggregatedNotification `json:"aggregated"`
 	// Unread count for badge
 	UnreadCount int `json:"unread_count"`
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// OneTimeToken holds the fields shared by single-use tokens sent by email
+// (password reset, email verification). Only the SHA-256 hash is stored, like refresh tokens.
+type OneTimeToken struct {
+	ID        int64      `db:"id"`
+	UserID    int64      `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// PasswordResetToken is a single-use token sent by email for "forgot password".
+type PasswordResetToken struct {
+	OneTimeToken
+}
+
+// PasswordResetRequest is the body of POST /auth/password-reset.
+// Login is a username or an email address.
+type PasswordResetRequest struct {
+	Login string `json:"login"`
+}
+
+// PasswordResetConfirmRequest is the body of POST /auth/password-reset/confirm
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// PasswordResetTokenTTL is how long a reset link stays valid
+const PasswordResetTokenTTL = time.Hour
+
+const CodeResetTokenInvalid = "RESET_TOKEN_INVALID"
+
+// ErrResetTokenInvalid covers unknown, expired and already used reset tokens
+var ErrResetTokenInvalid = errors.New("password reset token is invalid or expired")
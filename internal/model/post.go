@@ -10,38 +10,69 @@ type Post struct {
 	ID           int64      `db:"id" json:"id"`
 	UserID       int64      `db:"user_id" json:"user_id"`
 	Caption      *string    `db:"caption" json:"caption"`
-	LikeCount    int        `db:"like_count" json:"like_count"`
+	LikeCount    *int       `db:"like_count" json:"like_count,omitempty"` // nil when hidden from the viewer
 	CommentCount int        `db:"comment_count" json:"comment_count"`
+	RepostCount  int        `db:"repost_count" json:"repost_count"`
 	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
 	DeletedAt    *time.Time `db:"deleted_at" json:"-"`
 
+	// Settings chosen by the author
+	HideLikeCount    bool `db:"hide_like_count" json:"hide_like_count"`
+	CommentsDisabled bool `db:"comments_disabled" json:"comments_disabled"`
+
 	// Joined fields (not in posts table)
-	Media   []PostMedia  `json:"media,omitempty"`
-	Author  *UserSummary `json:"author,omitempty"`
-	IsLiked bool         `json:"is_liked"`
+	Media      []PostMedia  `json:"media,omitempty"`
+	Author     *UserSummary `json:"author,omitempty"`
+	IsLiked    bool         `json:"is_liked"`
+	IsSaved    bool         `json:"is_saved"`           // Viewer bookmarked this post
+	IsReposted bool         `json:"is_reposted"`        // Viewer reposted this post
+	Mentions   []Mention    `json:"mentions,omitempty"` // @username spans in the caption
+}
+
+// HideLikeCountFrom drops the like count if the author hid it and the viewer isn't the author.
+// viewerID is nil for anonymous viewers.
+func (p *Post) HideLikeCountFrom(viewerID *int64) {
+	if p.HideLikeCount && (viewerID == nil || *viewerID != p.UserID) {
+		p.LikeCount = nil
+	}
 }
 
 // PostMedia represents a single media item in a post (carousel support).
 type PostMedia struct {
-	ID        int64  `db:"id" json:"id"`
-	PostID    int64  `db:"post_id" json:"-"`
-	MediaURL  string `db:"media_url" json:"media_url"`
-	MediaType string `db:"media_type" json:"media_type"` // "image" or "video"
-	Position  int    `db:"position" json:"position"`
+	ID            int64   `db:"id" json:"id"`
+	PostID        int64   `db:"post_id" json:"-"`
+	MediaURL      string  `db:"media_url" json:"media_url"`
+	MediaType     string  `db:"media_type" json:"media_type"` // "image" or "video"
+	Position      int     `db:"position" json:"position"`
+	Width         *int    `db:"width" json:"width,omitempty"`
+	Height        *int    `db:"height" json:"height,omitempty"`
+	DurationMs    *int    `db:"duration_ms" json:"duration_ms,omitempty"`     // Videos only
+	ThumbnailURL  *string `db:"thumbnail_url" json:"thumbnail_url,omitempty"` // Poster frame for videos
+	ThumbnailKey  *string `db:"thumbnail_key" json:"-"`
+	AltText       *string `db:"alt_text" json:"alt_text,omitempty"`             // Author-provided description for screen readers
+	DominantColor *string `db:"dominant_color" json:"dominant_color,omitempty"` // "#rrggbb" placeholder while loading
+
+	// Users tagged on this media item
+	Tags []PostMediaTag `db:"-" json:"tags,omitempty"`
 }
 
 // PostThumbnail is a lightweight representation for profile grids.
 type PostThumbnail struct {
-	ID           int64  `db:"id" json:"id"`
-	ThumbnailURL string `db:"thumbnail_url" json:"thumbnail_url"` // First media URL
-	MediaCount   int    `db:"media_count" json:"media_count"`     // For carousel indicator
+	ID           int64     `db:"id" json:"id"`
+	ThumbnailURL string    `db:"thumbnail_url" json:"thumbnail_url"` // First media URL (poster frame for videos)
+	MediaType    string    `db:"media_type" json:"media_type"`       // Type of the first media item
+	MediaCount   int       `db:"media_count" json:"media_count"`     // For carousel indicator
+	CreatedAt    time.Time `db:"created_at" json:"-"`                // For cursor pagination
 }
 
 // FeedPost is an enriched post for feed display.
 type FeedPost struct {
 	Post
 	Author UserSummary `json:"author"`
+
+	// Set when the post is in the feed because someone the viewer follows reposted it
+	RepostedBy *UserSummary `json:"reposted_by,omitempty"`
 }
 
 // FeedResponse is the paginated feed response.
@@ -59,17 +90,72 @@ type PostListResponse struct {
 }
 
 // CreatePostRequest is the request body for creating a post.
+// Clients send either media (with optional alt text) or the older media_urls list.
 type CreatePostRequest struct {
-	Caption   *string  `json:"caption"`
-	MediaURLs []string `json:"media_urls"` // Pre-uploaded media URLs
+	Caption   *string               `json:"caption"`
+	Media     []CreatePostMediaItem `json:"media,omitempty"`
+	MediaURLs []string              `json:"media_urls,omitempty"` // Pre-uploaded media URLs (legacy)
+	PostSettings
+}
+
+// PostSettings are the author's per-post settings, set at creation.
+type PostSettings struct {
+	HideLikeCount    bool `json:"hide_like_count"`
+	CommentsDisabled bool `json:"comments_disabled"`
+}
+
+// UpdatePostRequest is the request body for PATCH /posts/:id.
+// Only fields that are present are changed.
+type UpdatePostRequest struct {
+	Caption          *string `json:"caption"`
+	HideLikeCount    *bool   `json:"hide_like_count"`
+	CommentsDisabled *bool   `json:"comments_disabled"`
+}
+
+// CreatePostMediaItem is a single pre-uploaded media item with optional alt text.
+type CreatePostMediaItem struct {
+	MediaURL string          `json:"media_url"`
+	AltText  *string         `json:"alt_text,omitempty"`
+	Tags     []MediaTagInput `json:"tags,omitempty"`
+}
+
+// MediaItems returns the request media in the item form, converting legacy media_urls.
+func (r CreatePostRequest) MediaItems() []CreatePostMediaItem {
+	if len(r.Media) > 0 {
+		return r.Media
+	}
+	items := make([]CreatePostMediaItem, len(r.MediaURLs))
+	for i, url := range r.MediaURLs {
+		items[i] = CreatePostMediaItem{MediaURL: url}
+	}
+	return items
+}
+
+// PostMediaInput is a verified media item ready to be stored in post_details.
+// Built by MediaService from the client's media_urls after checking the upload record and R2 object.
+type PostMediaInput struct {
+	URL           string
+	Key           string
+	MediaType     string
+	Width         *int
+	Height        *int
+	DurationMs    *int
+	ThumbnailURL  *string
+	ThumbnailKey  *string
+	AltText       *string
+	DominantColor *string
+	Tags          []MediaTagInput
 }
 
 // Post media constants
 const (
 	MaxPostMediaCount    = 10
 	MaxPostCaptionLength = 2200 // Instagram's limit
+	MaxAltTextLength     = 1000
 	PostMediaFolder      = "posts"
-	MaxPostMediaSize     = 10 * 1024 * 1024 // 10MB per media
+	PostThumbnailFolder  = "thumbnails"
+	MaxPostMediaSize     = 10 * 1024 * 1024  // 10MB per image
+	MaxPostVideoSize     = 100 * 1024 * 1024 // 100MB per video
 )
 
 // Post errors
@@ -80,6 +166,12 @@ var (
 	ErrTooManyMedia    = errors.New("too many media items")
 	ErrCaptionTooLong  = errors.New("caption too long")
 	ErrInvalidMediaURL = errors.New("invalid media URL")
+	ErrAltTextTooLong  = errors.New("alt text too long")
 	ErrAlreadyLiked    = errors.New("already liked this post")
 	ErrNotLiked        = errors.New("have not liked this post")
+	ErrCannotRepostOwn = errors.New("cannot repost your own post")
+	ErrAlreadyReposted = errors.New("already reposted this post")
+	ErrNotReposted     = errors.New("have not reposted this post")
+
+	ErrCannotRepostPrivate = errors.New("posts from private accounts can't be reposted")
 )
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Collection is a named group of a user's saved posts. Only its owner can see it.
+type Collection struct {
+	ID        int64     `db:"id" json:"id"`
+	UserID    int64     `db:"user_id" json:"-"`
+	Name      string    `db:"name" json:"name"`
+	PostCount int       `db:"post_count" json:"post_count"`
+	CoverURL  *string   `db:"cover_url" json:"cover_url"` // Thumbnail of the most recently added post
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// CollectionRequest is the request body for creating or renaming a collection.
+type CollectionRequest struct {
+	Name string `json:"name"`
+}
+
+// CollectionListResponse lists the current user's collections.
+type CollectionListResponse struct {
+	Collections []Collection `json:"collections"`
+}
+
+// CollectionPostsResponse is a collection with a page of its posts (feed shape).
+type CollectionPostsResponse struct {
+	Collection Collection `json:"collection"`
+	Posts      []FeedPost `json:"posts"`
+	NextCursor *string    `json:"next_cursor,omitempty"`
+	HasMore    bool       `json:"has_more"`
+}
+
+// SavedPostRef is a saved post ID with the time it was saved (for cursor pagination).
+type SavedPostRef struct {
+	PostID  int64     `db:"post_id"`
+	SavedAt time.Time `db:"saved_at"`
+}
+
+// Saved post constants
+const (
+	MaxCollectionNameLength = 100
+	MaxCollectionsPerUser   = 100
+)
+
+// Saved post errors
+var (
+	ErrAlreadySaved           = errors.New("already saved this post")
+	ErrNotSaved               = errors.New("have not saved this post")
+	ErrCollectionNotFound     = errors.New("collection not found")
+	ErrCollectionNameRequired = errors.New("collection name is required")
+	ErrCollectionNameTooLong  = errors.New("collection name too long")
+	ErrCollectionExists       = errors.New("collection name already exists")
+	ErrTooManyCollections     = errors.New("too many collections")
+	ErrNotInCollection        = errors.New("post is not in this collection")
+)
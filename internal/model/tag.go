@@ -0,0 +1,41 @@
+package model
+
+import "errors"
+
+// PostMediaTag is a user tagged on a single media item.
+// X and Y are normalized coordinates (0..1) measured from the top-left corner.
+type PostMediaTag struct {
+	ID           int64       `db:"id" json:"id"`
+	MediaID      int64       `db:"media_id" json:"-"`
+	TaggedUserID int64       `db:"tagged_user_id" json:"-"`
+	X            float64     `db:"x" json:"x"`
+	Y            float64     `db:"y" json:"y"`
+	User         UserSummary `db:"user" json:"user"`
+}
+
+// MediaTagInput is a tag sent by the client when creating a post or editing tags.
+type MediaTagInput struct {
+	UserID int64   `json:"user_id"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+}
+
+// UpdateMediaTagsRequest replaces all tags on one media item.
+type UpdateMediaTagsRequest struct {
+	Tags []MediaTagInput `json:"tags"`
+}
+
+// Tag constants
+const (
+	MaxTagsPerMedia = 20 // Instagram's limit
+)
+
+// Tag errors
+var (
+	ErrTooManyTags       = errors.New("too many tags on a media item")
+	ErrInvalidTagPoint   = errors.New("tag coordinates must be between 0 and 1")
+	ErrDuplicateTag      = errors.New("user is tagged more than once on the same media")
+	ErrTaggedUserMissing = errors.New("tagged user not found")
+	ErrMediaNotFound     = errors.New("media not found")
+	ErrTagNotFound       = errors.New("you are not tagged in this post")
+)
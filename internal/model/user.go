@@ -9,7 +9,9 @@ import (
 type User struct {
 	ID             int64     `db:"id" json:"id"`
 	Username       string    `db:"username" json:"username"`
-	PasswordHashed string    `db:"password_hashed" json:"-"` // "-" hides from JSON output
+	PasswordHashed string    `db:"password_hashed" json:"-"`     // "-" hides from JSON output
+	Email          *string   `db:"email" json:"email,omitempty"` // Only shown to the user themselves
+	EmailVerified  bool      `db:"email_verified" json:"email_verified"`
 	DisplayName    *string   `db:"display_name" json:"display_name"`
 	AvatarURL      *string   `db:"avatar_url" json:"avatar_url"`
 	AvatarKey      *string   `db:"avatar_key" json:"-"`
@@ -18,8 +20,12 @@ type User struct {
 	FollowerCount  int       `db:"follower_count" json:"follower_count"`
 	FollowingCount int       `db:"following_count" json:"following_count"`
 	PostCount      int       `db:"post_count" json:"post_count"`
+	IsPrivate      bool      `db:"is_private" json:"is_private"` // Posts and follow lists visible to approved followers only
 	CreatedAt      time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+
+	// Set while the account is pending deletion (only shown to the user themselves)
+	DeletionScheduledAt *time.Time `db:"deletion_scheduled_at" json:"deletion_scheduled_at,omitempty"`
 }
 
 // RegisterRequest represents the data needed to register a new user
@@ -27,21 +33,73 @@ type RegisterRequest struct {
 	Username    string  `json:"username"`
 	Password    string  `json:"password"`
 	DisplayName string  `json:"display_name"`
+	Email       *string `json:"email"` // Optional; verified separately
 	AvatarURL   *string `json:"-"`
 	AvatarKey   *string `json:"-"`
 }
 
-// LoginRequest represents the data needed to log in
+// LoginRequest represents the data needed to log in.
+// Username may also be the account's email address.
 type LoginRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
+// UpdateProfileRequest is the body of PATCH /me. Omitted fields are left unchanged;
+// an empty bio clears it.
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name"`
+	Bio         *string `json:"bio"`
+}
+
+const (
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 150
+)
+
 type ProfileResponse struct {
 	*User
 	IsFollowing bool `json:"is_following"`
+	IsRequested bool `json:"is_requested"` // Viewer has a pending follow request to this private account
+}
+
+// ChangeUsernameRequest is the body of PATCH /me/username
+type ChangeUsernameRequest struct {
+	Username string `json:"username"`
+}
+
+// ChangePasswordRequest is the body of POST /me/password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// UsernameLookupResponse is returned by GET /users/by-username/:username.
+// Redirected is true when the handle was one of the user's recent previous usernames.
+type UsernameLookupResponse struct {
+	*ProfileResponse
+	Redirected bool `json:"redirected"`
 }
 
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 30
+
+	// At most UsernameChangeLimit changes per UsernameChangeWindow
+	UsernameChangeLimit  = 2
+	UsernameChangeWindow = 14 * 24 * time.Hour
+
+	// A released handle stays reserved for (and resolves to) its previous owner this long
+	UsernameHoldPeriod = 14 * 24 * time.Hour
+
+	CodeUsernameChangeLimit = "USERNAME_CHANGE_LIMIT"
+
+	MinPasswordLength = 8
+	MaxPasswordLength = 72 // bcrypt ignores bytes past 72
+
+	CodeWeakPassword = "WEAK_PASSWORD"
+)
+
 var (
 	// ErrUserNotFound is returned when a user cannot be found
 	ErrUserNotFound = errors.New("user not found")
@@ -51,4 +109,19 @@ var (
 
 	// ErrInvalidCredentials is returned when login credentials are incorrect
 	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	ErrInvalidUsername     = errors.New("invalid username")
+	ErrUsernameReserved    = errors.New("username is reserved")
+	ErrUsernameUnchanged   = errors.New("username unchanged")
+	ErrUsernameChangeLimit = errors.New("username change limit reached")
+
+	ErrPasswordTooShort  = errors.New("password too short")
+	ErrPasswordTooLong   = errors.New("password too long")
+	ErrPasswordTooCommon = errors.New("password too common")
+	ErrPasswordUnchanged = errors.New("new password must differ from the current one")
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+
+	ErrDisplayNameRequired = errors.New("display name is required")
+	ErrDisplayNameTooLong  = errors.New("display name too long")
+	ErrBioTooLong          = errors.New("bio too long")
 )
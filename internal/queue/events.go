@@ -12,19 +12,36 @@ const (
 	EventPostDeleted    = "post_deleted"
 	EventUserFollowed   = "user_followed"
 	EventUserUnfollowed = "user_unfollowed"
+	EventPostReposted   = "post_reposted"
+	EventPostUnreposted = "post_unreposted"
 	// Notification events
-	EventPostLiked     = "post_liked"
-	EventPostCommented = "post_commented"
+	EventPostLiked      = "post_liked"
+	EventPostCommented  = "post_commented"
+	EventUserTagged     = "user_tagged"
+	EventUserMentioned  = "user_mentioned"
+	EventCommentLiked   = "comment_liked"
+	EventCommentReplied = "comment_replied"
+	// Account events
+	EventDataExportRequested = "data_export_requested"
+	// Media events
+	EventPostMediaAdded = "post_media_added"
 )
 
 // Stream names
 const (
 	StreamFeed = "stream:feed"
+	// StreamExport carries data export requests. Building an archive can take minutes,
+	// so exports get their own workers instead of holding up feed events.
+	StreamExport = "stream:export"
+	// StreamMedia carries post media processing (image decoding, ffprobe/ffmpeg), for the same reason.
+	StreamMedia = "stream:media"
 )
 
-// Consumer group name for feed workers
+// Consumer group names
 const (
-	ConsumerGroupFeed = "feed_workers"
+	ConsumerGroupFeed   = "feed_workers"
+	ConsumerGroupExport = "export_workers"
+	ConsumerGroupMedia  = "media_workers"
 )
 
 // FeedEvent represents an event published to the feed stream.
@@ -33,7 +50,7 @@ type FeedEvent struct {
 	Type      string `json:"type"`      // EventPostCreated, EventPostDeleted, EventUserFollowed
 	Timestamp int64  `json:"timestamp"` // Unix timestamp when event occurred
 
-	// Post events (PostCreated, PostDeleted)
+	// Post events (PostCreated, PostDeleted, PostReposted, PostUnreposted)
 	PostID   int64 `json:"post_id,omitempty"`
 	AuthorID int64 `json:"author_id,omitempty"`
 
@@ -41,10 +58,14 @@ type FeedEvent struct {
 	FollowerID int64 `json:"follower_id,omitempty"`
 	FolloweeID int64 `json:"followee_id,omitempty"`
 
-	// Notification events (PostLiked, PostCommented)
+	// Notification events (PostLiked, PostCommented, UserTagged, UserMentioned, CommentLiked, CommentReplied);
+	// also the reposter for PostReposted/PostUnreposted
 	ActorID     int64  `json:"actor_id,omitempty"`     // Who performed the action
 	RecipientID int64  `json:"recipient_id,omitempty"` // Who receives the notification
 	CommentID   *int64 `json:"comment_id,omitempty"`   // For comment notifications
+
+	// Data export event (DataExportRequested); the user is ActorID
+	ExportID int64 `json:"export_id,omitempty"`
 }
 
 // NewPostCreatedEvent creates an event for when a user creates a post.
@@ -91,6 +112,30 @@ func NewUserUnfollowedEvent(followerID, followeeID int64) FeedEvent {
 	}
 }
 
+// NewPostRepostedEvent creates an event for when a user reposts someone else's post.
+// Worker will fan-out the post to the reposter's followers' feed caches.
+func NewPostRepostedEvent(postID, authorID, reposterID int64) FeedEvent {
+	return FeedEvent{
+		Type:      EventPostReposted,
+		Timestamp: time.Now().Unix(),
+		PostID:    postID,
+		AuthorID:  authorID,
+		ActorID:   reposterID,
+	}
+}
+
+// NewPostUnrepostedEvent creates an event for when a user undoes a repost.
+// Worker will remove the post from feeds it reached through this repost.
+func NewPostUnrepostedEvent(postID, authorID, reposterID int64) FeedEvent {
+	return FeedEvent{
+		Type:      EventPostUnreposted,
+		Timestamp: time.Now().Unix(),
+		PostID:    postID,
+		AuthorID:  authorID,
+		ActorID:   reposterID,
+	}
+}
+
 // NewPostLikedEvent creates an event for when a user likes a post.
 // Worker will create a notification for the post author.
 func NewPostLikedEvent(postID, actorID, recipientID int64) FeedEvent {
@@ -117,6 +162,82 @@ func NewPostCommentedEvent(postID, commentID, actorID, recipientID int64) FeedEv
 	}
 }
 
+// NewCommentLikedEvent creates an event for when a user likes a comment.
+// Worker will create a notification for the comment author.
+func NewCommentLikedEvent(postID, commentID, actorID, recipientID int64) FeedEvent {
+	cid := commentID // Need pointer
+	return FeedEvent{
+		Type:        EventCommentLiked,
+		Timestamp:   time.Now().Unix(),
+		PostID:      postID,
+		CommentID:   &cid,
+		ActorID:     actorID,
+		RecipientID: recipientID,
+	}
+}
+
+// NewCommentRepliedEvent creates an event for when a user replies to a comment.
+// commentID is the (top-level) comment that was replied to.
+// Worker will create a notification for the parent comment's author.
+func NewCommentRepliedEvent(postID, commentID, actorID, recipientID int64) FeedEvent {
+	cid := commentID // Need pointer
+	return FeedEvent{
+		Type:        EventCommentReplied,
+		Timestamp:   time.Now().Unix(),
+		PostID:      postID,
+		CommentID:   &cid,
+		ActorID:     actorID,
+		RecipientID: recipientID,
+	}
+}
+
+// NewUserTaggedEvent creates an event for when a user is tagged in a post.
+// Worker will create a notification for the tagged user.
+func NewUserTaggedEvent(postID, actorID, recipientID int64) FeedEvent {
+	return FeedEvent{
+		Type:        EventUserTagged,
+		Timestamp:   time.Now().Unix(),
+		PostID:      postID,
+		ActorID:     actorID,
+		RecipientID: recipientID,
+	}
+}
+
+// NewUserMentionedEvent creates an event for when a user is @mentioned in a caption
+// (commentID nil) or a comment. Worker will create a notification for the mentioned user.
+func NewUserMentionedEvent(postID int64, commentID *int64, actorID, recipientID int64) FeedEvent {
+	return FeedEvent{
+		Type:        EventUserMentioned,
+		Timestamp:   time.Now().Unix(),
+		PostID:      postID,
+		CommentID:   commentID,
+		ActorID:     actorID,
+		RecipientID: recipientID,
+	}
+}
+
+// NewDataExportRequestedEvent creates an event for when a user asks for a copy of their data.
+// Worker will build the archive and notify the user when it's ready.
+func NewDataExportRequestedEvent(exportID, userID int64) FeedEvent {
+	return FeedEvent{
+		Type:      EventDataExportRequested,
+		Timestamp: time.Now().Unix(),
+		ExportID:  exportID,
+		ActorID:   userID,
+	}
+}
+
+// NewPostMediaAddedEvent creates an event for when a post is created.
+// Worker will compute image dominant colors, probe its videos and generate poster frames.
+func NewPostMediaAddedEvent(postID, authorID int64) FeedEvent {
+	return FeedEvent{
+		Type:      EventPostMediaAdded,
+		Timestamp: time.Now().Unix(),
+		PostID:    postID,
+		AuthorID:  authorID,
+	}
+}
+
 // ToMap converts the event to a map for Redis XADD.
 // Redis Streams store field-value pairs, so we serialize to JSON in a "data" field.
 func (e FeedEvent) ToMap() (map[string]interface{}, error) {
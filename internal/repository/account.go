@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type accountRepository struct {
+	db *sqlx.DB
+}
+
+func NewAccountRepository(db *sqlx.DB) AccountRepository {
+	return &accountRepository{db: db}
+}
+
+// GetDueDeletions returns accounts whose scheduled deletion time has passed, oldest first.
+func (r *accountRepository) GetDueDeletions(ctx context.Context, limit int) ([]int64, error) {
+	query := `
+		SELECT id FROM users
+		WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= NOW()
+		ORDER BY deletion_scheduled_at
+		LIMIT $1
+	`
+	var ids []int64
+	if err := r.db.SelectContext(ctx, &ids, query, limit); err != nil {
+		return nil, fmt.Errorf("get due deletions: %w", err)
+	}
+	return ids, nil
+}
+
+// GetLivePostIDs returns the IDs of a user's non-deleted posts.
+func (r *accountRepository) GetLivePostIDs(ctx context.Context, userID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.SelectContext(ctx, &ids, `SELECT id FROM posts WHERE user_id = $1 AND deleted_at IS NULL`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get live post ids: %w", err)
+	}
+	return ids, nil
+}
+
+// GetMediaKeys returns every R2 object owned by a user: presigned uploads (post media),
+// server-generated video thumbnails, the avatar and data export archives.
+// Soft-deleted posts are included.
+func (r *accountRepository) GetMediaKeys(ctx context.Context, userID int64) ([]string, error) {
+	query := `
+		SELECT object_key FROM media_uploads WHERE user_id = $1
+		UNION
+		SELECT d.thumbnail_key
+		FROM post_details d
+		JOIN posts p ON p.id = d.post_id
+		WHERE p.user_id = $1 AND d.thumbnail_key IS NOT NULL
+		UNION
+		SELECT avatar_key FROM users WHERE id = $1 AND avatar_key IS NOT NULL
+		UNION
+		SELECT object_key FROM data_exports WHERE user_id = $1 AND object_key IS NOT NULL
+	`
+	var keys []string
+	if err := r.db.SelectContext(ctx, &keys, query, userID); err != nil {
+		return nil, fmt.Errorf("get media keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Purge deletes the user row; posts, comments, likes, reposts, follows, saves, notifications,
+// device tokens and refresh tokens go with it through ON DELETE CASCADE.
+// Denormalized counters on rows that survive (other users' posts, comments, follow counts
+// and hashtags) are corrected first, while the rows being deleted can still be counted.
+// Returns false if the deletion was cancelled (or pushed back) since it was picked up.
+func (r *accountRepository) Purge(ctx context.Context, tx *sqlx.Tx, userID int64) (bool, error) {
+	// Lock the user so a login can't cancel the deletion halfway through
+	var id int64
+	err := tx.GetContext(ctx, &id, `
+		SELECT id FROM users
+		WHERE id = $1 AND deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= NOW()
+		FOR UPDATE
+	`, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("lock user: %w", err)
+	}
+
+	steps := []struct {
+		name  string
+		query string
+	}{
+		{"following counts", `
+			UPDATE users SET following_count = following_count - 1
+			WHERE id IN (SELECT follower_id FROM follows WHERE followee_id = $1)
+		`},
+		{"follower counts", `
+			UPDATE users SET follower_count = follower_count - 1
+			WHERE id IN (SELECT followee_id FROM follows WHERE follower_id = $1)
+		`},
+		{"post like counts", `
+			UPDATE posts p SET like_count = p.like_count - 1
+			FROM post_likes l
+			WHERE l.post_id = p.id AND l.user_id = $1 AND p.user_id <> $1
+		`},
+		// The user's comments plus every reply to them (replies cascade with their parent)
+		{"post comment counts", `
+			UPDATE posts p SET comment_count = p.comment_count - d.n
+			FROM (
+				SELECT c.post_id, COUNT(*) AS n
+				FROM post_comments c
+				WHERE c.user_id = $1
+				   OR c.parent_comment_id IN (SELECT id FROM post_comments WHERE user_id = $1)
+				GROUP BY c.post_id
+			) d
+			WHERE p.id = d.post_id AND p.user_id <> $1
+		`},
+		{"post repost counts", `
+			UPDATE posts p SET repost_count = p.repost_count - 1
+			FROM reposts rp
+			WHERE rp.post_id = p.id AND rp.user_id = $1 AND p.user_id <> $1
+		`},
+		{"comment like counts", `
+			UPDATE post_comments c SET like_count = c.like_count - 1
+			FROM comment_likes l
+			WHERE l.comment_id = c.id AND l.user_id = $1 AND c.user_id <> $1
+		`},
+		// Soft-deleted posts were already detached from their hashtags
+		{"hashtag post counts", `
+			UPDATE hashtags h SET post_count = h.post_count - d.n
+			FROM (
+				SELECT ph.hashtag_id, COUNT(*) AS n
+				FROM post_hashtags ph
+				JOIN posts p ON p.id = ph.post_id
+				WHERE p.user_id = $1 AND p.deleted_at IS NULL
+				GROUP BY ph.hashtag_id
+			) d
+			WHERE h.id = d.hashtag_id
+		`},
+		{"user", `DELETE FROM users WHERE id = $1`},
+	}
+
+	for _, step := range steps {
+		if _, err := tx.ExecContext(ctx, step.query, userID); err != nil {
+			return false, fmt.Errorf("purge %s: %w", step.name, err)
+		}
+	}
+	return true, nil
+}
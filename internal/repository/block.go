@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"iamstagram_22520060/internal/model"
+)
+
+type blockRepository struct {
+	db *sqlx.DB
+}
+
+func NewBlockRepository(db *sqlx.DB) BlockRepository {
+	return &blockRepository{db: db}
+}
+
+// notBlocked is the filter that hides users blocked by, or blocking, the viewer.
+// userCol is the column holding the other user's ID and viewerParam the placeholder
+// holding the viewer ID (0 if anonymous, which matches no block).
+func notBlocked(userCol, viewerParam string) string {
+	return fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM blocks bl
+		WHERE (bl.blocker_id = %[2]s AND bl.blocked_id = %[1]s) OR (bl.blocker_id = %[1]s AND bl.blocked_id = %[2]s))`, userCol, viewerParam)
+}
+
+// Create records a block. Returns false if the user was already blocked.
+func (r *blockRepository) Create(ctx context.Context, tx *sqlx.Tx, blockerID, blockedID int64) (bool, error) {
+	query := `
+		INSERT INTO blocks (blocker_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+	`
+	result, err := tx.ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return false, fmt.Errorf("failed to create block: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *blockRepository) Delete(ctx context.Context, blockerID, blockedID int64) error {
+	query := `DELETE FROM blocks WHERE blocker_id = $1 AND blocked_id = $2`
+	result, err := r.db.ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to delete block: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrNotBlocked
+	}
+
+	return nil
+}
+
+// IsBlocked reports whether either user has blocked the other.
+func (r *blockRepository) IsBlocked(ctx context.Context, userID, otherID int64) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`
+	var blocked bool
+	if err := r.db.GetContext(ctx, &blocked, query, userID, otherID); err != nil {
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+	return blocked, nil
+}
+
+// GetBlockedIDs returns everyone the user blocked or was blocked by.
+func (r *blockRepository) GetBlockedIDs(ctx context.Context, userID int64) ([]int64, error) {
+	query := `
+		SELECT blocked_id FROM blocks WHERE blocker_id = $1
+		UNION
+		SELECT blocker_id FROM blocks WHERE blocked_id = $1
+	`
+	var ids []int64
+	if err := r.db.SelectContext(ctx, &ids, query, userID); err != nil {
+		return nil, fmt.Errorf("get blocked ids: %w", err)
+	}
+	return ids, nil
+}
+
+// GetBlocked retrieves the users the blocker has blocked, most recent first.
+// Uses the same created_at cursor as followRepository.GetFollowers.
+func (r *blockRepository) GetBlocked(ctx context.Context, blockerID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error) {
+	var query string
+	var args []interface{}
+
+	if cursor == nil {
+		query = `
+			SELECT u.id, u.username, u.display_name, u.avatar_url, b.created_at
+			FROM blocks b
+			JOIN users u ON u.id = b.blocked_id
+			WHERE b.blocker_id = $1
+			ORDER BY b.created_at DESC
+			LIMIT $2
+		`
+		args = []interface{}{blockerID, limit + 1}
+	} else {
+		query = `
+			SELECT u.id, u.username, u.display_name, u.avatar_url, b.created_at
+			FROM blocks b
+			JOIN users u ON u.id = b.blocked_id
+			WHERE b.blocker_id = $1 AND b.created_at < $2
+			ORDER BY b.created_at DESC
+			LIMIT $3
+		`
+		args = []interface{}{blockerID, cursor, limit + 1}
+	}
+
+	type userWithTime struct {
+		model.UserSummary
+		CreatedAt time.Time `db:"created_at"`
+	}
+
+	var results []userWithTime
+	if err := r.db.SelectContext(ctx, &results, query, args...); err != nil {
+		return nil, nil, fmt.Errorf("failed to get blocked users: %w", err)
+	}
+
+	var users []model.UserSummary
+	var nextCursor *time.Time
+
+	if len(results) > limit {
+		results = results[:limit]
+		nextCursor = &results[len(results)-1].CreatedAt
+	}
+
+	for _, result := range results {
+		users = append(users, result.UserSummary)
+	}
+
+	return users, nextCursor, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"iamstagram_22520060/internal/model"
 )
@@ -20,35 +21,48 @@ func NewCommentRepository(db *sqlx.DB) CommentRepository {
 	return &commentRepository{db: db}
 }
 
-// Create inserts a new comment. Uses transaction for atomic counter update.
-func (r *commentRepository) Create(ctx context.Context, tx *sqlx.Tx, postID, userID int64, content string, parentID *int64) (*model.Comment, error) {
+// Create inserts a new comment, hidden if hiddenReason is set. Uses transaction for atomic counter update.
+func (r *commentRepository) Create(ctx context.Context, tx *sqlx.Tx, postID, userID int64, content string, parentID *int64, hiddenReason *string) (*model.Comment, error) {
 	query := `
-		INSERT INTO post_comments (post_id, user_id, content, parent_comment_id)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, post_id, user_id, content, parent_comment_id, created_at
+		INSERT INTO post_comments (post_id, user_id, content, parent_comment_id, hidden_reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, post_id, user_id, content, parent_comment_id, like_count, created_at, edited_at, hidden_reason
 	`
 	var comment model.Comment
-	err := tx.GetContext(ctx, &comment, query, postID, userID, content, parentID)
+	err := tx.GetContext(ctx, &comment, query, postID, userID, content, parentID, hiddenReason)
 	if err != nil {
 		return nil, fmt.Errorf("insert comment: %w", err)
 	}
 	return &comment, nil
 }
 
-// Update updates a comment's content. Only the owner can update.
-func (r *commentRepository) Update(ctx context.Context, commentID, userID int64, content string) (*model.Comment, error) {
+// Update updates a comment's content and sets edited_at. Only the owner can update.
+// The previous version is kept in comment_edits. A non-nil hiddenReason hides the
+// comment; an already hidden comment stays hidden.
+func (r *commentRepository) Update(ctx context.Context, tx *sqlx.Tx, commentID, userID int64, content string, hiddenReason *string) (*model.Comment, error) {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO comment_edits (comment_id, content, written_at)
+		SELECT id, content, COALESCE(edited_at, created_at)
+		FROM post_comments
+		WHERE id = $1 AND user_id = $2
+	`, commentID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("archive comment version: %w", err)
+	}
+
 	query := `
 		UPDATE post_comments 
-		SET content = $1
+		SET content = $1, edited_at = NOW(), hidden_reason = COALESCE(hidden_reason, $4)
 		WHERE id = $2 AND user_id = $3
-		RETURNING id, post_id, user_id, content, parent_comment_id, created_at
+		RETURNING id, post_id, user_id, content, parent_comment_id, like_count, created_at, edited_at, hidden_reason,
+		          (SELECT COUNT(*) FROM post_comments r WHERE r.parent_comment_id = post_comments.id) as reply_count
 	`
 	var comment model.Comment
-	err := r.db.GetContext(ctx, &comment, query, content, commentID, userID)
+	err = tx.GetContext(ctx, &comment, query, content, commentID, userID, hiddenReason)
 	if err == sql.ErrNoRows {
 		// Check if comment exists but belongs to different user
 		var exists bool
-		r.db.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM post_comments WHERE id = $1)`, commentID)
+		tx.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM post_comments WHERE id = $1)`, commentID)
 		if exists {
 			return nil, model.ErrNotCommentOwner
 		}
@@ -105,96 +119,256 @@ func (r *commentRepository) Delete(ctx context.Context, tx *sqlx.Tx, commentID,
 	return comment.PostID, deletedCount, nil
 }
 
-// GetByPostID returns paginated comments for a post.
-func (r *commentRepository) GetByPostID(ctx context.Context, postID int64, cursor *string, limit int) ([]model.Comment, *string, error) {
-	var query string
-	var args []interface{}
+// visibleComment is the filter for comments the viewer may see: visible comments, plus
+// hidden ones the viewer wrote or that are on the viewer's post, minus comments of users
+// blocked by or blocking the viewer. alias is the comment table alias and viewerParam the
+// placeholder holding the viewer ID (0 if anonymous).
+func visibleComment(alias, viewerParam string) string {
+	return fmt.Sprintf(`((%[1]s.hidden_reason IS NULL OR %[1]s.user_id = %[2]s
+		OR EXISTS (SELECT 1 FROM posts vp WHERE vp.id = %[1]s.post_id AND vp.user_id = %[2]s))
+		AND %[3]s)`, alias, viewerParam, notBlocked(alias+".user_id", viewerParam))
+}
 
-	if cursor == nil {
-		query = `
-			SELECT c.id, c.post_id, c.user_id, c.content, c.parent_comment_id, c.created_at,
-			       u.id as "author.id", u.username as "author.username", 
-			       u.display_name as "author.display_name", u.avatar_url as "author.avatar_url"
-			FROM post_comments c
-			JOIN users u ON u.id = c.user_id
-			WHERE c.post_id = $1
-			ORDER BY c.created_at DESC, c.id DESC
-			LIMIT $2
-		`
-		args = []interface{}{postID, limit + 1}
-	} else {
+// commentListColumns selects a comment row aliased as c, joined with its author as u.
+// reply_count only counts replies visible to the viewer in viewerParam.
+func commentListColumns(viewerParam string) string {
+	return `c.id, c.post_id, c.user_id, c.content, c.parent_comment_id, c.like_count, c.created_at, c.edited_at, c.hidden_reason,
+		       (SELECT COUNT(*) FROM post_comments r WHERE r.parent_comment_id = c.id AND ` + visibleComment("r", viewerParam) + `) as reply_count,
+		       u.id as "author.id", u.username as "author.username",
+		       u.display_name as "author.display_name", u.avatar_url as "author.avatar_url"`
+}
+
+// commentRow scans commentListColumns (plus an optional row number for previews).
+type commentRow struct {
+	ID              int64      `db:"id"`
+	PostID          int64      `db:"post_id"`
+	UserID          int64      `db:"user_id"`
+	Content         string     `db:"content"`
+	ParentCommentID *int64     `db:"parent_comment_id"`
+	LikeCount       int        `db:"like_count"`
+	ReplyCount      int        `db:"reply_count"`
+	CreatedAt       time.Time  `db:"created_at"`
+	EditedAt        *time.Time `db:"edited_at"`
+	HiddenReason    *string    `db:"hidden_reason"`
+	AuthorID        int64      `db:"author.id"`
+	AuthorUsername  string     `db:"author.username"`
+	AuthorDisplay   *string    `db:"author.display_name"`
+	AuthorAvatar    *string    `db:"author.avatar_url"`
+	RowNumber       int        `db:"rn"`
+}
+
+func (row commentRow) toComment() model.Comment {
+	return model.Comment{
+		ID:              row.ID,
+		PostID:          row.PostID,
+		UserID:          row.UserID,
+		Content:         row.Content,
+		ParentCommentID: row.ParentCommentID,
+		LikeCount:       row.LikeCount,
+		ReplyCount:      row.ReplyCount,
+		CreatedAt:       row.CreatedAt,
+		EditedAt:        row.EditedAt,
+		HiddenReason:    row.HiddenReason,
+		Author: &model.UserSummary{
+			ID:          row.AuthorID,
+			Username:    row.AuthorUsername,
+			DisplayName: row.AuthorDisplay,
+			AvatarURL:   row.AuthorAvatar,
+		},
+	}
+}
+
+// GetByPostID returns paginated top-level comments for a post visible to the viewer, newest first.
+// The pinned comment is left out (the service puts it first).
+// Replies are fetched separately (GetReplies / GetReplyPreviews).
+func (r *commentRepository) GetByPostID(ctx context.Context, postID, viewerID int64, cursor *string, limit int) ([]model.Comment, *string, error) {
+	query := `
+		SELECT ` + commentListColumns("$2") + `
+		FROM post_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.post_id = $1 AND c.parent_comment_id IS NULL AND ` + visibleComment("c", "$2") + `
+		  AND c.id IS DISTINCT FROM (SELECT pinned_comment_id FROM posts WHERE id = $1)
+	`
+	args := []interface{}{postID, viewerID}
+
+	if cursor != nil {
+		ts, id, err := parseCommentCursor(*cursor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		query += ` AND (c.created_at, c.id) < ($3, $4)`
+		args = append(args, ts, id)
+	}
+	query += fmt.Sprintf(` ORDER BY c.created_at DESC, c.id DESC LIMIT $%d`, len(args)+1)
+	args = append(args, limit+1)
+
+	return r.selectCommentPage(ctx, limit, query, args...)
+}
+
+// GetReplies returns paginated replies of a comment visible to the viewer, oldest first (reading order).
+func (r *commentRepository) GetReplies(ctx context.Context, parentID, viewerID int64, cursor *string, limit int) ([]model.Comment, *string, error) {
+	query := `
+		SELECT ` + commentListColumns("$2") + `
+		FROM post_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.parent_comment_id = $1 AND ` + visibleComment("c", "$2") + `
+	`
+	args := []interface{}{parentID, viewerID}
+
+	if cursor != nil {
+		ts, id, err := parseCommentCursor(*cursor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		// Cursor timestamps are whole seconds, so compare truncated to avoid repeating the last row
+		query += ` AND (date_trunc('second', c.created_at), c.id) > ($3, $4)`
+		args = append(args, ts, id)
+	}
+	query += fmt.Sprintf(` ORDER BY c.created_at ASC, c.id ASC LIMIT $%d`, len(args)+1)
+	args = append(args, limit+1)
+
+	return r.selectCommentPage(ctx, limit, query, args...)
+}
+
+// GetHidden returns paginated hidden comments (top-level and replies) on a post, newest first.
+func (r *commentRepository) GetHidden(ctx context.Context, postID, viewerID int64, cursor *string, limit int) ([]model.Comment, *string, error) {
+	query := `
+		SELECT ` + commentListColumns("$2") + `
+		FROM post_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.post_id = $1 AND c.hidden_reason IS NOT NULL
+	`
+	args := []interface{}{postID, viewerID}
+
+	if cursor != nil {
 		ts, id, err := parseCommentCursor(*cursor)
 		if err != nil {
 			return nil, nil, fmt.Errorf("invalid cursor: %w", err)
 		}
-		query = `
-			SELECT c.id, c.post_id, c.user_id, c.content, c.parent_comment_id, c.created_at,
-			       u.id as "author.id", u.username as "author.username", 
-			       u.display_name as "author.display_name", u.avatar_url as "author.avatar_url"
+		query += ` AND (c.created_at, c.id) < ($3, $4)`
+		args = append(args, ts, id)
+	}
+	query += fmt.Sprintf(` ORDER BY c.created_at DESC, c.id DESC LIMIT $%d`, len(args)+1)
+	args = append(args, limit+1)
+
+	return r.selectCommentPage(ctx, limit, query, args...)
+}
+
+// GetReplyPreviews returns the first perParent replies (oldest first) of each parent comment
+// that are visible to the viewer.
+func (r *commentRepository) GetReplyPreviews(ctx context.Context, parentIDs []int64, viewerID int64, perParent int) (map[int64][]model.Comment, error) {
+	result := make(map[int64][]model.Comment)
+	if len(parentIDs) == 0 || perParent <= 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT * FROM (
+			SELECT ` + commentListColumns("$2") + `,
+			       ROW_NUMBER() OVER (PARTITION BY c.parent_comment_id ORDER BY c.created_at ASC, c.id ASC) as rn
 			FROM post_comments c
 			JOIN users u ON u.id = c.user_id
-			WHERE c.post_id = $1 AND (c.created_at, c.id) < ($2, $3)
-			ORDER BY c.created_at DESC, c.id DESC
-			LIMIT $4
-		`
-		args = []interface{}{postID, ts, id, limit + 1}
-	}
-
-	// Use a struct that can scan the joined author data
-	type commentRow struct {
-		ID              int64     `db:"id"`
-		PostID          int64     `db:"post_id"`
-		UserID          int64     `db:"user_id"`
-		Content         string    `db:"content"`
-		ParentCommentID *int64    `db:"parent_comment_id"`
-		CreatedAt       time.Time `db:"created_at"`
-		AuthorID        int64     `db:"author.id"`
-		AuthorUsername  string    `db:"author.username"`
-		AuthorDisplay   *string   `db:"author.display_name"`
-		AuthorAvatar    *string   `db:"author.avatar_url"`
+			WHERE c.parent_comment_id = ANY($1) AND ` + visibleComment("c", "$2") + `
+		) t
+		WHERE t.rn <= $3
+		ORDER BY t.parent_comment_id, t.rn
+	`
+	replies, err := r.selectComments(ctx, query, pq.Array(parentIDs), viewerID, perParent)
+	if err != nil {
+		return nil, err
 	}
 
-	var rows []commentRow
-	err := r.db.SelectContext(ctx, &rows, query, args...)
+	if err := r.attachMentions(ctx, replies); err != nil {
+		return nil, err
+	}
+	for _, reply := range replies {
+		result[*reply.ParentCommentID] = append(result[*reply.ParentCommentID], reply)
+	}
+	return result, nil
+}
+
+// GetWithAuthor returns a single comment in the list shape (author, reply count, mentions).
+// Comments hidden from the viewer are reported as not found.
+func (r *commentRepository) GetWithAuthor(ctx context.Context, commentID, viewerID int64) (*model.Comment, error) {
+	comments, err := r.selectComments(ctx, `
+		SELECT `+commentListColumns("$2")+`
+		FROM post_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1 AND `+visibleComment("c", "$2")+`
+	`, commentID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, model.ErrCommentNotFound
+	}
+
+	if err := r.attachMentions(ctx, comments); err != nil {
+		return nil, err
+	}
+	return &comments[0], nil
+}
+
+// selectCommentPage runs a limit+1 commentListColumns query and returns the page with mentions.
+func (r *commentRepository) selectCommentPage(ctx context.Context, limit int, query string, args ...interface{}) ([]model.Comment, *string, error) {
+	comments, err := r.selectComments(ctx, query, args...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("get comments: %w", err)
+		return nil, nil, err
+	}
+	comments, nextCursor := paginateComments(comments, limit)
+
+	if err := r.attachMentions(ctx, comments); err != nil {
+		return nil, nil, err
+	}
+	return comments, nextCursor, nil
+}
+
+// selectComments runs a commentListColumns query and converts the rows.
+func (r *commentRepository) selectComments(ctx context.Context, query string, args ...interface{}) ([]model.Comment, error) {
+	var rows []commentRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("get comments: %w", err)
 	}
 
-	// Convert to Comment with Author
 	comments := make([]model.Comment, len(rows))
 	for i, row := range rows {
-		comments[i] = model.Comment{
-			ID:              row.ID,
-			PostID:          row.PostID,
-			UserID:          row.UserID,
-			Content:         row.Content,
-			ParentCommentID: row.ParentCommentID,
-			CreatedAt:       row.CreatedAt,
-			Author: &model.UserSummary{
-				ID:          row.AuthorID,
-				Username:    row.AuthorUsername,
-				DisplayName: row.AuthorDisplay,
-				AvatarURL:   row.AuthorAvatar,
-			},
-		}
+		comments[i] = row.toComment()
 	}
+	return comments, nil
+}
 
-	// Check for more and build cursor
-	var nextCursor *string
-	if len(comments) > limit {
-		comments = comments[:limit]
-		last := comments[len(comments)-1]
-		c := formatCommentCursor(last.CreatedAt, last.ID)
-		nextCursor = &c
+// attachMentions fills in the mention spans of each comment.
+func (r *commentRepository) attachMentions(ctx context.Context, comments []model.Comment) error {
+	commentIDs := make([]int64, len(comments))
+	for i := range comments {
+		commentIDs[i] = comments[i].ID
+	}
+	mentions, err := getCommentMentions(ctx, r.db, commentIDs)
+	if err != nil {
+		return err
 	}
+	for i := range comments {
+		comments[i].Mentions = mentions[comments[i].ID]
+	}
+	return nil
+}
 
-	return comments, nextCursor, nil
+// paginateComments trims a limit+1 page and builds the cursor from its last comment.
+func paginateComments(comments []model.Comment, limit int) ([]model.Comment, *string) {
+	if len(comments) <= limit {
+		return comments, nil
+	}
+	comments = comments[:limit]
+	last := comments[len(comments)-1]
+	c := formatCommentCursor(last.CreatedAt, last.ID)
+	return comments, &c
 }
 
 // GetByID retrieves a single comment.
 func (r *commentRepository) GetByID(ctx context.Context, commentID int64) (*model.Comment, error) {
 	query := `
-		SELECT id, post_id, user_id, content, parent_comment_id, created_at
+		SELECT id, post_id, user_id, content, parent_comment_id, like_count, created_at, edited_at, hidden_reason,
+		       (SELECT COUNT(*) FROM post_comments r WHERE r.parent_comment_id = post_comments.id) as reply_count
 		FROM post_comments
 		WHERE id = $1
 	`
@@ -209,6 +383,127 @@ func (r *commentRepository) GetByID(ctx context.Context, commentID int64) (*mode
 	return &comment, nil
 }
 
+// Unhide makes a hidden comment on a post visible. Returns ErrCommentNotHidden if it isn't hidden.
+func (r *commentRepository) Unhide(ctx context.Context, postID, commentID int64) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE post_comments SET hidden_reason = NULL
+		WHERE id = $1 AND post_id = $2 AND hidden_reason IS NOT NULL
+	`, commentID, postID)
+	if err != nil {
+		return fmt.Errorf("unhide comment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrCommentNotHidden
+	}
+	return nil
+}
+
+// CountRecentDuplicatePosts counts the other posts on which the user left the same text
+// (ignoring case and surrounding spaces) since the given time.
+func (r *commentRepository) CountRecentDuplicatePosts(ctx context.Context, userID, postID int64, content string, since time.Time) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `
+		SELECT COUNT(DISTINCT post_id) FROM post_comments
+		WHERE user_id = $1 AND post_id <> $2 AND created_at >= $3
+		  AND lower(btrim(content)) = lower(btrim($4))
+	`, userID, postID, since, content)
+	if err != nil {
+		return 0, fmt.Errorf("count duplicate comments: %w", err)
+	}
+	return count, nil
+}
+
+// GetEdits returns the previous versions of a comment, most recent first.
+func (r *commentRepository) GetEdits(ctx context.Context, commentID int64) ([]model.CommentEdit, error) {
+	edits := []model.CommentEdit{}
+	err := r.db.SelectContext(ctx, &edits, `
+		SELECT id, comment_id, content, written_at, replaced_at
+		FROM comment_edits
+		WHERE comment_id = $1
+		ORDER BY id DESC
+	`, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("get comment edits: %w", err)
+	}
+	return edits, nil
+}
+
+// Like inserts a comment like record. Returns ErrCommentAlreadyLiked if duplicate.
+func (r *commentRepository) Like(ctx context.Context, tx *sqlx.Tx, commentID, userID int64) error {
+	query := `INSERT INTO comment_likes (comment_id, user_id) VALUES ($1, $2)`
+	_, err := tx.ExecContext(ctx, query, commentID, userID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return model.ErrCommentAlreadyLiked
+		}
+		return fmt.Errorf("insert comment like: %w", err)
+	}
+	return nil
+}
+
+// Unlike deletes a comment like record. Returns ErrCommentNotLiked if not found.
+func (r *commentRepository) Unlike(ctx context.Context, tx *sqlx.Tx, commentID, userID int64) error {
+	query := `DELETE FROM comment_likes WHERE comment_id = $1 AND user_id = $2`
+	result, err := tx.ExecContext(ctx, query, commentID, userID)
+	if err != nil {
+		return fmt.Errorf("delete comment like: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrCommentNotLiked
+	}
+	return nil
+}
+
+// IncrementLikeCount atomically updates the like_count on a comment.
+func (r *commentRepository) IncrementLikeCount(ctx context.Context, tx *sqlx.Tx, commentID int64, delta int) error {
+	query := `UPDATE post_comments SET like_count = like_count + $1 WHERE id = $2`
+	result, err := tx.ExecContext(ctx, query, delta, commentID)
+	if err != nil {
+		return fmt.Errorf("update comment like count: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrCommentNotFound
+	}
+	return nil
+}
+
+// CheckLikes checks which comments the user has liked.
+// Returns a map of comment_id -> liked (true/false).
+func (r *commentRepository) CheckLikes(ctx context.Context, userID int64, commentIDs []int64) (map[int64]bool, error) {
+	if len(commentIDs) == 0 {
+		return make(map[int64]bool), nil
+	}
+
+	query := `SELECT comment_id FROM comment_likes WHERE user_id = $1 AND comment_id = ANY($2)`
+	var likedIDs []int64
+	err := r.db.SelectContext(ctx, &likedIDs, query, userID, pq.Array(commentIDs))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("check comment likes: %w", err)
+	}
+
+	result := make(map[int64]bool)
+	for _, id := range commentIDs {
+		result[id] = false
+	}
+	for _, id := range likedIDs {
+		result[id] = true
+	}
+
+	return result, nil
+}
+
 // Helper: parse comment cursor "id:timestamp"
 func parseCommentCursor(cursor string) (time.Time, int64, error) {
 	parts := strings.Split(cursor, ":")
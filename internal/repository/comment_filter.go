@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"iamstagram_22520060/internal/model"
+)
+
+type commentFilterRepository struct {
+	db *sqlx.DB
+}
+
+func NewCommentFilterRepository(db *sqlx.DB) CommentFilterRepository {
+	return &commentFilterRepository{db: db}
+}
+
+// Get returns a user's comment filters, or the defaults (no filtering) if never set.
+func (r *commentFilterRepository) Get(ctx context.Context, userID int64) (*model.CommentFilterSettings, error) {
+	var row struct {
+		HiddenWords   pq.StringArray `db:"hidden_words"`
+		HideOffensive bool           `db:"hide_offensive"`
+	}
+	err := r.db.GetContext(ctx, &row, `
+		SELECT hidden_words, hide_offensive FROM comment_filter_settings WHERE user_id = $1
+	`, userID)
+	if err == sql.ErrNoRows {
+		return &model.CommentFilterSettings{UserID: userID, HiddenWords: []string{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get comment filters: %w", err)
+	}
+
+	words := []string(row.HiddenWords)
+	if words == nil {
+		words = []string{}
+	}
+	return &model.CommentFilterSettings{
+		UserID:        userID,
+		HiddenWords:   words,
+		HideOffensive: row.HideOffensive,
+	}, nil
+}
+
+// Upsert stores a user's comment filters, replacing the previous ones.
+func (r *commentFilterRepository) Upsert(ctx context.Context, settings *model.CommentFilterSettings) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO comment_filter_settings (user_id, hidden_words, hide_offensive)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET hidden_words = EXCLUDED.hidden_words,
+		    hide_offensive = EXCLUDED.hide_offensive,
+		    updated_at = NOW()
+	`, settings.UserID, pq.Array(settings.HiddenWords), settings.HideOffensive)
+	if err != nil {
+		return fmt.Errorf("upsert comment filters: %w", err)
+	}
+	return nil
+}
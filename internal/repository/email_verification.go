@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"iamstagram_22520060/internal/model"
+)
+
+var emailVerificationTokens = oneTimeTokenTable{
+	name:       "email_verification_tokens",
+	columns:    "id, user_id, email, token_hash, expires_at, used_at, created_at",
+	label:      "email verification token",
+	errInvalid: model.ErrVerificationTokenInvalid,
+}
+
+type emailVerificationRepository struct {
+	db *sqlx.DB
+}
+
+// NewEmailVerificationRepository creates a new email verification token repository
+func NewEmailVerificationRepository(db *sqlx.DB) EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+// Create inserts a new verification token and invalidates the user's older ones
+func (r *emailVerificationRepository) Create(ctx context.Context, token *model.EmailVerificationToken) error {
+	return emailVerificationTokens.create(ctx, r.db, &token.OneTimeToken, []string{"email"}, token.Email)
+}
+
+// FindValid retrieves an unused, unexpired verification token by its hash
+func (r *emailVerificationRepository) FindValid(ctx context.Context, tokenHash string) (*model.EmailVerificationToken, error) {
+	var token model.EmailVerificationToken
+	if err := emailVerificationTokens.findValid(ctx, r.db, &token, tokenHash); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes a token
+func (r *emailVerificationRepository) MarkUsed(ctx context.Context, id int64) error {
+	return emailVerificationTokens.markUsed(ctx, r.db, id)
+}
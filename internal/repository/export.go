@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"iamstagram_22520060/internal/model"
+)
+
+type exportRepository struct {
+	db *sqlx.DB
+}
+
+func NewExportRepository(db *sqlx.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+const exportColumns = `id, user_id, status, object_key, size_bytes, created_at, completed_at, expires_at`
+
+// Create records a pending export. The partial unique index allows one pending export per user.
+func (r *exportRepository) Create(ctx context.Context, userID int64) (*model.DataExport, error) {
+	query := `INSERT INTO data_exports (user_id) VALUES ($1) RETURNING ` + exportColumns
+
+	var export model.DataExport
+	if err := r.db.GetContext(ctx, &export, query, userID); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, model.ErrExportInProgress
+		}
+		return nil, fmt.Errorf("insert data export: %w", err)
+	}
+	return &export, nil
+}
+
+func (r *exportRepository) GetByID(ctx context.Context, exportID int64) (*model.DataExport, error) {
+	var export model.DataExport
+	err := r.db.GetContext(ctx, &export, `SELECT `+exportColumns+` FROM data_exports WHERE id = $1`, exportID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrExportNotFound
+		}
+		return nil, fmt.Errorf("get data export: %w", err)
+	}
+	return &export, nil
+}
+
+func (r *exportRepository) GetLatest(ctx context.Context, userID int64) (*model.DataExport, error) {
+	query := `SELECT ` + exportColumns + ` FROM data_exports WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1`
+
+	var export model.DataExport
+	if err := r.db.GetContext(ctx, &export, query, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrExportNotFound
+		}
+		return nil, fmt.Errorf("get latest data export: %w", err)
+	}
+	return &export, nil
+}
+
+func (r *exportRepository) MarkReady(ctx context.Context, exportID int64, objectKey string, sizeBytes int64, expiresAt time.Time) error {
+	query := `
+		UPDATE data_exports
+		SET status = $1, object_key = $2, size_bytes = $3, completed_at = NOW(), expires_at = $4
+		WHERE id = $5 AND status = $6
+	`
+	result, err := r.db.ExecContext(ctx, query, model.DataExportReady, objectKey, sizeBytes, expiresAt, exportID, model.DataExportPending)
+	if err != nil {
+		return fmt.Errorf("mark data export ready: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		// FailStale gave up on it while it was building; the user may have started another
+		return model.ErrExportNotPending
+	}
+	return nil
+}
+
+func (r *exportRepository) MarkFailed(ctx context.Context, exportID int64) error {
+	query := `UPDATE data_exports SET status = $1, completed_at = NOW() WHERE id = $2`
+	if _, err := r.db.ExecContext(ctx, query, model.DataExportFailed, exportID); err != nil {
+		return fmt.Errorf("mark data export failed: %w", err)
+	}
+	return nil
+}
+
+func (r *exportRepository) GetExpired(ctx context.Context, limit int) ([]model.DataExport, error) {
+	query := `
+		SELECT ` + exportColumns + ` FROM data_exports
+		WHERE status = $1 AND expires_at <= NOW()
+		ORDER BY expires_at
+		LIMIT $2
+	`
+	var exports []model.DataExport
+	if err := r.db.SelectContext(ctx, &exports, query, model.DataExportReady, limit); err != nil {
+		return nil, fmt.Errorf("get expired data exports: %w", err)
+	}
+	return exports, nil
+}
+
+func (r *exportRepository) MarkExpired(ctx context.Context, exportID int64) error {
+	query := `UPDATE data_exports SET status = $1, object_key = NULL WHERE id = $2`
+	if _, err := r.db.ExecContext(ctx, query, model.DataExportExpired, exportID); err != nil {
+		return fmt.Errorf("mark data export expired: %w", err)
+	}
+	return nil
+}
+
+func (r *exportRepository) FailStale(ctx context.Context, olderThan time.Time) (int64, error) {
+	query := `UPDATE data_exports SET status = $1, completed_at = NOW() WHERE status = $2 AND created_at < $3`
+	result, err := r.db.ExecContext(ctx, query, model.DataExportFailed, model.DataExportPending, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("fail stale data exports: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows, nil
+}
+
+// GetPosts returns the user's live posts with their media, oldest first.
+func (r *exportRepository) GetPosts(ctx context.Context, userID int64) ([]model.ExportPost, error) {
+	var posts []model.ExportPost
+	err := r.db.SelectContext(ctx, &posts, `
+		SELECT id, caption, like_count, comment_count, created_at
+		FROM posts
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get export posts: %w", err)
+	}
+	if len(posts) == 0 {
+		return posts, nil
+	}
+
+	var media []model.ExportMedia
+	err = r.db.SelectContext(ctx, &media, `
+		SELECT d.post_id, d.media_type, d.media_url, d."position"
+		FROM post_details d
+		JOIN posts p ON p.id = d.post_id
+		WHERE p.user_id = $1 AND p.deleted_at IS NULL
+		ORDER BY d.post_id, d."position"
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get export media: %w", err)
+	}
+
+	byPost := make(map[int64][]model.ExportMedia)
+	for _, m := range media {
+		byPost[m.PostID] = append(byPost[m.PostID], m)
+	}
+	for i := range posts {
+		posts[i].Media = byPost[posts[i].ID]
+		if posts[i].Media == nil {
+			posts[i].Media = []model.ExportMedia{}
+		}
+	}
+	return posts, nil
+}
+
+// GetComments returns the comments the user wrote, oldest first.
+func (r *exportRepository) GetComments(ctx context.Context, userID int64) ([]model.ExportComment, error) {
+	var comments []model.ExportComment
+	err := r.db.SelectContext(ctx, &comments, `
+		SELECT id, post_id, parent_comment_id, content, created_at
+		FROM post_comments
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get export comments: %w", err)
+	}
+	return comments, nil
+}
+
+// GetLikes returns the user's post and comment likes, oldest first.
+func (r *exportRepository) GetLikes(ctx context.Context, userID int64) ([]model.ExportLike, error) {
+	var likes []model.ExportLike
+	err := r.db.SelectContext(ctx, &likes, `
+		SELECT post_id, NULL::BIGINT AS comment_id, created_at FROM post_likes WHERE user_id = $1
+		UNION ALL
+		SELECT NULL::BIGINT, comment_id, created_at FROM comment_likes WHERE user_id = $1
+		ORDER BY created_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get export likes: %w", err)
+	}
+	return likes, nil
+}
+
+func (r *exportRepository) GetFollowers(ctx context.Context, userID int64) ([]model.ExportFollow, error) {
+	var follows []model.ExportFollow
+	err := r.db.SelectContext(ctx, &follows, `
+		SELECT u.id AS user_id, u.username, f.created_at
+		FROM follows f
+		JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = $1
+		ORDER BY f.created_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get export followers: %w", err)
+	}
+	return follows, nil
+}
+
+func (r *exportRepository) GetFollowing(ctx context.Context, userID int64) ([]model.ExportFollow, error) {
+	var follows []model.ExportFollow
+	err := r.db.SelectContext(ctx, &follows, `
+		SELECT u.id AS user_id, u.username, f.created_at
+		FROM follows f
+		JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1
+		ORDER BY f.created_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get export following: %w", err)
+	}
+	return follows, nil
+}
+
+// GetNotifications returns the notifications the user received, newest first.
+func (r *exportRepository) GetNotifications(ctx context.Context, userID int64) ([]model.ExportNotification, error) {
+	var notifications []model.ExportNotification
+	err := r.db.SelectContext(ctx, &notifications, `
+		SELECT n.type, u.username AS actor_username, n.post_id, n.comment_id, n.is_read, n.created_at
+		FROM notifications n
+		JOIN users u ON u.id = n.actor_id
+		WHERE n.user_id = $1
+		ORDER BY n.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get export notifications: %w", err)
+	}
+	return notifications, nil
+}
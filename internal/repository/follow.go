@@ -83,7 +83,7 @@ func (r *followRepository) Exists(ctx context.Context, followerID, followeeID in
 //	❌ Offset pagination breaks when data changes, has O(n) performance on large offsets
 //
 // Returns: users slice, nextCursor (nil if no more results), error
-func (r *followRepository) GetFollowers(ctx context.Context, userID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error) {
+func (r *followRepository) GetFollowers(ctx context.Context, userID, viewerID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error) {
 	var query string
 	var args []interface{}
 
@@ -92,21 +92,22 @@ func (r *followRepository) GetFollowers(ctx context.Context, userID int64, curso
 			SELECT u.id, u.username, u.display_name, u.avatar_url, f.created_at
 			FROM follows f
 			JOIN users u ON u.id = f.follower_id
-			WHERE f.followee_id = $1
+			WHERE f.followee_id = $1 AND ` + notBlocked("u.id", "$2") + `
 			ORDER BY f.created_at DESC
-			LIMIT $2
+			LIMIT $3
 		`
-		args = []interface{}{userID, limit + 1}
+		args = []interface{}{userID, viewerID, limit + 1}
 	} else {
 		query = `
 			SELECT u.id, u.username, u.display_name, u.avatar_url, f.created_at
 			FROM follows f
 			JOIN users u ON u.id = f.follower_id
-			WHERE f.followee_id = $1 AND f.created_at < $2
+			WHERE f.followee_id = $1 AND ` + notBlocked("u.id", "$2") + `
+			  AND f.created_at < $3
 			ORDER BY f.created_at DESC
-			LIMIT $3
+			LIMIT $4
 		`
-		args = []interface{}{userID, cursor, limit + 1}
+		args = []interface{}{userID, viewerID, cursor, limit + 1}
 	}
 
 	type userWithTime struct {
@@ -139,7 +140,7 @@ func (r *followRepository) GetFollowers(ctx context.Context, userID int64, curso
 
 // GetFollowing retrieves users that the specified user follows with cursor-based pagination.
 // See GetFollowers documentation for detailed explanation of cursor pagination approach.
-func (r *followRepository) GetFollowing(ctx context.Context, userID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error) {
+func (r *followRepository) GetFollowing(ctx context.Context, userID, viewerID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error) {
 	var query string
 	var args []interface{}
 
@@ -148,21 +149,22 @@ func (r *followRepository) GetFollowing(ctx context.Context, userID int64, curso
 			SELECT u.id, u.username, u.display_name, u.avatar_url, f.created_at
 			FROM follows f
 			JOIN users u ON u.id = f.followee_id
-			WHERE f.follower_id = $1
+			WHERE f.follower_id = $1 AND ` + notBlocked("u.id", "$2") + `
 			ORDER BY f.created_at DESC
-			LIMIT $2
+			LIMIT $3
 		`
-		args = []interface{}{userID, limit + 1}
+		args = []interface{}{userID, viewerID, limit + 1}
 	} else {
 		query = `
 			SELECT u.id, u.username, u.display_name, u.avatar_url, f.created_at
 			FROM follows f
 			JOIN users u ON u.id = f.followee_id
-			WHERE f.follower_id = $1 AND f.created_at < $2
+			WHERE f.follower_id = $1 AND ` + notBlocked("u.id", "$2") + `
+			  AND f.created_at < $3
 			ORDER BY f.created_at DESC
-			LIMIT $3
+			LIMIT $4
 		`
-		args = []interface{}{userID, cursor, limit + 1}
+		args = []interface{}{userID, viewerID, cursor, limit + 1}
 	}
 
 	type userWithTime struct {
@@ -235,3 +237,166 @@ func (r *followRepository) GetFolloweeIDs(ctx context.Context, userID int64) ([]
 	}
 	return ids, nil
 }
+
+// CreateRequest records a pending follow request to a private account.
+// Returns false if one is already pending.
+func (r *followRepository) CreateRequest(ctx context.Context, requesterID, targetID int64) (bool, error) {
+	query := `
+		INSERT INTO follow_requests (requester_id, target_id)
+		VALUES ($1, $2)
+		ON CONFLICT (requester_id, target_id) DO NOTHING
+	`
+	result, err := r.db.ExecContext(ctx, query, requesterID, targetID)
+	if err != nil {
+		return false, fmt.Errorf("failed to create follow request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// DeleteRequest removes a pending request (declined by the target or withdrawn by the requester).
+func (r *followRepository) DeleteRequest(ctx context.Context, requesterID, targetID int64) error {
+	query := `DELETE FROM follow_requests WHERE requester_id = $1 AND target_id = $2`
+	result, err := r.db.ExecContext(ctx, query, requesterID, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to delete follow request: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrFollowRequestNotFound
+	}
+
+	return nil
+}
+
+func (r *followRepository) RequestExists(ctx context.Context, requesterID, targetID int64) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM follow_requests WHERE requester_id = $1 AND target_id = $2)`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, requesterID, targetID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check follow request existence: %w", err)
+	}
+	return exists, nil
+}
+
+// AcceptRequest turns a pending request into a follow in one statement.
+// Returns ErrFollowRequestNotFound if there was no pending request.
+func (r *followRepository) AcceptRequest(ctx context.Context, tx *sqlx.Tx, requesterID, targetID int64) error {
+	query := `
+		WITH req AS (
+			DELETE FROM follow_requests WHERE requester_id = $1 AND target_id = $2
+			RETURNING requester_id, target_id
+		)
+		INSERT INTO follows (follower_id, followee_id)
+		SELECT requester_id, target_id FROM req
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+	`
+	result, err := tx.ExecContext(ctx, query, requesterID, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to accept follow request: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrFollowRequestNotFound
+	}
+
+	return nil
+}
+
+// AcceptAllRequests turns every pending request to targetID into a follow
+// (used when the account goes public). Returns the new followers' IDs.
+func (r *followRepository) AcceptAllRequests(ctx context.Context, tx *sqlx.Tx, targetID int64) ([]int64, error) {
+	query := `
+		WITH req AS (
+			DELETE FROM follow_requests WHERE target_id = $1
+			RETURNING requester_id, target_id
+		)
+		INSERT INTO follows (follower_id, followee_id)
+		SELECT requester_id, target_id FROM req
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+		RETURNING follower_id
+	`
+	var ids []int64
+	if err := tx.SelectContext(ctx, &ids, query, targetID); err != nil {
+		return nil, fmt.Errorf("failed to accept follow requests: %w", err)
+	}
+	return ids, nil
+}
+
+// GetRequests retrieves users with a pending request to the specified user, newest first.
+// Uses the same created_at cursor as GetFollowers.
+func (r *followRepository) GetRequests(ctx context.Context, targetID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error) {
+	var query string
+	var args []interface{}
+
+	if cursor == nil {
+		query = `
+			SELECT u.id, u.username, u.display_name, u.avatar_url, fr.created_at
+			FROM follow_requests fr
+			JOIN users u ON u.id = fr.requester_id
+			WHERE fr.target_id = $1
+			ORDER BY fr.created_at DESC
+			LIMIT $2
+		`
+		args = []interface{}{targetID, limit + 1}
+	} else {
+		query = `
+			SELECT u.id, u.username, u.display_name, u.avatar_url, fr.created_at
+			FROM follow_requests fr
+			JOIN users u ON u.id = fr.requester_id
+			WHERE fr.target_id = $1 AND fr.created_at < $2
+			ORDER BY fr.created_at DESC
+			LIMIT $3
+		`
+		args = []interface{}{targetID, cursor, limit + 1}
+	}
+
+	type userWithTime struct {
+		model.UserSummary
+		CreatedAt time.Time `db:"created_at"`
+	}
+
+	var results []userWithTime
+	err := r.db.SelectContext(ctx, &results, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get follow requests: %w", err)
+	}
+
+	var users []model.UserSummary
+	var nextCursor *time.Time
+
+	if len(results) > limit {
+		results = results[:limit]
+		nextCursor = &results[len(results)-1].CreatedAt
+	}
+
+	for _, result := range results {
+		users = append(users, result.UserSummary)
+	}
+
+	return users, nextCursor, nil
+}
+
+func (r *followRepository) DeleteRequestsBetween(ctx context.Context, tx *sqlx.Tx, userID, otherID int64) error {
+	query := `
+		DELETE FROM follow_requests
+		WHERE (requester_id = $1 AND target_id = $2) OR (requester_id = $2 AND target_id = $1)
+	`
+	if _, err := tx.ExecContext(ctx, query, userID, otherID); err != nil {
+		return fmt.Errorf("failed to delete follow requests: %w", err)
+	}
+	return nil
+}
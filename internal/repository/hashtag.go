@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"iamstagram_22520060/internal/model"
+)
+
+type hashtagRepository struct {
+	db *sqlx.DB
+}
+
+func NewHashtagRepository(db *sqlx.DB) HashtagRepository {
+	return &hashtagRepository{db: db}
+}
+
+// SetPostHashtags makes the post's hashtags exactly `names`.
+// Removed hashtags are detached and decremented; new ones are upserted, attached and incremented.
+func (r *hashtagRepository) SetPostHashtags(ctx context.Context, tx *sqlx.Tx, postID int64, names []string) error {
+	// Detach hashtags no longer in the caption
+	var removed []int64
+	err := tx.SelectContext(ctx, &removed, `
+		DELETE FROM post_hashtags ph
+		USING hashtags h
+		WHERE ph.hashtag_id = h.id AND ph.post_id = $1 AND NOT (h.name = ANY($2))
+		RETURNING ph.hashtag_id
+	`, postID, pq.Array(names))
+	if err != nil {
+		return fmt.Errorf("detach hashtags: %w", err)
+	}
+	if len(removed) > 0 {
+		_, err = tx.ExecContext(ctx, `UPDATE hashtags SET post_count = post_count - 1 WHERE id = ANY($1)`, pq.Array(removed))
+		if err != nil {
+			return fmt.Errorf("decrement hashtag counts: %w", err)
+		}
+	}
+
+	for _, name := range names {
+		var hashtagID int64
+		// DO UPDATE (no-op) so RETURNING yields the id for existing rows too
+		err := tx.GetContext(ctx, &hashtagID, `
+			INSERT INTO hashtags (name) VALUES ($1)
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		`, name)
+		if err != nil {
+			return fmt.Errorf("upsert hashtag: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO post_hashtags (post_id, hashtag_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, postID, hashtagID)
+		if err != nil {
+			return fmt.Errorf("attach hashtag: %w", err)
+		}
+		attached, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if attached == 0 {
+			continue // Already on this post
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE hashtags SET post_count = post_count + 1 WHERE id = $1`, hashtagID)
+		if err != nil {
+			return fmt.Errorf("increment hashtag count: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DetachPost removes all hashtags from a post and decrements their counts.
+func (r *hashtagRepository) DetachPost(ctx context.Context, tx *sqlx.Tx, postID int64) error {
+	return r.SetPostHashtags(ctx, tx, postID, []string{})
+}
+
+// GetByName returns a hashtag by its normalized name.
+func (r *hashtagRepository) GetByName(ctx context.Context, name string) (*model.Hashtag, error) {
+	var h model.Hashtag
+	err := r.db.GetContext(ctx, &h, `SELECT id, name, post_count, created_at FROM hashtags WHERE name = $1`, name)
+	if err == sql.ErrNoRows {
+		return nil, model.ErrHashtagNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get hashtag: %w", err)
+	}
+	return &h, nil
+}
+
+// GetByNames returns hashtags for the given names (missing names are omitted).
+func (r *hashtagRepository) GetByNames(ctx context.Context, names []string) ([]model.Hashtag, error) {
+	if len(names) == 0 {
+		return []model.Hashtag{}, nil
+	}
+
+	var hashtags []model.Hashtag
+	err := r.db.SelectContext(ctx, &hashtags, `
+		SELECT id, name, post_count, created_at FROM hashtags WHERE name = ANY($1)
+	`, pq.Array(names))
+	if err != nil {
+		return nil, fmt.Errorf("get hashtags: %w", err)
+	}
+	return hashtags, nil
+}
+
+// Search returns hashtags starting with prefix, most used first.
+func (r *hashtagRepository) Search(ctx context.Context, prefix string, limit int) ([]model.Hashtag, error) {
+	// Escape LIKE wildcards; hashtag names never contain them but the raw query might
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+
+	var hashtags []model.Hashtag
+	err := r.db.SelectContext(ctx, &hashtags, `
+		SELECT id, name, post_count, created_at
+		FROM hashtags
+		WHERE name LIKE $1 AND post_count > 0
+		ORDER BY post_count DESC, name
+		LIMIT $2
+	`, escaped+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("search hashtags: %w", err)
+	}
+	return hashtags, nil
+}
+
+// GetPostThumbnails returns thumbnails of posts using a hashtag, newest post first.
+// Posts of private accounts only show up for the viewer if they follow the author.
+func (r *hashtagRepository) GetPostThumbnails(ctx context.Context, hashtagID, viewerID int64, cursor *string, limit int) ([]model.PostThumbnail, *string, error) {
+	var query string
+	var args []interface{}
+
+	if cursor == nil {
+		query = `
+			SELECT ` + postThumbnailColumns + `
+			FROM post_hashtags ph
+			JOIN posts p ON p.id = ph.post_id
+			WHERE ph.hashtag_id = $1 AND p.deleted_at IS NULL
+			  AND ` + visiblePost("p", "$2") + `
+			ORDER BY p.created_at DESC, p.id DESC
+			LIMIT $3
+		`
+		args = []interface{}{hashtagID, viewerID, limit + 1}
+	} else {
+		ts, id, err := parseCursor(*cursor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		query = `
+			SELECT ` + postThumbnailColumns + `
+			FROM post_hashtags ph
+			JOIN posts p ON p.id = ph.post_id
+			WHERE ph.hashtag_id = $1 AND p.deleted_at IS NULL
+			  AND ` + visiblePost("p", "$2") + `
+			  AND (p.created_at, p.id) < ($3, $4)
+			ORDER BY p.created_at DESC, p.id DESC
+			LIMIT $5
+		`
+		args = []interface{}{hashtagID, viewerID, ts, id, limit + 1}
+	}
+
+	var thumbnails []model.PostThumbnail
+	if err := r.db.SelectContext(ctx, &thumbnails, query, args...); err != nil {
+		return nil, nil, fmt.Errorf("get hashtag thumbnails: %w", err)
+	}
+
+	var nextCursor *string
+	if len(thumbnails) > limit {
+		thumbnails = thumbnails[:limit]
+		last := thumbnails[len(thumbnails)-1]
+		c := formatCursor(last.CreatedAt, last.ID)
+		nextCursor = &c
+	}
+
+	return thumbnails, nextCursor, nil
+}
+
+// GetUsageCounts counts hashtag usage on live posts since baselineSince,
+// split into the recent window (>= recentSince) and the baseline before it.
+// Only hashtags with at least minRecent recent uses are returned.
+func (r *hashtagRepository) GetUsageCounts(ctx context.Context, recentSince, baselineSince time.Time, minRecent int) ([]model.HashtagUsage, error) {
+	query := `
+		SELECT h.name,
+		       COUNT(*) FILTER (WHERE ph.created_at >= $1) AS recent,
+		       COUNT(*) FILTER (WHERE ph.created_at < $1) AS baseline
+		FROM post_hashtags ph
+		JOIN hashtags h ON h.id = ph.hashtag_id
+		JOIN posts p ON p.id = ph.post_id AND p.deleted_at IS NULL
+		WHERE ph.created_at >= $2
+		GROUP BY h.name
+		HAVING COUNT(*) FILTER (WHERE ph.created_at >= $1) >= $3
+	`
+	var usage []model.HashtagUsage
+	if err := r.db.SelectContext(ctx, &usage, query, recentSince, baselineSince, minRecent); err != nil {
+		return nil, fmt.Errorf("get hashtag usage: %w", err)
+	}
+	return usage, nil
+}
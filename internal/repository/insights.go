@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"iamstagram_22520060/internal/cache"
+	"iamstagram_22520060/internal/model"
+)
+
+type insightsRepository struct {
+	db *sqlx.DB
+}
+
+func NewInsightsRepository(db *sqlx.DB) InsightsRepository {
+	return &insightsRepository{db: db}
+}
+
+// AddCounters adds rolled-up counters to a post's insights and stores the latest reach estimate.
+// Reach never goes down, so an expired HLL doesn't wipe the stored value.
+func (r *insightsRepository) AddCounters(ctx context.Context, postID int64, counters cache.InsightCounters, reach int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO post_insights (post_id, impressions_feed, impressions_profile, impressions_other, profile_visits, reach)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (post_id) DO UPDATE SET
+			impressions_feed = post_insights.impressions_feed + EXCLUDED.impressions_feed,
+			impressions_profile = post_insights.impressions_profile + EXCLUDED.impressions_profile,
+			impressions_other = post_insights.impressions_other + EXCLUDED.impressions_other,
+			profile_visits = post_insights.profile_visits + EXCLUDED.profile_visits,
+			reach = GREATEST(post_insights.reach, EXCLUDED.reach),
+			updated_at = NOW()
+	`, postID, counters.ImpressionsFeed, counters.ImpressionsProfile, counters.ImpressionsOther, counters.ProfileVisits, reach)
+	if err != nil {
+		return fmt.Errorf("upsert post insights: %w", err)
+	}
+	return nil
+}
+
+// GetPostInsights returns the insights of a live post, with zeros if nothing was rolled up yet.
+func (r *insightsRepository) GetPostInsights(ctx context.Context, postID int64) (*model.PostInsights, error) {
+	var insights model.PostInsights
+	err := r.db.GetContext(ctx, &insights, `
+		SELECT p.id as post_id, p.user_id,
+		       p.like_count as likes, p.comment_count as comments, p.repost_count as reposts,
+		       (SELECT COUNT(*) FROM saved_posts s WHERE s.post_id = p.id) as saves,
+		       COALESCE(i.impressions_feed + i.impressions_profile + i.impressions_other, 0) as impressions,
+		       COALESCE(i.impressions_feed, 0) as impressions_feed,
+		       COALESCE(i.impressions_profile, 0) as impressions_profile,
+		       COALESCE(i.impressions_other, 0) as impressions_other,
+		       COALESCE(i.reach, 0) as reach,
+		       COALESCE(i.profile_visits, 0) as profile_visits,
+		       i.updated_at
+		FROM posts p
+		LEFT JOIN post_insights i ON i.post_id = p.id
+		WHERE p.id = $1 AND p.deleted_at IS NULL
+	`, postID)
+	if err == sql.ErrNoRows {
+		return nil, model.ErrPostNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get post insights: %w", err)
+	}
+	return &insights, nil
+}
@@ -14,11 +14,94 @@ type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	GetByID(ctx context.Context, id int64) (*model.User, error)
 	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
-	Search(ctx context.Context, query string, limit int) ([]model.UserSummary, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	// UpdateEmail sets (or with nil, removes) the email and resets email_verified
+	UpdateEmail(ctx context.Context, userID int64, email *string) error
+	// MarkEmailVerified verifies the user's email if it still equals email; returns false otherwise.
+	// Returns ErrEmailExists if another account verified the address first.
+	MarkEmailVerified(ctx context.Context, userID int64, email string) (bool, error)
+	// Search matches usernames by prefix, leaving out users blocked by or blocking the viewer (0 if anonymous)
+	Search(ctx context.Context, query string, viewerID int64, limit int) ([]model.UserSummary, error)
 	IncrementFollowerCount(ctx context.Context, tx *sqlx.Tx, userID int64, delta int) error
 	IncrementFollowingCount(ctx context.Context, tx *sqlx.Tx, userID int64, delta int) error
 	SetIsNewUser(ctx context.Context, userID int64, isNew bool) error
+	// UpdateProfile sets display_name and bio (nil clears them)
+	UpdateProfile(ctx context.Context, userID int64, displayName, bio *string) error
+	// UpdateAvatar swaps the avatar and returns the previous avatar_key
+	UpdateAvatar(ctx context.Context, userID int64, avatarURL, avatarKey string) (oldKey string, err error)
+	UpdatePassword(ctx context.Context, userID int64, passwordHashed string) error
+	UpdatePasswordTx(ctx context.Context, tx *sqlx.Tx, userID int64, passwordHashed string) error
+	SetPrivate(ctx context.Context, tx *sqlx.Tx, userID int64, private bool) error
+	// ScheduleDeletion marks the account for the purge job after at
+	ScheduleDeletion(ctx context.Context, userID int64, at time.Time) error
+	// CancelDeletion clears a pending deletion (no-op if none is scheduled)
+	CancelDeletion(ctx context.Context, userID int64) error
+	// ChangeUsername renames the user and records the old handle in username_history
+	ChangeUsername(ctx context.Context, userID int64, username string) error
+	CountUsernameChangesSince(ctx context.Context, userID int64, since time.Time) (int, error)
+	// IsUsernameHeld reports whether username (in any case) was released by another user after since
+	IsUsernameHeld(ctx context.Context, username string, exceptUserID int64, since time.Time) (bool, error)
+	// GetByPreviousUsername returns the user who released username (in any case) after since
+	GetByPreviousUsername(ctx context.Context, username string, since time.Time) (*model.User, error)
+	// GetSummariesByIDs returns summaries for the given users (missing IDs are omitted)
+	GetSummariesByIDs(ctx context.Context, userIDs []int64) ([]model.UserSummary, error)
+	// GetSummariesByUsernames returns summaries for the given usernames (unknown names are omitted)
+	GetSummariesByUsernames(ctx context.Context, usernames []string) ([]model.UserSummary, error)
+}
+
+type AccountRepository interface {
+	// GetDueDeletions returns accounts whose scheduled deletion time has passed
+	GetDueDeletions(ctx context.Context, limit int) ([]int64, error)
+	// GetLivePostIDs returns the IDs of a user's non-deleted posts
+	GetLivePostIDs(ctx context.Context, userID int64) ([]int64, error)
+	// GetMediaKeys returns every R2 object owned by a user (uploads, video thumbnails, avatar, exports)
+	GetMediaKeys(ctx context.Context, userID int64) ([]string, error)
+	// Purge deletes the user and everything they own, fixing counters on other users' rows.
+	// Returns false if the deletion was cancelled or isn't due anymore.
+	Purge(ctx context.Context, tx *sqlx.Tx, userID int64) (bool, error)
+}
+
+type ExportRepository interface {
+	// Create records a pending export; ErrExportInProgress if one is already pending
+	Create(ctx context.Context, userID int64) (*model.DataExport, error)
+	GetByID(ctx context.Context, exportID int64) (*model.DataExport, error)
+	// GetLatest returns the user's most recent export (ErrExportNotFound if none)
+	GetLatest(ctx context.Context, userID int64) (*model.DataExport, error)
+	// MarkReady finishes a pending export; ErrExportNotPending if it was failed in the meantime
+	MarkReady(ctx context.Context, exportID int64, objectKey string, sizeBytes int64, expiresAt time.Time) error
+	MarkFailed(ctx context.Context, exportID int64) error
+	// GetExpired returns ready exports whose archive has passed expires_at
+	GetExpired(ctx context.Context, limit int) ([]model.DataExport, error)
+	MarkExpired(ctx context.Context, exportID int64) error
+	// FailStale marks exports still pending since before olderThan as failed
+	FailStale(ctx context.Context, olderThan time.Time) (int64, error)
+	// Archive contents
+	GetPosts(ctx context.Context, userID int64) ([]model.ExportPost, error)
+	GetComments(ctx context.Context, userID int64) ([]model.ExportComment, error)
+	GetLikes(ctx context.Context, userID int64) ([]model.ExportLike, error)
+	GetFollowers(ctx context.Context, userID int64) ([]model.ExportFollow, error)
+	GetFollowing(ctx context.Context, userID int64) ([]model.ExportFollow, error)
+	GetNotifications(ctx context.Context, userID int64) ([]model.ExportNotification, error)
+}
+
+type EmailVerificationRepository interface {
+	// Create stores a new token and invalidates the user's previous unused ones
+	Create(ctx context.Context, token *model.EmailVerificationToken) error
+	// FindValid returns an unused, unexpired token by hash
+	FindValid(ctx context.Context, tokenHash string) (*model.EmailVerificationToken, error)
+	// MarkUsed consumes the token; returns ErrVerificationTokenInvalid if it was already used
+	MarkUsed(ctx context.Context, id int64) error
+}
+
+type PasswordResetRepository interface {
+	// Create stores a new token and invalidates the user's previous unused ones
+	Create(ctx context.Context, token *model.PasswordResetToken) error
+	// FindValid returns an unused, unexpired token by hash
+	FindValid(ctx context.Context, tokenHash string) (*model.PasswordResetToken, error)
+	// MarkUsed consumes the token; returns ErrResetTokenInvalid if it was already used
+	MarkUsed(ctx context.Context, tx *sqlx.Tx, id int64) error
 }
 
 type RefreshTokenRepository interface {
@@ -26,6 +109,7 @@ type RefreshTokenRepository interface {
 	FindByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
 	Revoke(ctx context.Context, id string, replacedBy *string) error
 	RevokeAllForUser(ctx context.Context, userID int64) error
+	RevokeAllForUserTx(ctx context.Context, tx *sqlx.Tx, userID int64) error
 	DeleteExpired(ctx context.Context, olderThan time.Duration) (int64, error)
 }
 
@@ -33,47 +117,201 @@ type FollowRepository interface {
 	Create(ctx context.Context, tx *sqlx.Tx, followerID, followeeID int64) (bool, error)
 	Delete(ctx context.Context, tx *sqlx.Tx, followerID, followeeID int64) error
 	Exists(ctx context.Context, followerID, followeeID int64) (bool, error)
-	GetFollowers(ctx context.Context, userID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error)
-	GetFollowing(ctx context.Context, userID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error)
+	// GetFollowers and GetFollowing leave out users blocked by or blocking the viewer (0 if anonymous)
+	GetFollowers(ctx context.Context, userID, viewerID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error)
+	GetFollowing(ctx context.Context, userID, viewerID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error)
 	CheckFollows(ctx context.Context, followerID int64, followeeIDs []int64) (map[int64]bool, error)
 	// New methods for feed system
 	GetFollowerIDs(ctx context.Context, userID int64) ([]int64, error)
 	GetFolloweeIDs(ctx context.Context, userID int64) ([]int64, error)
+	// Follow requests to private accounts
+	CreateRequest(ctx context.Context, requesterID, targetID int64) (bool, error)
+	DeleteRequest(ctx context.Context, requesterID, targetID int64) error
+	RequestExists(ctx context.Context, requesterID, targetID int64) (bool, error)
+	AcceptRequest(ctx context.Context, tx *sqlx.Tx, requesterID, targetID int64) error
+	AcceptAllRequests(ctx context.Context, tx *sqlx.Tx, targetID int64) ([]int64, error)
+	GetRequests(ctx context.Context, targetID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error)
+	// DeleteRequestsBetween drops pending requests in both directions
+	DeleteRequestsBetween(ctx context.Context, tx *sqlx.Tx, userID, otherID int64) error
+}
+
+type BlockRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, blockerID, blockedID int64) (bool, error)
+	Delete(ctx context.Context, blockerID, blockedID int64) error
+	// IsBlocked reports whether either user blocked the other
+	IsBlocked(ctx context.Context, userID, otherID int64) (bool, error)
+	// GetBlockedIDs returns users the user blocked or was blocked by
+	GetBlockedIDs(ctx context.Context, userID int64) ([]int64, error)
+	GetBlocked(ctx context.Context, blockerID int64, cursor *time.Time, limit int) ([]model.UserSummary, *time.Time, error)
 }
 
 type PostRepository interface {
-	Create(ctx context.Context, userID int64, caption *string, mediaURLs []string) (*model.Post, error)
+	Create(ctx context.Context, tx *sqlx.Tx, userID int64, caption *string, settings model.PostSettings, media []model.PostMediaInput) (*model.Post, error)
 	GetByID(ctx context.Context, postID int64) (*model.Post, error)
 	GetByIDs(ctx context.Context, postIDs []int64) ([]model.Post, error)
-	Delete(ctx context.Context, postID, userID int64) error
+	UpdateCaption(ctx context.Context, tx *sqlx.Tx, postID int64, caption *string) error
+	// UpdateSettings changes per-post settings, nil values are left unchanged (ErrPostNotFound if deleted)
+	UpdateSettings(ctx context.Context, tx *sqlx.Tx, postID int64, hideLikeCount, commentsDisabled *bool) error
+	// UpdateMediaMetadata stores metadata computed after the post was created (ErrPostNotFound if deleted)
+	UpdateMediaMetadata(ctx context.Context, mediaID int64, m *model.PostMediaInput) error
+	// CommentsDisabled reports whether comments are turned off (ErrPostNotFound if deleted)
+	CommentsDisabled(ctx context.Context, postID int64) (bool, error)
+	// GetPinnedCommentID returns the pinned comment, nil if none (ErrPostNotFound if deleted)
+	GetPinnedCommentID(ctx context.Context, postID int64) (*int64, error)
+	// SetPinnedComment pins a comment, replacing any previous pin
+	SetPinnedComment(ctx context.Context, postID, commentID int64) error
+	// UnpinComment removes the pin if commentID is pinned (ErrCommentNotPinned otherwise)
+	UnpinComment(ctx context.Context, postID, commentID int64) error
+	Delete(ctx context.Context, tx *sqlx.Tx, postID, userID int64) error
 	GetUserThumbnails(ctx context.Context, userID int64, cursor *string, limit int) ([]model.PostThumbnail, *string, error)
 	GetRecentPostsByUser(ctx context.Context, userID int64, limit int) ([]cache.PostScore, error)
 	GetFeedPostIDs(ctx context.Context, followeeIDs []int64, limit int) ([]cache.PostScore, error)
 	GetAuthorID(ctx context.Context, postID int64) (int64, error)
+	// GetAuthorIDs returns post_id -> author for live posts (missing or deleted posts are omitted)
+	GetAuthorIDs(ctx context.Context, postIDs []int64) (map[int64]int64, error)
 	// CheckLikes checks which posts the user has liked
 	CheckLikes(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error)
+	// CheckSaves checks which posts the user has saved
+	CheckSaves(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error)
+	// CheckReposts checks which posts the user has reposted
+	CheckReposts(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error)
 	// Like methods
 	Like(ctx context.Context, tx *sqlx.Tx, postID, userID int64) error
 	Unlike(ctx context.Context, tx *sqlx.Tx, postID, userID int64) error
-	GetPostLikers(ctx context.Context, postID int64, cursor *string, limit int) ([]model.UserSummary, *string, error)
+	GetPostLikers(ctx context.Context, postID, viewerID int64, cursor *string, limit int) ([]model.UserSummary, *string, error)
 	IncrementLikeCount(ctx context.Context, tx *sqlx.Tx, postID int64, delta int) error
 	IncrementCommentCount(ctx context.Context, tx *sqlx.Tx, postID int64, delta int) error
+	IncrementRepostCount(ctx context.Context, tx *sqlx.Tx, postID int64, delta int) error
 	// Exists checks if a post exists (not deleted)
 	Exists(ctx context.Context, postID int64) (bool, error)
+	// IsVisible reports whether the post is live and visible to the viewer (0 if anonymous)
+	IsVisible(ctx context.Context, postID, viewerID int64) (bool, error)
+}
+
+type MediaRepository interface {
+	// CreateUpload records a presigned post upload issued to a user
+	CreateUpload(ctx context.Context, userID int64, objectKey, contentType string) error
+	// GetUploadsByKeys returns upload records for the given object keys (missing keys are omitted)
+	GetUploadsByKeys(ctx context.Context, objectKeys []string) ([]model.MediaUpload, error)
+}
+
+type TagRepository interface {
+	// GetMediaPostID returns the post a media item belongs to
+	GetMediaPostID(ctx context.Context, mediaID int64) (int64, error)
+	// GetTaggedUserIDsByPost returns every user tagged anywhere in a post
+	GetTaggedUserIDsByPost(ctx context.Context, postID int64) ([]int64, error)
+	// ReplaceMediaTags swaps the full tag set of a media item
+	ReplaceMediaTags(ctx context.Context, tx *sqlx.Tx, mediaID int64, tags []model.MediaTagInput) error
+	// DeleteUserTagsOnPost removes a tagged user from every media item of a post
+	DeleteUserTagsOnPost(ctx context.Context, postID, userID int64) error
+	// GetTaggedThumbnails returns posts a user is tagged in (profile "tagged" tab) that the viewer may see
+	GetTaggedThumbnails(ctx context.Context, userID, viewerID int64, cursor *string, limit int) ([]model.PostThumbnail, *string, error)
+}
+
+type HashtagRepository interface {
+	// SetPostHashtags makes the post's hashtags exactly `names`, keeping post_count in sync
+	SetPostHashtags(ctx context.Context, tx *sqlx.Tx, postID int64, names []string) error
+	// DetachPost removes all hashtags from a (deleted) post
+	DetachPost(ctx context.Context, tx *sqlx.Tx, postID int64) error
+	GetByName(ctx context.Context, name string) (*model.Hashtag, error)
+	GetByNames(ctx context.Context, names []string) ([]model.Hashtag, error)
+	// Search returns hashtags starting with prefix, most used first
+	Search(ctx context.Context, prefix string, limit int) ([]model.Hashtag, error)
+	// GetPostThumbnails returns thumbnails of posts using a hashtag that the viewer may see, newest first
+	GetPostThumbnails(ctx context.Context, hashtagID, viewerID int64, cursor *string, limit int) ([]model.PostThumbnail, *string, error)
+	// GetUsageCounts returns per-hashtag usage since baselineSince, split at recentSince
+	GetUsageCounts(ctx context.Context, recentSince, baselineSince time.Time, minRecent int) ([]model.HashtagUsage, error)
+}
+
+type MentionRepository interface {
+	// ReplacePostMentions swaps the caption mentions of a post
+	ReplacePostMentions(ctx context.Context, tx *sqlx.Tx, postID int64, mentions []model.Mention) error
+	// ReplaceCommentMentions swaps the mentions of a comment
+	ReplaceCommentMentions(ctx context.Context, tx *sqlx.Tx, postID, commentID int64, mentions []model.Mention) error
+	// GetCommentMentions returns mentions keyed by comment ID
+	GetCommentMentions(ctx context.Context, commentIDs []int64) (map[int64][]model.Mention, error)
+}
+
+type SavedRepository interface {
+	// Save bookmarks a post, returns false if it was already saved
+	Save(ctx context.Context, tx *sqlx.Tx, userID, postID int64) (bool, error)
+	// Unsave removes a bookmark and takes the post out of the user's collections
+	Unsave(ctx context.Context, tx *sqlx.Tx, userID, postID int64) error
+	GetSavedPostIDs(ctx context.Context, userID int64, cursor *string, limit int) ([]int64, *string, error)
+	// Collection methods (lookups are scoped to the owner)
+	CreateCollection(ctx context.Context, userID int64, name string) (*model.Collection, error)
+	GetCollection(ctx context.Context, collectionID, userID int64) (*model.Collection, error)
+	GetCollections(ctx context.Context, userID int64) ([]model.Collection, error)
+	CountCollections(ctx context.Context, userID int64) (int, error)
+	RenameCollection(ctx context.Context, collectionID, userID int64, name string) error
+	DeleteCollection(ctx context.Context, collectionID, userID int64) error
+	AddToCollection(ctx context.Context, tx *sqlx.Tx, collectionID, postID int64) error
+	RemoveFromCollection(ctx context.Context, collectionID, postID int64) error
+	GetCollectionPostIDs(ctx context.Context, collectionID int64, cursor *string, limit int) ([]int64, *string, error)
+}
+
+type RepostRepository interface {
+	// Create records a repost, returns false if the user already reposted the post
+	Create(ctx context.Context, tx *sqlx.Tx, userID, postID int64) (bool, error)
+	Delete(ctx context.Context, tx *sqlx.Tx, userID, postID int64) error
+	// GetReposterIDs returns everyone who reposted a post (deleted posts included)
+	GetReposterIDs(ctx context.Context, postID int64) ([]int64, error)
+	// GetRecentRepostsByUser returns a user's recent reposts (for follow backfill)
+	GetRecentRepostsByUser(ctx context.Context, userID int64, limit int) ([]cache.PostScore, error)
+	// GetFeedReposts returns reposts by the given users for cache warming
+	GetFeedReposts(ctx context.Context, reposterIDs []int64, limit int) ([]cache.RepostScore, error)
+	// DeleteByAuthor removes every repost of the author's posts and resets their repost counts
+	DeleteByAuthor(ctx context.Context, tx *sqlx.Tx, authorID int64) ([]cache.RepostScore, error)
+}
+
+type InsightsRepository interface {
+	// AddCounters adds rolled-up counters and stores the latest reach estimate
+	AddCounters(ctx context.Context, postID int64, counters cache.InsightCounters, reach int64) error
+	// GetPostInsights returns the insights of a live post (ErrPostNotFound otherwise)
+	GetPostInsights(ctx context.Context, postID int64) (*model.PostInsights, error)
 }
 
 type CommentRepository interface {
-	Create(ctx context.Context, tx *sqlx.Tx, postID, userID int64, content string, parentID *int64) (*model.Comment, error)
-	Update(ctx context.Context, commentID, userID int64, content string) (*model.Comment, error)
+	Create(ctx context.Context, tx *sqlx.Tx, postID, userID int64, content string, parentID *int64, hiddenReason *string) (*model.Comment, error)
+	Update(ctx context.Context, tx *sqlx.Tx, commentID, userID int64, content string, hiddenReason *string) (*model.Comment, error)
 	Delete(ctx context.Context, tx *sqlx.Tx, commentID, userID int64) (postID int64, deletedCount int, err error)
-	GetByPostID(ctx context.Context, postID int64, cursor *string, limit int) ([]model.Comment, *string, error)
+	// List methods only return comments visible to viewerID (0 = anonymous):
+	// hidden comments are seen by their author and the post author only.
+	// GetByPostID returns top-level comments only, newest first
+	GetByPostID(ctx context.Context, postID, viewerID int64, cursor *string, limit int) ([]model.Comment, *string, error)
+	// GetReplies returns replies of a comment, oldest first
+	GetReplies(ctx context.Context, parentID, viewerID int64, cursor *string, limit int) ([]model.Comment, *string, error)
+	// GetReplyPreviews returns the first perParent replies of each parent, keyed by parent ID
+	GetReplyPreviews(ctx context.Context, parentIDs []int64, viewerID int64, perParent int) (map[int64][]model.Comment, error)
+	// GetHidden returns the hidden comments of a post, newest first
+	GetHidden(ctx context.Context, postID, viewerID int64, cursor *string, limit int) ([]model.Comment, *string, error)
 	GetByID(ctx context.Context, commentID int64) (*model.Comment, error)
+	// GetWithAuthor returns a comment in the list shape (author, reply count, mentions)
+	GetWithAuthor(ctx context.Context, commentID, viewerID int64) (*model.Comment, error)
+	// Unhide makes a hidden comment visible (ErrCommentNotHidden if it isn't hidden)
+	Unhide(ctx context.Context, postID, commentID int64) error
+	// CountRecentDuplicatePosts counts other posts where the user left the same text since a time
+	CountRecentDuplicatePosts(ctx context.Context, userID, postID int64, content string, since time.Time) (int, error)
+	// GetEdits returns previous versions of a comment, most recent first
+	GetEdits(ctx context.Context, commentID int64) ([]model.CommentEdit, error)
+	// Like methods
+	Like(ctx context.Context, tx *sqlx.Tx, commentID, userID int64) error
+	Unlike(ctx context.Context, tx *sqlx.Tx, commentID, userID int64) error
+	IncrementLikeCount(ctx context.Context, tx *sqlx.Tx, commentID int64, delta int) error
+	// CheckLikes checks which comments the user has liked
+	CheckLikes(ctx context.Context, userID int64, commentIDs []int64) (map[int64]bool, error)
+}
+
+type CommentFilterRepository interface {
+	// Get returns a user's comment filters (defaults if never set)
+	Get(ctx context.Context, userID int64) (*model.CommentFilterSettings, error)
+	Upsert(ctx context.Context, settings *model.CommentFilterSettings) error
 }
 
 type NotificationRepository interface {
 	// Create inserts a new notification
 	Create(ctx context.Context, userID, actorID int64, notifType string, postID, commentID *int64) error
-	// GetFollowNotifications returns non-aggregated follow notifications + unread count
+	// GetFollowNotifications returns non-aggregated notifications (follows, data exports) + unread count
 	GetFollowNotifications(ctx context.Context, userID int64, limit int) ([]model.Notification, error, int)
 	// GetAggregatedNotifications returns likes/comments grouped by post + unread count
 	GetAggregatedNotifications(ctx context.Context, userID int64, limit int) ([]model.AggregatedNotification, error, int)
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"iamstagram_22520060/internal/model"
+)
+
+type mediaRepository struct {
+	db *sqlx.DB
+}
+
+func NewMediaRepository(db *sqlx.DB) MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+// CreateUpload records a presigned upload so the key can later be verified on post creation.
+func (r *mediaRepository) CreateUpload(ctx context.Context, userID int64, objectKey, contentType string) error {
+	query := `
+		INSERT INTO media_uploads (user_id, object_key, content_type)
+		VALUES ($1, $2, $3)
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, objectKey, contentType)
+	if err != nil {
+		return fmt.Errorf("insert media upload: %w", err)
+	}
+	return nil
+}
+
+// GetUploadsByKeys returns the upload records for the given object keys.
+func (r *mediaRepository) GetUploadsByKeys(ctx context.Context, objectKeys []string) ([]model.MediaUpload, error) {
+	if len(objectKeys) == 0 {
+		return []model.MediaUpload{}, nil
+	}
+
+	query := `
+		SELECT id, user_id, object_key, content_type, post_id, created_at, used_at
+		FROM media_uploads
+		WHERE object_key = ANY($1)
+	`
+	var uploads []model.MediaUpload
+	err := r.db.SelectContext(ctx, &uploads, query, pq.Array(objectKeys))
+	if err != nil {
+		return nil, fmt.Errorf("get media uploads: %w", err)
+	}
+	return uploads, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"iamstagram_22520060/internal/model"
+)
+
+type mentionRepository struct {
+	db *sqlx.DB
+}
+
+func NewMentionRepository(db *sqlx.DB) MentionRepository {
+	return &mentionRepository{db: db}
+}
+
+// ReplacePostMentions swaps the caption mentions of a post.
+func (r *mentionRepository) ReplacePostMentions(ctx context.Context, tx *sqlx.Tx, postID int64, mentions []model.Mention) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mentions WHERE post_id = $1 AND comment_id IS NULL`, postID); err != nil {
+		return fmt.Errorf("delete post mentions: %w", err)
+	}
+	return insertMentions(ctx, tx, postID, nil, mentions)
+}
+
+// ReplaceCommentMentions swaps the mentions of a comment.
+func (r *mentionRepository) ReplaceCommentMentions(ctx context.Context, tx *sqlx.Tx, postID, commentID int64, mentions []model.Mention) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mentions WHERE comment_id = $1`, commentID); err != nil {
+		return fmt.Errorf("delete comment mentions: %w", err)
+	}
+	return insertMentions(ctx, tx, postID, &commentID, mentions)
+}
+
+// GetCommentMentions returns mentions keyed by comment ID.
+func (r *mentionRepository) GetCommentMentions(ctx context.Context, commentIDs []int64) (map[int64][]model.Mention, error) {
+	return getCommentMentions(ctx, r.db, commentIDs)
+}
+
+// insertMentions inserts mentions for a caption (commentID nil) or a comment.
+func insertMentions(ctx context.Context, tx *sqlx.Tx, postID int64, commentID *int64, mentions []model.Mention) error {
+	query := `
+		INSERT INTO mentions (post_id, comment_id, mentioned_user_id, start_offset, length)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, m := range mentions {
+		if _, err := tx.ExecContext(ctx, query, postID, commentID, m.UserID, m.Offset, m.Length); err != nil {
+			return fmt.Errorf("insert mention: %w", err)
+		}
+	}
+	return nil
+}
+
+// getPostMentions loads caption mentions for posts, keyed by post ID, in text order.
+func getPostMentions(ctx context.Context, db sqlx.QueryerContext, postIDs []int64) (map[int64][]model.Mention, error) {
+	if len(postIDs) == 0 {
+		return map[int64][]model.Mention{}, nil
+	}
+
+	query := `
+		SELECT m.post_id, m.comment_id, m.mentioned_user_id, u.username, m.start_offset, m.length
+		FROM mentions m
+		JOIN users u ON u.id = m.mentioned_user_id
+		WHERE m.post_id = ANY($1) AND m.comment_id IS NULL
+		ORDER BY m.post_id, m.start_offset
+	`
+	var mentions []model.Mention
+	if err := sqlx.SelectContext(ctx, db, &mentions, query, pq.Array(postIDs)); err != nil {
+		return nil, fmt.Errorf("get post mentions: %w", err)
+	}
+
+	result := make(map[int64][]model.Mention)
+	for _, m := range mentions {
+		result[m.PostID] = append(result[m.PostID], m)
+	}
+	return result, nil
+}
+
+// getCommentMentions loads mentions for comments, keyed by comment ID, in text order.
+func getCommentMentions(ctx context.Context, db sqlx.QueryerContext, commentIDs []int64) (map[int64][]model.Mention, error) {
+	if len(commentIDs) == 0 {
+		return map[int64][]model.Mention{}, nil
+	}
+
+	query := `
+		SELECT m.post_id, m.comment_id, m.mentioned_user_id, u.username, m.start_offset, m.length
+		FROM mentions m
+		JOIN users u ON u.id = m.mentioned_user_id
+		WHERE m.comment_id = ANY($1)
+		ORDER BY m.comment_id, m.start_offset
+	`
+	var mentions []model.Mention
+	if err := sqlx.SelectContext(ctx, db, &mentions, query, pq.Array(commentIDs)); err != nil {
+		return nil, fmt.Errorf("get comment mentions: %w", err)
+	}
+
+	result := make(map[int64][]model.Mention)
+	for _, m := range mentions {
+		result[*m.CommentID] = append(result[*m.CommentID], m)
+	}
+	return result, nil
+}
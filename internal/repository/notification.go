@@ -33,7 +33,7 @@ func (r *notificationRepository) Create(ctx context.Context, userID, actorID int
 	return nil
 }
 
-// GetFollowNotifications returns non-aggregated follow notifications with actor info.
+// GetFollowNotifications returns non-aggregated notifications (follows, data exports) with actor info.
 func (r *notificationRepository) GetFollowNotifications(ctx context.Context, userID int64, limit int) ([]model.Notification, error, int) {
 	query := `
 		SELECT n.id, n.user_id, n.actor_id, n.type, n.post_id, n.comment_id, n.is_read, n.created_at,
@@ -41,7 +41,7 @@ func (r *notificationRepository) GetFollowNotifications(ctx context.Context, use
 		       u.display_name as "actor.display_name", u.avatar_url as "actor.avatar_url"
 		FROM notifications n
 		JOIN users u ON u.id = n.actor_id
-		WHERE n.user_id = $1 AND n.type = 'follow'
+		WHERE n.user_id = $1 AND n.type = ANY($3) AND ` + notBlocked("n.actor_id", "$1") + `
 		ORDER BY n.created_at DESC
 		LIMIT $2
 	`
@@ -62,7 +62,7 @@ func (r *notificationRepository) GetFollowNotifications(ctx context.Context, use
 	}
 
 	var rows []notifRow
-	err := r.db.SelectContext(ctx, &rows, query, userID, limit)
+	err := r.db.SelectContext(ctx, &rows, query, userID, limit, pq.Array(model.IndividualNotificationTypes))
 	if err != nil {
 		return nil, fmt.Errorf("get follow notifications: %w", err), 0
 	}
@@ -94,20 +94,22 @@ func (r *notificationRepository) GetFollowNotifications(ctx context.Context, use
 	return notifications, nil, unreadCount
 }
 
-// GetAggregatedNotifications returns likes/comments grouped by post.
+// GetAggregatedNotifications returns likes/comments/tags grouped by post.
+// Per-comment types (comment likes, replies, mentions) are grouped by comment instead.
 func (r *notificationRepository) GetAggregatedNotifications(ctx context.Context, userID int64, limit int) ([]model.AggregatedNotification, error, int) {
-	// First, get aggregated data grouped by type and post
+	// First, get aggregated data grouped by type and post (or comment)
 	query := `
 		SELECT 
 			n.type,
 			n.post_id,
+			CASE WHEN n.type = ANY($4) THEN n.comment_id END as group_comment_id,
 			array_agg(n.actor_id ORDER BY n.created_at DESC) as actor_ids,
 			COUNT(*) as total_count,
 			MAX(n.created_at) as latest_at,
 			bool_and(n.is_read) as is_read
 		FROM notifications n
-		WHERE n.user_id = $1 AND n.type IN ('like', 'comment')
-		GROUP BY n.type, n.post_id
+		WHERE n.user_id = $1 AND n.type = ANY($3) AND ` + notBlocked("n.actor_id", "$1") + `
+		GROUP BY n.type, n.post_id, group_comment_id
 		ORDER BY latest_at DESC
 		LIMIT $2
 	`
@@ -115,6 +117,7 @@ func (r *notificationRepository) GetAggregatedNotifications(ctx context.Context,
 	type aggRow struct {
 		Type       string        `db:"type"`
 		PostID     *int64        `db:"post_id"`
+		CommentID  *int64        `db:"group_comment_id"`
 		ActorIDs   pq.Int64Array `db:"actor_ids"`
 		TotalCount int           `db:"total_count"`
 		LatestAt   time.Time     `db:"latest_at"`
@@ -122,7 +125,8 @@ func (r *notificationRepository) GetAggregatedNotifications(ctx context.Context,
 	}
 
 	var rows []aggRow
-	err := r.db.SelectContext(ctx, &rows, query, userID, limit)
+	err := r.db.SelectContext(ctx, &rows, query, userID, limit,
+		pq.Array(model.AggregatedNotificationTypes), pq.Array(model.PerCommentNotificationTypes))
 	if err != nil {
 		return nil, fmt.Errorf("get aggregated notifications: %w", err), 0
 	}
@@ -187,6 +191,7 @@ func (r *notificationRepository) GetAggregatedNotifications(ctx context.Context,
 		result[i] = model.AggregatedNotification{
 			Type:       row.Type,
 			PostID:     row.PostID,
+			CommentID:  row.CommentID,
 			Actors:     actors,
 			TotalCount: row.TotalCount,
 			LatestAt:   row.LatestAt,
@@ -232,8 +237,8 @@ func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID int64
 // GetUnreadCount returns the count of unread notifications.
 func (r *notificationRepository) GetUnreadCount(ctx context.Context, userID int64) (int, error) {
 	query := `
-		SELECT COUNT(*) FROM notifications
-		WHERE user_id = $1 AND is_read = false
+		SELECT COUNT(*) FROM notifications n
+		WHERE n.user_id = $1 AND n.is_read = false AND ` + notBlocked("n.actor_id", "$1") + `
 	`
 	var count int
 	err := r.db.GetContext(ctx, &count, query, userID)
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"iamstagram_22520060/internal/model"
+)
+
+// oneTimeTokenTable runs the queries shared by the single-use token tables
+// (password_reset_tokens, email_verification_tokens).
+type oneTimeTokenTable struct {
+	name       string // Table name
+	columns    string // Columns selected by findValid, matching the token struct
+	label      string // Used in error messages, e.g. "password reset token"
+	errInvalid error  // Returned for unknown, expired and already used tokens
+}
+
+// create inserts a new token along with any extra columns of the table. Older unused
+// tokens of the user are marked used, so only the latest email works.
+func (t oneTimeTokenTable) create(ctx context.Context, db sqlx.QueryerContext, token *model.OneTimeToken, extraColumns []string, extraValues ...any) error {
+	columns := append([]string{"user_id", "token_hash", "expires_at"}, extraColumns...)
+	args := append([]any{token.UserID, token.TokenHash, token.ExpiresAt}, extraValues...)
+	placeholders := make([]string, len(args))
+	for i := range args {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	query := fmt.Sprintf(`
+		WITH invalidated AS (
+			UPDATE %[1]s SET used_at = NOW()
+			WHERE user_id = $1 AND used_at IS NULL
+		)
+		INSERT INTO %[1]s (%[2]s)
+		VALUES (%[3]s)
+		RETURNING id, created_at
+	`, t.name, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	err := db.QueryRowxContext(ctx, query, args...).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", t.label, err)
+	}
+	return nil
+}
+
+// findValid loads an unused, unexpired token by its hash into dest
+func (t oneTimeTokenTable) findValid(ctx context.Context, db sqlx.QueryerContext, dest any, tokenHash string) error {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+	`, t.columns, t.name)
+	err := sqlx.GetContext(ctx, db, dest, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return t.errInvalid
+		}
+		return fmt.Errorf("failed to find %s: %w", t.label, err)
+	}
+	return nil
+}
+
+// markUsed consumes a token. The used_at check makes concurrent uses race-safe.
+func (t oneTimeTokenTable) markUsed(ctx context.Context, db sqlx.ExecerContext, id int64) error {
+	query := fmt.Sprintf(`UPDATE %s SET used_at = NOW() WHERE id = $1 AND used_at IS NULL`, t.name)
+	result, err := db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark %s used: %w", t.label, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return t.errInvalid
+	}
+	return nil
+}
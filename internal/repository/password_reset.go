@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"iamstagram_22520060/internal/model"
+)
+
+var passwordResetTokens = oneTimeTokenTable{
+	name:       "password_reset_tokens",
+	columns:    "id, user_id, token_hash, expires_at, used_at, created_at",
+	label:      "password reset token",
+	errInvalid: model.ErrResetTokenInvalid,
+}
+
+type passwordResetRepository struct {
+	db *sqlx.DB
+}
+
+// NewPasswordResetRepository creates a new password reset token repository
+func NewPasswordResetRepository(db *sqlx.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create inserts a new reset token and invalidates the user's older ones
+func (r *passwordResetRepository) Create(ctx context.Context, token *model.PasswordResetToken) error {
+	return passwordResetTokens.create(ctx, r.db, &token.OneTimeToken, nil)
+}
+
+// FindValid retrieves an unused, unexpired reset token by its hash
+func (r *passwordResetRepository) FindValid(ctx context.Context, tokenHash string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	if err := passwordResetTokens.findValid(ctx, r.db, &token, tokenHash); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes a token as part of the password reset transaction
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, tx *sqlx.Tx, id int64) error {
+	return passwordResetTokens.markUsed(ctx, tx, id)
+}
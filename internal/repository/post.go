@@ -22,42 +22,70 @@ func NewPostRepository(db *sqlx.DB) PostRepository {
 	return &postRepository{db: db}
 }
 
-// Create inserts a new post and its media in a transaction.
-func (r *postRepository) Create(ctx context.Context, userID int64, caption *string, mediaURLs []string) (*model.Post, error) {
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
+// Create inserts a new post and its media within the caller's transaction.
+// Media uploads are claimed in the same transaction so a key can only ever be attached to one post.
+func (r *postRepository) Create(ctx context.Context, tx *sqlx.Tx, userID int64, caption *string, settings model.PostSettings, media []model.PostMediaInput) (*model.Post, error) {
 	// Insert post
 	var post model.Post
 	query := `
-		INSERT INTO posts (user_id, caption)
-		VALUES ($1, $2)
-		RETURNING id, user_id, caption, like_count, comment_count, created_at, updated_at
+		INSERT INTO posts (user_id, caption, hide_like_count, comments_disabled)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, caption, like_count, comment_count, repost_count, hide_like_count, comments_disabled, created_at, updated_at
 	`
-	err = tx.GetContext(ctx, &post, query, userID, caption)
+	err := tx.GetContext(ctx, &post, query, userID, caption, settings.HideLikeCount, settings.CommentsDisabled)
 	if err != nil {
 		return nil, fmt.Errorf("insert post: %w", err)
 	}
 
 	// Insert media items
-	if len(mediaURLs) > 0 {
+	if len(media) > 0 {
 		mediaQuery := `
-			INSERT INTO post_details (post_id, media_url, media_type, position)
-			VALUES ($1, $2, $3, $4)
-			RETURNING id, post_id, media_url, media_type, position
+			INSERT INTO post_details (post_id, media_url, media_type, position, width, height, duration_ms, thumbnail_url, thumbnail_key, alt_text, dominant_color)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING id, post_id, media_url, media_type, position, width, height, duration_ms, thumbnail_url, thumbnail_key, alt_text, dominant_color
 		`
-		post.Media = make([]model.PostMedia, len(mediaURLs))
-		for i, url := range mediaURLs {
-			var media model.PostMedia
-			mediaType := "image" // Default; could detect from URL or content-type
-			err = tx.GetContext(ctx, &media, mediaQuery, post.ID, url, mediaType, i)
+		post.Media = make([]model.PostMedia, len(media))
+		keys := make([]string, len(media))
+		for i, m := range media {
+			var pm model.PostMedia
+			err = tx.GetContext(ctx, &pm, mediaQuery, post.ID, m.URL, m.MediaType, i, m.Width, m.Height, m.DurationMs, m.ThumbnailURL, m.ThumbnailKey, m.AltText, m.DominantColor)
 			if err != nil {
 				return nil, fmt.Errorf("insert media %d: %w", i, err)
 			}
-			post.Media[i] = media
+			if err := insertMediaTags(ctx, tx, pm.ID, m.Tags); err != nil {
+				return nil, err
+			}
+			post.Media[i] = pm
+			keys[i] = m.Key
+		}
+
+		// Load tags back with user info for the response
+		mediaIDs := make([]int64, len(post.Media))
+		for i := range post.Media {
+			mediaIDs[i] = post.Media[i].ID
+		}
+		tags, err := getMediaTags(ctx, tx, mediaIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range post.Media {
+			post.Media[i].Tags = tags[post.Media[i].ID]
+		}
+
+		// Claim the uploads; a concurrent post using the same key will match fewer rows
+		result, err := tx.ExecContext(ctx, `
+			UPDATE media_uploads SET post_id = $1, used_at = NOW()
+			WHERE object_key = ANY($2) AND user_id = $3 AND used_at IS NULL
+		`, post.ID, pq.Array(keys), userID)
+		if err != nil {
+			return nil, fmt.Errorf("claim media uploads: %w", err)
+		}
+		claimed, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("get rows affected: %w", err)
+		}
+		if int(claimed) != len(keys) {
+			return nil, model.ErrMediaAlreadyUsed
 		}
 	}
 
@@ -67,17 +95,13 @@ func (r *postRepository) Create(ctx context.Context, userID int64, caption *stri
 		return nil, fmt.Errorf("increment post count: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit transaction: %w", err)
-	}
-
 	return &post, nil
 }
 
-// GetByID retrieves a single post with its media.
+// GetByID retrieves a single post with its media and caption mentions.
 func (r *postRepository) GetByID(ctx context.Context, postID int64) (*model.Post, error) {
 	query := `
-		SELECT id, user_id, caption, like_count, comment_count, created_at, updated_at
+		SELECT id, user_id, caption, like_count, comment_count, repost_count, hide_like_count, comments_disabled, created_at, updated_at
 		FROM posts
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -97,17 +121,137 @@ func (r *postRepository) GetByID(ctx context.Context, postID int64) (*model.Post
 	}
 	post.Media = media[postID]
 
+	mentions, err := getPostMentions(ctx, r.db, []int64{postID})
+	if err != nil {
+		return nil, err
+	}
+	post.Mentions = mentions[postID]
+
 	return &post, nil
 }
 
-// Delete performs a soft delete on a post.
-func (r *postRepository) Delete(ctx context.Context, postID, userID int64) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+// UpdateCaption replaces a post's caption.
+func (r *postRepository) UpdateCaption(ctx context.Context, tx *sqlx.Tx, postID int64, caption *string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE posts SET caption = $1, updated_at = NOW() WHERE id = $2 AND deleted_at IS NULL`, caption, postID)
 	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
+		return fmt.Errorf("update caption: %w", err)
+	}
+	return nil
+}
+
+// UpdateSettings changes the author's per-post settings. Nil values are left unchanged.
+// Returns ErrPostNotFound if the post doesn't exist or is deleted.
+func (r *postRepository) UpdateSettings(ctx context.Context, tx *sqlx.Tx, postID int64, hideLikeCount, commentsDisabled *bool) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE posts
+		SET hide_like_count = COALESCE($1, hide_like_count),
+		    comments_disabled = COALESCE($2, comments_disabled),
+		    updated_at = NOW()
+		WHERE id = $3 AND deleted_at IS NULL
+	`, hideLikeCount, commentsDisabled, postID)
+	if err != nil {
+		return fmt.Errorf("update settings: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrPostNotFound
+	}
+	return nil
+}
+
+// UpdateMediaMetadata stores the dimensions, duration, poster frame and dominant color
+// computed by the worker. Returns ErrPostNotFound if the post was deleted in the meantime.
+func (r *postRepository) UpdateMediaMetadata(ctx context.Context, mediaID int64, m *model.PostMediaInput) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE post_details d
+		SET width = $1, height = $2, duration_ms = $3, thumbnail_url = $4, thumbnail_key = $5, dominant_color = $6
+		FROM posts p
+		WHERE d.id = $7 AND p.id = d.post_id AND p.deleted_at IS NULL
+	`, m.Width, m.Height, m.DurationMs, m.ThumbnailURL, m.ThumbnailKey, m.DominantColor, mediaID)
+	if err != nil {
+		return fmt.Errorf("update media metadata: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrPostNotFound
+	}
+	return nil
+}
+
+// CommentsDisabled reports whether the author turned off comments on a post.
+// Returns ErrPostNotFound if the post doesn't exist or is deleted.
+func (r *postRepository) CommentsDisabled(ctx context.Context, postID int64) (bool, error) {
+	var disabled bool
+	err := r.db.GetContext(ctx, &disabled, `SELECT comments_disabled FROM posts WHERE id = $1 AND deleted_at IS NULL`, postID)
+	if err == sql.ErrNoRows {
+		return false, model.ErrPostNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("get comments disabled: %w", err)
+	}
+	return disabled, nil
+}
+
+// GetPinnedCommentID returns the pinned comment of a post, or nil if none.
+// Returns ErrPostNotFound if the post doesn't exist or is deleted.
+func (r *postRepository) GetPinnedCommentID(ctx context.Context, postID int64) (*int64, error) {
+	var pinnedID *int64
+	err := r.db.GetContext(ctx, &pinnedID, `SELECT pinned_comment_id FROM posts WHERE id = $1 AND deleted_at IS NULL`, postID)
+	if err == sql.ErrNoRows {
+		return nil, model.ErrPostNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get pinned comment: %w", err)
+	}
+	return pinnedID, nil
+}
+
+// SetPinnedComment pins a comment on a post, replacing any previous pin.
+func (r *postRepository) SetPinnedComment(ctx context.Context, postID, commentID int64) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE posts SET pinned_comment_id = $1
+		WHERE id = $2 AND deleted_at IS NULL
+	`, commentID, postID)
+	if err != nil {
+		return fmt.Errorf("pin comment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrPostNotFound
+	}
+	return nil
+}
+
+// UnpinComment removes the pin from a post if commentID is the pinned comment.
+func (r *postRepository) UnpinComment(ctx context.Context, postID, commentID int64) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE posts SET pinned_comment_id = NULL
+		WHERE id = $1 AND pinned_comment_id = $2 AND deleted_at IS NULL
+	`, postID, commentID)
+	if err != nil {
+		return fmt.Errorf("unpin comment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrCommentNotPinned
 	}
-	defer tx.Rollback()
+	return nil
+}
 
+// Delete performs a soft delete on a post within the caller's transaction.
+func (r *postRepository) Delete(ctx context.Context, tx *sqlx.Tx, postID, userID int64) error {
 	// Verify ownership and soft delete
 	result, err := tx.ExecContext(ctx, `
 		UPDATE posts SET deleted_at = NOW()
@@ -137,10 +281,10 @@ func (r *postRepository) Delete(ctx context.Context, postID, userID int64) error
 		return fmt.Errorf("decrement post count: %w", err)
 	}
 
-	return tx.Commit()
+	return nil
 }
 
-// GetByIDs retrieves multiple posts by their IDs with media.
+// GetByIDs retrieves multiple posts by their IDs with media and caption mentions.
 // Used for hydrating feed from cache.
 func (r *postRepository) GetByIDs(ctx context.Context, postIDs []int64) ([]model.Post, error) {
 	if len(postIDs) == 0 {
@@ -148,7 +292,7 @@ func (r *postRepository) GetByIDs(ctx context.Context, postIDs []int64) ([]model
 	}
 
 	query := `
-		SELECT id, user_id, caption, like_count, comment_count, created_at, updated_at
+		SELECT id, user_id, caption, like_count, comment_count, repost_count, hide_like_count, comments_disabled, created_at, updated_at
 		FROM posts
 		WHERE id = ANY($1) AND deleted_at IS NULL
 	`
@@ -163,8 +307,13 @@ func (r *postRepository) GetByIDs(ctx context.Context, postIDs []int64) ([]model
 	if err != nil {
 		return nil, err
 	}
+	mentionMap, err := getPostMentions(ctx, r.db, postIDs)
+	if err != nil {
+		return nil, err
+	}
 	for i := range posts {
 		posts[i].Media = mediaMap[posts[i].ID]
+		posts[i].Mentions = mentionMap[posts[i].ID]
 	}
 
 	// Re-order posts to match input order (important for feed ordering)
@@ -182,6 +331,14 @@ func (r *postRepository) GetByIDs(ctx context.Context, postIDs []int64) ([]model
 	return ordered, nil
 }
 
+// postThumbnailColumns selects model.PostThumbnail fields for a posts row aliased as p.
+// Videos use their poster frame as the thumbnail.
+const postThumbnailColumns = `p.id,
+				   (SELECT COALESCE(thumbnail_url, media_url) FROM post_details WHERE post_id = p.id ORDER BY position LIMIT 1) as thumbnail_url,
+				   (SELECT media_type FROM post_details WHERE post_id = p.id ORDER BY position LIMIT 1) as media_type,
+				   (SELECT COUNT(*) FROM post_details WHERE post_id = p.id) as media_count,
+				   p.created_at`
+
 // GetUserThumbnails retrieves post thumbnails for a user's profile grid.
 func (r *postRepository) GetUserThumbnails(ctx context.Context, userID int64, cursor *string, limit int) ([]model.PostThumbnail, *string, error) {
 	var query string
@@ -190,9 +347,7 @@ func (r *postRepository) GetUserThumbnails(ctx context.Context, userID int64, cu
 	// Parse compound cursor: "timestamp_id"
 	if cursor == nil {
 		query = `
-			SELECT p.id, 
-				   (SELECT media_url FROM post_details WHERE post_id = p.id ORDER BY position LIMIT 1) as thumbnail_url,
-				   (SELECT COUNT(*) FROM post_details WHERE post_id = p.id) as media_count
+			SELECT ` + postThumbnailColumns + `
 			FROM posts p
 			WHERE p.user_id = $1 AND p.deleted_at IS NULL
 			ORDER BY p.created_at DESC, p.id DESC
@@ -206,9 +361,7 @@ func (r *postRepository) GetUserThumbnails(ctx context.Context, userID int64, cu
 			return nil, nil, fmt.Errorf("invalid cursor: %w", err)
 		}
 		query = `
-			SELECT p.id,
-				   (SELECT media_url FROM post_details WHERE post_id = p.id ORDER BY position LIMIT 1) as thumbnail_url,
-				   (SELECT COUNT(*) FROM post_details WHERE post_id = p.id) as media_count
+			SELECT ` + postThumbnailColumns + `
 			FROM posts p
 			WHERE p.user_id = $1 AND p.deleted_at IS NULL
 			  AND (p.created_at, p.id) < ($2, $3)
@@ -229,10 +382,7 @@ func (r *postRepository) GetUserThumbnails(ctx context.Context, userID int64, cu
 	if len(thumbnails) > limit {
 		thumbnails = thumbnails[:limit]
 		last := thumbnails[len(thumbnails)-1]
-		// Need to get created_at for cursor - fetch it
-		var createdAt time.Time
-		r.db.GetContext(ctx, &createdAt, `SELECT created_at FROM posts WHERE id = $1`, last.ID)
-		c := formatCursor(createdAt, last.ID)
+		c := formatCursor(last.CreatedAt, last.ID)
 		nextCursor = &c
 	}
 
@@ -310,6 +460,29 @@ func (r *postRepository) GetAuthorID(ctx context.Context, postID int64) (int64,
 	return authorID, nil
 }
 
+// GetAuthorIDs returns the author of each live post in postIDs.
+func (r *postRepository) GetAuthorIDs(ctx context.Context, postIDs []int64) (map[int64]int64, error) {
+	result := make(map[int64]int64)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	type row struct {
+		ID     int64 `db:"id"`
+		UserID int64 `db:"user_id"`
+	}
+	var rows []row
+	err := r.db.SelectContext(ctx, &rows, `SELECT id, user_id FROM posts WHERE id = ANY($1) AND deleted_at IS NULL`, pq.Array(postIDs))
+	if err != nil {
+		return nil, fmt.Errorf("get author ids: %w", err)
+	}
+
+	for _, r := range rows {
+		result[r.ID] = r.UserID
+	}
+	return result, nil
+}
+
 // CheckLikes checks which posts the user has liked.
 // Returns a map of post_id -> liked (true/false).
 func (r *postRepository) CheckLikes(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error) {
@@ -335,6 +508,54 @@ func (r *postRepository) CheckLikes(ctx context.Context, userID int64, postIDs [
 	return result, nil
 }
 
+// CheckSaves checks which posts the user has saved.
+func (r *postRepository) CheckSaves(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error) {
+	if len(postIDs) == 0 {
+		return make(map[int64]bool), nil
+	}
+
+	query := `SELECT post_id FROM saved_posts WHERE user_id = $1 AND post_id = ANY($2)`
+	var savedIDs []int64
+	err := r.db.SelectContext(ctx, &savedIDs, query, userID, pq.Array(postIDs))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("check saves: %w", err)
+	}
+
+	result := make(map[int64]bool)
+	for _, id := range postIDs {
+		result[id] = false
+	}
+	for _, id := range savedIDs {
+		result[id] = true
+	}
+
+	return result, nil
+}
+
+// CheckReposts checks which posts the user has reposted.
+func (r *postRepository) CheckReposts(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error) {
+	if len(postIDs) == 0 {
+		return make(map[int64]bool), nil
+	}
+
+	query := `SELECT post_id FROM reposts WHERE user_id = $1 AND post_id = ANY($2)`
+	var repostedIDs []int64
+	err := r.db.SelectContext(ctx, &repostedIDs, query, userID, pq.Array(postIDs))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("check reposts: %w", err)
+	}
+
+	result := make(map[int64]bool)
+	for _, id := range postIDs {
+		result[id] = false
+	}
+	for _, id := range repostedIDs {
+		result[id] = true
+	}
+
+	return result, nil
+}
+
 // Like inserts a like record. Returns ErrAlreadyLiked if duplicate.
 func (r *postRepository) Like(ctx context.Context, tx *sqlx.Tx, postID, userID int64) error {
 	query := `INSERT INTO post_likes (post_id, user_id) VALUES ($1, $2)`
@@ -367,7 +588,7 @@ func (r *postRepository) Unlike(ctx context.Context, tx *sqlx.Tx, postID, userID
 }
 
 // GetPostLikers returns paginated users who liked a post.
-func (r *postRepository) GetPostLikers(ctx context.Context, postID int64, cursor *string, limit int) ([]model.UserSummary, *string, error) {
+func (r *postRepository) GetPostLikers(ctx context.Context, postID, viewerID int64, cursor *string, limit int) ([]model.UserSummary, *string, error) {
 	var query string
 	var args []interface{}
 
@@ -376,11 +597,11 @@ func (r *postRepository) GetPostLikers(ctx context.Context, postID int64, cursor
 			SELECT u.id, u.username, u.display_name, u.avatar_url
 			FROM post_likes pl
 			JOIN users u ON u.id = pl.user_id
-			WHERE pl.post_id = $1
+			WHERE pl.post_id = $1 AND ` + notBlocked("u.id", "$2") + `
 			ORDER BY pl.created_at DESC, pl.id DESC
-			LIMIT $2
+			LIMIT $3
 		`
-		args = []interface{}{postID, limit + 1}
+		args = []interface{}{postID, viewerID, limit + 1}
 	} else {
 		ts, id, err := parseCursor(*cursor)
 		if err != nil {
@@ -390,11 +611,12 @@ func (r *postRepository) GetPostLikers(ctx context.Context, postID int64, cursor
 			SELECT u.id, u.username, u.display_name, u.avatar_url
 			FROM post_likes pl
 			JOIN users u ON u.id = pl.user_id
-			WHERE pl.post_id = $1 AND (pl.created_at, pl.id) < ($2, $3)
+			WHERE pl.post_id = $1 AND ` + notBlocked("u.id", "$2") + `
+			  AND (pl.created_at, pl.id) < ($3, $4)
 			ORDER BY pl.created_at DESC, pl.id DESC
-			LIMIT $4
+			LIMIT $5
 		`
-		args = []interface{}{postID, ts, id, limit + 1}
+		args = []interface{}{postID, viewerID, ts, id, limit + 1}
 	}
 
 	var users []model.UserSummary
@@ -459,6 +681,24 @@ func (r *postRepository) IncrementCommentCount(ctx context.Context, tx *sqlx.Tx,
 	return nil
 }
 
+// IncrementRepostCount atomically updates the repost_count on a post.
+// Decrements also apply to deleted posts, so a repost can still be undone after the post is deleted.
+func (r *postRepository) IncrementRepostCount(ctx context.Context, tx *sqlx.Tx, postID int64, delta int) error {
+	query := `UPDATE posts SET repost_count = repost_count + $1, updated_at = NOW() WHERE id = $2 AND (deleted_at IS NULL OR $1 < 0)`
+	result, err := tx.ExecContext(ctx, query, delta, postID)
+	if err != nil {
+		return fmt.Errorf("update repost count: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrPostNotFound
+	}
+	return nil
+}
+
 // Exists checks if a post exists and is not deleted.
 func (r *postRepository) Exists(ctx context.Context, postID int64) (bool, error) {
 	var exists bool
@@ -469,6 +709,27 @@ func (r *postRepository) Exists(ctx context.Context, postID int64) (bool, error)
 	return exists, nil
 }
 
+// IsVisible reports whether a live post exists and the viewer (0 if anonymous) may see it.
+func (r *postRepository) IsVisible(ctx context.Context, postID, viewerID int64) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM posts p WHERE p.id = $1 AND p.deleted_at IS NULL AND ` + visiblePost("p", "$2") + `)`
+	var visible bool
+	if err := r.db.GetContext(ctx, &visible, query, postID, viewerID); err != nil {
+		return false, fmt.Errorf("check post visible: %w", err)
+	}
+	return visible, nil
+}
+
+// visiblePost is the filter for posts the viewer may see: posts of public accounts, the
+// viewer's own posts and posts of private accounts the viewer follows, minus posts of users
+// blocked by or blocking the viewer. alias is the post table alias and viewerParam the
+// placeholder holding the viewer ID (0 if anonymous).
+func visiblePost(alias, viewerParam string) string {
+	return fmt.Sprintf(`((%[1]s.user_id = %[2]s
+		OR NOT EXISTS (SELECT 1 FROM users vu WHERE vu.id = %[1]s.user_id AND vu.is_private)
+		OR EXISTS (SELECT 1 FROM follows vf WHERE vf.follower_id = %[2]s AND vf.followee_id = %[1]s.user_id))
+		AND %[3]s)`, alias, viewerParam, notBlocked(alias+".user_id", viewerParam))
+}
+
 // Helper: fetch media for multiple posts in one query
 func (r *postRepository) getPostMedia(ctx context.Context, postIDs []int64) (map[int64][]model.PostMedia, error) {
 	if len(postIDs) == 0 {
@@ -476,7 +737,7 @@ func (r *postRepository) getPostMedia(ctx context.Context, postIDs []int64) (map
 	}
 
 	query := `
-		SELECT id, post_id, media_url, media_type, position
+		SELECT id, post_id, media_url, media_type, position, width, height, duration_ms, thumbnail_url, thumbnail_key, alt_text, dominant_color
 		FROM post_details
 		WHERE post_id = ANY($1)
 		ORDER BY post_id, position
@@ -487,9 +748,19 @@ func (r *postRepository) getPostMedia(ctx context.Context, postIDs []int64) (map
 		return nil, fmt.Errorf("get post media: %w", err)
 	}
 
+	mediaIDs := make([]int64, len(media))
+	for i, m := range media {
+		mediaIDs[i] = m.ID
+	}
+	tags, err := getMediaTags(ctx, r.db, mediaIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Group by post_id
 	result := make(map[int64][]model.PostMedia)
 	for _, m := range media {
+		m.Tags = tags[m.ID]
 		result[m.PostID] = append(result[m.PostID], m)
 	}
 	return result, nil
@@ -518,4 +789,3 @@ func parseCursor(cursor string) (time.Time, int64, error) {
 func formatCursor(t time.Time, id int64) string {
 	return fmt.Sprintf("%d:%d", id, t.Unix())
 }
-
@@ -75,12 +75,21 @@ func (r *refreshTokenRepository) Revoke(ctx context.Context, id string, replaced
 
 // RevokeAllForUser revokes all active refresh tokens for a user
 func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int64) error {
+	return revokeAllForUser(ctx, r.db, userID)
+}
+
+// RevokeAllForUserTx is RevokeAllForUser as part of a larger transaction (e.g. a password reset).
+func (r *refreshTokenRepository) RevokeAllForUserTx(ctx context.Context, tx *sqlx.Tx, userID int64) error {
+	return revokeAllForUser(ctx, tx, userID)
+}
+
+func revokeAllForUser(ctx context.Context, db sqlx.ExecerContext, userID int64) error {
 	query := `
 		UPDATE refresh_tokens
 		SET revoked_at = NOW()
 		WHERE user_id = $1 AND revoked_at IS NULL
 	`
-	_, err := r.db.ExecContext(ctx, query, userID)
+	_, err := db.ExecContext(ctx, query, userID)
 	if err != nil {
 		return fmt.Errorf("failed to revoke all tokens for user: %w", err)
 	}
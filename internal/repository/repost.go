@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"iamstagram_22520060/internal/cache"
+	"iamstagram_22520060/internal/model"
+)
+
+type repostRepository struct {
+	db *sqlx.DB
+}
+
+func NewRepostRepository(db *sqlx.DB) RepostRepository {
+	return &repostRepository{db: db}
+}
+
+// Create records a repost. Returns false if the user already reposted the post.
+func (r *repostRepository) Create(ctx context.Context, tx *sqlx.Tx, userID, postID int64) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO reposts (user_id, post_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, userID, postID)
+	if err != nil {
+		return false, fmt.Errorf("insert repost: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Delete removes a repost. Returns ErrNotReposted if not found.
+func (r *repostRepository) Delete(ctx context.Context, tx *sqlx.Tx, userID, postID int64) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM reposts WHERE user_id = $1 AND post_id = $2`, userID, postID)
+	if err != nil {
+		return fmt.Errorf("delete repost: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrNotReposted
+	}
+	return nil
+}
+
+// GetReposterIDs returns everyone who reposted a post.
+// Deleted posts are included so their reposts can be cleaned out of feeds.
+func (r *repostRepository) GetReposterIDs(ctx context.Context, postID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.SelectContext(ctx, &ids, `SELECT user_id FROM reposts WHERE post_id = $1`, postID)
+	if err != nil {
+		return nil, fmt.Errorf("get reposter ids: %w", err)
+	}
+	return ids, nil
+}
+
+// GetRecentRepostsByUser returns a user's recent reposts of live posts (for follow backfill).
+// Timestamps are the repost time.
+func (r *repostRepository) GetRecentRepostsByUser(ctx context.Context, userID int64, limit int) ([]cache.PostScore, error) {
+	query := `
+		SELECT rp.post_id, EXTRACT(EPOCH FROM rp.created_at)::bigint as timestamp
+		FROM reposts rp
+		JOIN posts p ON p.id = rp.post_id AND p.deleted_at IS NULL
+		WHERE rp.user_id = $1
+		ORDER BY rp.created_at DESC
+		LIMIT $2
+	`
+	type row struct {
+		PostID    int64 `db:"post_id"`
+		Timestamp int64 `db:"timestamp"`
+	}
+	var rows []row
+	if err := r.db.SelectContext(ctx, &rows, query, userID, limit); err != nil {
+		return nil, fmt.Errorf("get recent reposts: %w", err)
+	}
+
+	posts := make([]cache.PostScore, len(rows))
+	for i, r := range rows {
+		posts[i] = cache.PostScore{PostID: r.PostID, Timestamp: r.Timestamp}
+	}
+	return posts, nil
+}
+
+// GetFeedReposts returns reposts by the given users for cache warming, newest first.
+// A post reposted by several of them appears once, attributed to the earliest repost.
+func (r *repostRepository) GetFeedReposts(ctx context.Context, reposterIDs []int64, limit int) ([]cache.RepostScore, error) {
+	if len(reposterIDs) == 0 {
+		return []cache.RepostScore{}, nil
+	}
+
+	query := `
+		SELECT post_id, user_id, timestamp FROM (
+			SELECT DISTINCT ON (rp.post_id)
+			       rp.post_id, rp.user_id, EXTRACT(EPOCH FROM rp.created_at)::bigint as timestamp
+			FROM reposts rp
+			JOIN posts p ON p.id = rp.post_id AND p.deleted_at IS NULL
+			WHERE rp.user_id = ANY($1)
+			ORDER BY rp.post_id, rp.created_at ASC
+		) first_reposts
+		ORDER BY timestamp DESC
+		LIMIT $2
+	`
+	type row struct {
+		PostID    int64 `db:"post_id"`
+		UserID    int64 `db:"user_id"`
+		Timestamp int64 `db:"timestamp"`
+	}
+	var rows []row
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(reposterIDs), limit); err != nil {
+		return nil, fmt.Errorf("get feed reposts: %w", err)
+	}
+
+	reposts := make([]cache.RepostScore, len(rows))
+	for i, r := range rows {
+		reposts[i] = cache.RepostScore{PostID: r.PostID, ReposterID: r.UserID, Timestamp: r.Timestamp}
+	}
+	return reposts, nil
+}
+
+// DeleteByAuthor removes every repost of the author's posts (e.g. when the account goes
+// private) and resets the posts' repost counts. Returns the removed reposts so they can be
+// cleaned out of feeds.
+func (r *repostRepository) DeleteByAuthor(ctx context.Context, tx *sqlx.Tx, authorID int64) ([]cache.RepostScore, error) {
+	query := `
+		WITH removed AS (
+			DELETE FROM reposts rp
+			USING posts p
+			WHERE rp.post_id = p.id AND p.user_id = $1
+			RETURNING rp.post_id, rp.user_id, EXTRACT(EPOCH FROM rp.created_at)::bigint as timestamp
+		), reset AS (
+			UPDATE posts SET repost_count = 0
+			WHERE user_id = $1 AND repost_count <> 0
+		)
+		SELECT post_id, user_id, timestamp FROM removed
+	`
+	type row struct {
+		PostID    int64 `db:"post_id"`
+		UserID    int64 `db:"user_id"`
+		Timestamp int64 `db:"timestamp"`
+	}
+	var rows []row
+	if err := tx.SelectContext(ctx, &rows, query, authorID); err != nil {
+		return nil, fmt.Errorf("delete reposts by author: %w", err)
+	}
+
+	reposts := make([]cache.RepostScore, len(rows))
+	for i, r := range rows {
+		reposts[i] = cache.RepostScore{PostID: r.PostID, ReposterID: r.UserID, Timestamp: r.Timestamp}
+	}
+	return reposts, nil
+}
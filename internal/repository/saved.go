@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"iamstagram_22520060/internal/model"
+)
+
+type savedRepository struct {
+	db *sqlx.DB
+}
+
+func NewSavedRepository(db *sqlx.DB) SavedRepository {
+	return &savedRepository{db: db}
+}
+
+// collectionColumns selects model.Collection fields for a collections row aliased as c.
+// Deleted posts don't count and never become the cover.
+const collectionColumns = `c.id, c.user_id, c.name, c.created_at, c.updated_at,
+		       (SELECT COUNT(*) FROM collection_posts cp
+		        JOIN posts p ON p.id = cp.post_id AND p.deleted_at IS NULL
+		        WHERE cp.collection_id = c.id) as post_count,
+		       (SELECT COALESCE(pd.thumbnail_url, pd.media_url) FROM collection_posts cp
+		        JOIN posts p ON p.id = cp.post_id AND p.deleted_at IS NULL
+		        JOIN post_details pd ON pd.post_id = p.id AND pd.position = 0
+		        WHERE cp.collection_id = c.id
+		        ORDER BY cp.created_at DESC, cp.post_id DESC LIMIT 1) as cover_url`
+
+// Save bookmarks a post. Returns false if it was already saved.
+func (r *savedRepository) Save(ctx context.Context, tx *sqlx.Tx, userID, postID int64) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO saved_posts (user_id, post_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, userID, postID)
+	if err != nil {
+		return false, fmt.Errorf("insert saved post: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Unsave removes a bookmark and takes the post out of all of the user's collections.
+func (r *savedRepository) Unsave(ctx context.Context, tx *sqlx.Tx, userID, postID int64) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM saved_posts WHERE user_id = $1 AND post_id = $2`, userID, postID)
+	if err != nil {
+		return fmt.Errorf("delete saved post: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrNotSaved
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM collection_posts
+		WHERE post_id = $1 AND collection_id IN (SELECT id FROM collections WHERE user_id = $2)
+	`, postID, userID)
+	if err != nil {
+		return fmt.Errorf("remove from collections: %w", err)
+	}
+	return nil
+}
+
+// GetSavedPostIDs returns the user's saved (live) posts, most recently saved first.
+func (r *savedRepository) GetSavedPostIDs(ctx context.Context, userID int64, cursor *string, limit int) ([]int64, *string, error) {
+	query := `
+		SELECT s.post_id, s.created_at as saved_at
+		FROM saved_posts s
+		JOIN posts p ON p.id = s.post_id AND p.deleted_at IS NULL
+		WHERE s.user_id = $1
+	`
+	return r.selectPostRefs(ctx, query, "s", userID, cursor, limit)
+}
+
+// CreateCollection creates an empty collection.
+func (r *savedRepository) CreateCollection(ctx context.Context, userID int64, name string) (*model.Collection, error) {
+	var c model.Collection
+	err := r.db.GetContext(ctx, &c, `
+		INSERT INTO collections (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, user_id, name, 0 as post_count, NULL::text as cover_url, created_at, updated_at
+	`, userID, name)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, model.ErrCollectionExists
+		}
+		return nil, fmt.Errorf("insert collection: %w", err)
+	}
+	return &c, nil
+}
+
+// GetCollection returns a collection owned by userID.
+// Collections of other users are reported as not found.
+func (r *savedRepository) GetCollection(ctx context.Context, collectionID, userID int64) (*model.Collection, error) {
+	var c model.Collection
+	err := r.db.GetContext(ctx, &c, `
+		SELECT `+collectionColumns+`
+		FROM collections c
+		WHERE c.id = $1 AND c.user_id = $2
+	`, collectionID, userID)
+	if err == sql.ErrNoRows {
+		return nil, model.ErrCollectionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get collection: %w", err)
+	}
+	return &c, nil
+}
+
+// GetCollections returns all collections of a user, newest first.
+func (r *savedRepository) GetCollections(ctx context.Context, userID int64) ([]model.Collection, error) {
+	var collections []model.Collection
+	err := r.db.SelectContext(ctx, &collections, `
+		SELECT `+collectionColumns+`
+		FROM collections c
+		WHERE c.user_id = $1
+		ORDER BY c.created_at DESC, c.id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get collections: %w", err)
+	}
+	return collections, nil
+}
+
+// CountCollections returns how many collections a user has.
+func (r *savedRepository) CountCollections(ctx context.Context, userID int64) (int, error) {
+	var count int
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM collections WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("count collections: %w", err)
+	}
+	return count, nil
+}
+
+// RenameCollection renames a collection owned by userID.
+func (r *savedRepository) RenameCollection(ctx context.Context, collectionID, userID int64, name string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE collections SET name = $1, updated_at = NOW()
+		WHERE id = $2 AND user_id = $3
+	`, name, collectionID, userID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return model.ErrCollectionExists
+		}
+		return fmt.Errorf("rename collection: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrCollectionNotFound
+	}
+	return nil
+}
+
+// DeleteCollection deletes a collection owned by userID. Its posts stay saved.
+func (r *savedRepository) DeleteCollection(ctx context.Context, collectionID, userID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM collections WHERE id = $1 AND user_id = $2`, collectionID, userID)
+	if err != nil {
+		return fmt.Errorf("delete collection: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrCollectionNotFound
+	}
+	return nil
+}
+
+// AddToCollection adds a post to a collection (no-op if already there).
+func (r *savedRepository) AddToCollection(ctx context.Context, tx *sqlx.Tx, collectionID, postID int64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO collection_posts (collection_id, post_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, collectionID, postID)
+	if err != nil {
+		return fmt.Errorf("add to collection: %w", err)
+	}
+	return nil
+}
+
+// RemoveFromCollection removes a post from a collection. The post stays saved.
+func (r *savedRepository) RemoveFromCollection(ctx context.Context, collectionID, postID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM collection_posts WHERE collection_id = $1 AND post_id = $2`, collectionID, postID)
+	if err != nil {
+		return fmt.Errorf("remove from collection: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrNotInCollection
+	}
+	return nil
+}
+
+// GetCollectionPostIDs returns the (live) posts in a collection, most recently added first.
+func (r *savedRepository) GetCollectionPostIDs(ctx context.Context, collectionID int64, cursor *string, limit int) ([]int64, *string, error) {
+	query := `
+		SELECT cp.post_id, cp.created_at as saved_at
+		FROM collection_posts cp
+		JOIN posts p ON p.id = cp.post_id AND p.deleted_at IS NULL
+		WHERE cp.collection_id = $1
+	`
+	return r.selectPostRefs(ctx, query, "cp", collectionID, cursor, limit)
+}
+
+// selectPostRefs pages through a (post_id, saved_at) query filtered by ownerID ($1),
+// newest first. alias is the table alias holding created_at and post_id.
+func (r *savedRepository) selectPostRefs(ctx context.Context, baseQuery, alias string, ownerID int64, cursor *string, limit int) ([]int64, *string, error) {
+	query := baseQuery
+	args := []interface{}{ownerID}
+
+	if cursor != nil {
+		ts, id, err := parseCursor(*cursor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		query += fmt.Sprintf(" AND (%[1]s.created_at, %[1]s.post_id) < ($2, $3)", alias)
+		args = append(args, ts, id)
+	}
+	query += fmt.Sprintf(" ORDER BY %[1]s.created_at DESC, %[1]s.post_id DESC LIMIT $%[2]d", alias, len(args)+1)
+	args = append(args, limit+1)
+
+	var refs []model.SavedPostRef
+	if err := r.db.SelectContext(ctx, &refs, query, args...); err != nil {
+		return nil, nil, fmt.Errorf("get saved posts: %w", err)
+	}
+
+	var nextCursor *string
+	if len(refs) > limit {
+		refs = refs[:limit]
+		last := refs[len(refs)-1]
+		c := formatCursor(last.SavedAt, last.PostID)
+		nextCursor = &c
+	}
+
+	postIDs := make([]int64, len(refs))
+	for i, ref := range refs {
+		postIDs[i] = ref.PostID
+	}
+	return postIDs, nextCursor, nil
+}
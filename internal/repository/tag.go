@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"iamstagram_22520060/internal/model"
+)
+
+type tagRepository struct {
+	db *sqlx.DB
+}
+
+func NewTagRepository(db *sqlx.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// GetMediaPostID returns the post a media item belongs to.
+func (r *tagRepository) GetMediaPostID(ctx context.Context, mediaID int64) (int64, error) {
+	var postID int64
+	err := r.db.GetContext(ctx, &postID, `SELECT post_id FROM post_details WHERE id = $1`, mediaID)
+	if err == sql.ErrNoRows {
+		return 0, model.ErrMediaNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get media post id: %w", err)
+	}
+	return postID, nil
+}
+
+// GetTaggedUserIDsByPost returns every user tagged anywhere in a post.
+func (r *tagRepository) GetTaggedUserIDsByPost(ctx context.Context, postID int64) ([]int64, error) {
+	query := `
+		SELECT DISTINCT t.tagged_user_id
+		FROM post_media_tags t
+		JOIN post_details d ON d.id = t.media_id
+		WHERE d.post_id = $1
+	`
+	var ids []int64
+	if err := r.db.SelectContext(ctx, &ids, query, postID); err != nil {
+		return nil, fmt.Errorf("get tagged users: %w", err)
+	}
+	return ids, nil
+}
+
+// ReplaceMediaTags deletes all tags on a media item and inserts the new set.
+func (r *tagRepository) ReplaceMediaTags(ctx context.Context, tx *sqlx.Tx, mediaID int64, tags []model.MediaTagInput) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_media_tags WHERE media_id = $1`, mediaID); err != nil {
+		return fmt.Errorf("delete media tags: %w", err)
+	}
+	return insertMediaTags(ctx, tx, mediaID, tags)
+}
+
+// DeleteUserTagsOnPost removes a user's tags from every media item of a post.
+func (r *tagRepository) DeleteUserTagsOnPost(ctx context.Context, postID, userID int64) error {
+	query := `
+		DELETE FROM post_media_tags t
+		USING post_details d
+		WHERE d.id = t.media_id AND d.post_id = $1 AND t.tagged_user_id = $2
+	`
+	result, err := r.db.ExecContext(ctx, query, postID, userID)
+	if err != nil {
+		return fmt.Errorf("delete user tags: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrTagNotFound
+	}
+	return nil
+}
+
+// GetTaggedThumbnails returns thumbnails of posts a user is tagged in, newest post first.
+// Posts of private accounts the viewer doesn't follow are left out.
+func (r *tagRepository) GetTaggedThumbnails(ctx context.Context, userID, viewerID int64, cursor *string, limit int) ([]model.PostThumbnail, *string, error) {
+	var query string
+	var args []interface{}
+
+	if cursor == nil {
+		query = `
+			SELECT ` + postThumbnailColumns + `
+			FROM posts p
+			WHERE p.deleted_at IS NULL
+			  AND EXISTS (
+				SELECT 1 FROM post_media_tags t
+				JOIN post_details d ON d.id = t.media_id
+				WHERE d.post_id = p.id AND t.tagged_user_id = $1
+			  )
+			  AND ` + visiblePost("p", "$2") + `
+			ORDER BY p.created_at DESC, p.id DESC
+			LIMIT $3
+		`
+		args = []interface{}{userID, viewerID, limit + 1}
+	} else {
+		ts, id, err := parseCursor(*cursor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		query = `
+			SELECT ` + postThumbnailColumns + `
+			FROM posts p
+			WHERE p.deleted_at IS NULL
+			  AND EXISTS (
+				SELECT 1 FROM post_media_tags t
+				JOIN post_details d ON d.id = t.media_id
+				WHERE d.post_id = p.id AND t.tagged_user_id = $1
+			  )
+			  AND ` + visiblePost("p", "$2") + `
+			  AND (p.created_at, p.id) < ($3, $4)
+			ORDER BY p.created_at DESC, p.id DESC
+			LIMIT $5
+		`
+		args = []interface{}{userID, viewerID, ts, id, limit + 1}
+	}
+
+	var thumbnails []model.PostThumbnail
+	if err := r.db.SelectContext(ctx, &thumbnails, query, args...); err != nil {
+		return nil, nil, fmt.Errorf("get tagged thumbnails: %w", err)
+	}
+
+	var nextCursor *string
+	if len(thumbnails) > limit {
+		thumbnails = thumbnails[:limit]
+		last := thumbnails[len(thumbnails)-1]
+		c := formatCursor(last.CreatedAt, last.ID)
+		nextCursor = &c
+	}
+
+	return thumbnails, nextCursor, nil
+}
+
+// insertMediaTags inserts tags for a media item inside an existing transaction.
+// Shared with postRepository.Create so tags are written atomically with the post.
+func insertMediaTags(ctx context.Context, tx *sqlx.Tx, mediaID int64, tags []model.MediaTagInput) error {
+	for _, t := range tags {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO post_media_tags (media_id, tagged_user_id, x, y)
+			VALUES ($1, $2, $3, $4)
+		`, mediaID, t.UserID, t.X, t.Y)
+		if err != nil {
+			return fmt.Errorf("insert media tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// getMediaTags fetches tags (with tagged user info) for a set of media items, grouped by media ID.
+func getMediaTags(ctx context.Context, db sqlx.QueryerContext, mediaIDs []int64) (map[int64][]model.PostMediaTag, error) {
+	if len(mediaIDs) == 0 {
+		return map[int64][]model.PostMediaTag{}, nil
+	}
+
+	query := `
+		SELECT t.id, t.media_id, t.tagged_user_id, t.x, t.y,
+		       u.id as "user.id", u.username as "user.username",
+		       u.display_name as "user.display_name", u.avatar_url as "user.avatar_url"
+		FROM post_media_tags t
+		JOIN users u ON u.id = t.tagged_user_id
+		WHERE t.media_id = ANY($1)
+		ORDER BY t.media_id, t.id
+	`
+	var tags []model.PostMediaTag
+	if err := sqlx.SelectContext(ctx, db, &tags, query, pq.Array(mediaIDs)); err != nil {
+		return nil, fmt.Errorf("get media tags: %w", err)
+	}
+
+	result := make(map[int64][]model.PostMediaTag)
+	for _, t := range tags {
+		result[t.MediaID] = append(result[t.MediaID], t)
+	}
+	return result, nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"iamstagram_22520060/internal/model"
 )
@@ -23,14 +25,15 @@ func NewUserRepository(db *sqlx.DB) UserRepository {
 // Create inserts a new user into the database
 func (r *userRepository) Create(ctx context.Context, u *model.User) error {
 	query := `
-		INSERT INTO users (username, password_hashed, display_name, avatar_url, avatar_key, is_new_user, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		INSERT INTO users (username, password_hashed, email, display_name, avatar_url, avatar_key, is_new_user, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
 		RETURNING id, is_new_user, follower_count, following_count, post_count, created_at, updated_at
 	`
 
 	row := r.db.QueryRowxContext(ctx, query,
 		u.Username,
 		u.PasswordHashed,
+		u.Email,
 		u.DisplayName,
 		u.AvatarURL,
 		u.AvatarKey,
@@ -47,6 +50,9 @@ func (r *userRepository) Create(ctx context.Context, u *model.User) error {
 		&u.UpdatedAt,
 	)
 	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == "idx_users_email" {
+			return model.ErrEmailExists
+		}
 		return fmt.Errorf("failed to insert user: %w", err)
 	}
 
@@ -56,8 +62,8 @@ func (r *userRepository) Create(ctx context.Context, u *model.User) error {
 // GetByID retrieves a user by their ID
 func (r *userRepository) GetByID(ctx context.Context, id int64) (*model.User, error) {
 	query := `
-		SELECT id, username, password_hashed, display_name, avatar_url, avatar_key, bio, is_new_user,
-		       follower_count, following_count, post_count, created_at, updated_at
+		SELECT id, username, password_hashed, email, email_verified, display_name, avatar_url, avatar_key, bio, is_new_user,
+		       follower_count, following_count, post_count, created_at, updated_at, deletion_scheduled_at, is_private
 		FROM users
 		WHERE id = $1
 	`
@@ -77,8 +83,8 @@ func (r *userRepository) GetByID(ctx context.Context, id int64) (*model.User, er
 // GetByUsername retrieves a user by their username
 func (r *userRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
 	query := `
-		SELECT id, username, password_hashed, display_name, avatar_url, avatar_key, bio, is_new_user,
-		       follower_count, following_count, post_count, created_at, updated_at
+		SELECT id, username, password_hashed, email, email_verified, display_name, avatar_url, avatar_key, bio, is_new_user,
+		       follower_count, following_count, post_count, created_at, updated_at, deletion_scheduled_at, is_private
 		FROM users
 		WHERE username = $1
 	`
@@ -95,9 +101,31 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*m
 	return &u, nil
 }
 
-// ExistsByUsername checks if a username is already taken
+// GetByEmail retrieves the user who verified email (case-insensitive).
+// Unverified addresses aren't unique, so they never identify an account.
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	query := `
+		SELECT id, username, password_hashed, email, email_verified, display_name, avatar_url, avatar_key, bio, is_new_user,
+		       follower_count, following_count, post_count, created_at, updated_at, deletion_scheduled_at, is_private
+		FROM users
+		WHERE LOWER(email) = LOWER($1) AND email_verified
+	`
+
+	var u model.User
+	err := r.db.GetContext(ctx, &u, query, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	return &u, nil
+}
+
+// ExistsByUsername checks if a username is already taken, ignoring case
 func (r *userRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(username) = LOWER($1))`
 
 	var exists bool
 	err := r.db.GetContext(ctx, &exists, query, username)
@@ -108,17 +136,30 @@ func (r *userRepository) ExistsByUsername(ctx context.Context, username string)
 	return exists, nil
 }
 
-func (r *userRepository) Search(ctx context.Context, query string, limit int) ([]model.UserSummary, error) {
+// ExistsByEmail checks if an email is already verified by an account (case-insensitive)
+func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(email) = LOWER($1) AND email_verified)`
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, email)
+	if err != nil {
+		return false, fmt.Errorf("failed to check email existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *userRepository) Search(ctx context.Context, query string, viewerID int64, limit int) ([]model.UserSummary, error) {
 	searchQuery := `
 		SELECT id, username, display_name, avatar_url
 		FROM users
-		WHERE username ILIKE $1
+		WHERE username ILIKE $1 AND ` + notBlocked("id", "$2") + `
 		ORDER BY follower_count DESC
-		LIMIT $2
+		LIMIT $3
 	`
 
 	var users []model.UserSummary
-	err := r.db.SelectContext(ctx, &users, searchQuery, query+"%", limit)
+	err := r.db.SelectContext(ctx, &users, searchQuery, query+"%", viewerID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search users: %w", err)
 	}
@@ -126,6 +167,46 @@ func (r *userRepository) Search(ctx context.Context, query string, limit int) ([
 	return users, nil
 }
 
+func (r *userRepository) GetSummariesByIDs(ctx context.Context, userIDs []int64) ([]model.UserSummary, error) {
+	if len(userIDs) == 0 {
+		return []model.UserSummary{}, nil
+	}
+
+	query := `
+		SELECT id, username, display_name, avatar_url
+		FROM users
+		WHERE id = ANY($1)
+	`
+
+	var users []model.UserSummary
+	err := r.db.SelectContext(ctx, &users, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user summaries: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) GetSummariesByUsernames(ctx context.Context, usernames []string) ([]model.UserSummary, error) {
+	if len(usernames) == 0 {
+		return []model.UserSummary{}, nil
+	}
+
+	query := `
+		SELECT id, username, display_name, avatar_url
+		FROM users
+		WHERE username = ANY($1)
+	`
+
+	var users []model.UserSummary
+	err := r.db.SelectContext(ctx, &users, query, pq.Array(usernames))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user summaries by username: %w", err)
+	}
+
+	return users, nil
+}
+
 func (r *userRepository) IncrementFollowerCount(ctx context.Context, tx *sqlx.Tx, userID int64, delta int) error {
 	query := `UPDATE users SET follower_count = follower_count + $1 WHERE id = $2`
 	_, err := tx.ExecContext(ctx, query, delta, userID)
@@ -152,3 +233,229 @@ func (r *userRepository) SetIsNewUser(ctx context.Context, userID int64, isNew b
 	}
 	return nil
 }
+
+func (r *userRepository) UpdateProfile(ctx context.Context, userID int64, displayName, bio *string) error {
+	query := `UPDATE users SET display_name = $1, bio = $2, updated_at = NOW() WHERE id = $3`
+	result, err := r.db.ExecContext(ctx, query, displayName, bio, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) UpdateAvatar(ctx context.Context, userID int64, avatarURL, avatarKey string) (string, error) {
+	// The subquery reads the row before the update, so the old key comes back
+	query := `
+		UPDATE users u
+		SET avatar_url = $1, avatar_key = $2, updated_at = NOW()
+		FROM (SELECT id, avatar_key FROM users WHERE id = $3 FOR UPDATE) old
+		WHERE u.id = old.id
+		RETURNING COALESCE(old.avatar_key, '')
+	`
+
+	var oldKey string
+	err := r.db.GetContext(ctx, &oldKey, query, avatarURL, avatarKey, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", model.ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to update avatar: %w", err)
+	}
+	return oldKey, nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, userID int64, passwordHashed string) error {
+	return updatePassword(ctx, r.db, userID, passwordHashed)
+}
+
+// UpdatePasswordTx is UpdatePassword as part of a larger transaction (e.g. a password reset).
+func (r *userRepository) UpdatePasswordTx(ctx context.Context, tx *sqlx.Tx, userID int64, passwordHashed string) error {
+	return updatePassword(ctx, tx, userID, passwordHashed)
+}
+
+func updatePassword(ctx context.Context, db sqlx.ExecerContext, userID int64, passwordHashed string) error {
+	query := `UPDATE users SET password_hashed = $1, updated_at = NOW() WHERE id = $2`
+	result, err := db.ExecContext(ctx, query, passwordHashed, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) SetPrivate(ctx context.Context, tx *sqlx.Tx, userID int64, private bool) error {
+	query := `UPDATE users SET is_private = $1, updated_at = NOW() WHERE id = $2`
+	result, err := tx.ExecContext(ctx, query, private, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update privacy: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) ScheduleDeletion(ctx context.Context, userID int64, at time.Time) error {
+	query := `UPDATE users SET deletion_scheduled_at = $1, updated_at = NOW() WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, at, userID)
+	if err != nil {
+		return fmt.Errorf("failed to schedule deletion: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) CancelDeletion(ctx context.Context, userID int64) error {
+	query := `UPDATE users SET deletion_scheduled_at = NULL, updated_at = NOW() WHERE id = $1 AND deletion_scheduled_at IS NOT NULL`
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to cancel deletion: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) ChangeUsername(ctx context.Context, userID int64, username string) error {
+	// Single statement so the history row and the rename commit together
+	query := `
+		WITH old AS (
+			SELECT id, username FROM users WHERE id = $1 FOR UPDATE
+		), history AS (
+			INSERT INTO username_history (user_id, old_username)
+			SELECT id, username FROM old
+		)
+		UPDATE users u
+		SET username = $2, updated_at = NOW()
+		FROM old
+		WHERE u.id = old.id
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, username)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return model.ErrUsernameExists
+		}
+		return fmt.Errorf("failed to change username: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) CountUsernameChangesSince(ctx context.Context, userID int64, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM username_history WHERE user_id = $1 AND changed_at > $2`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, userID, since); err != nil {
+		return 0, fmt.Errorf("failed to count username changes: %w", err)
+	}
+	return count, nil
+}
+
+func (r *userRepository) IsUsernameHeld(ctx context.Context, username string, exceptUserID int64, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM username_history
+			WHERE LOWER(old_username) = LOWER($1) AND user_id <> $2 AND changed_at > $3
+		)
+	`
+
+	var held bool
+	if err := r.db.GetContext(ctx, &held, query, username, exceptUserID, since); err != nil {
+		return false, fmt.Errorf("failed to check held username: %w", err)
+	}
+	return held, nil
+}
+
+func (r *userRepository) GetByPreviousUsername(ctx context.Context, username string, since time.Time) (*model.User, error) {
+	query := `
+		SELECT u.id, u.username, u.password_hashed, u.email, u.email_verified, u.display_name, u.avatar_url, u.avatar_key, u.bio, u.is_new_user,
+		       u.follower_count, u.following_count, u.post_count, u.created_at, u.updated_at, u.deletion_scheduled_at, u.is_private
+		FROM username_history h
+		JOIN users u ON u.id = h.user_id
+		WHERE LOWER(h.old_username) = LOWER($1) AND h.changed_at > $2
+		ORDER BY h.changed_at DESC
+		LIMIT 1
+	`
+
+	var u model.User
+	err := r.db.GetContext(ctx, &u, query, username, since)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by previous username: %w", err)
+	}
+
+	return &u, nil
+}
+
+func (r *userRepository) UpdateEmail(ctx context.Context, userID int64, email *string) error {
+	query := `UPDATE users SET email = $1, email_verified = false, updated_at = NOW() WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, email, userID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return model.ErrEmailExists
+		}
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
+
+// MarkEmailVerified also drops the address from other accounts that set it without
+// verifying, since the verified claim wins.
+func (r *userRepository) MarkEmailVerified(ctx context.Context, userID int64, email string) (bool, error) {
+	query := `
+		WITH verified AS (
+			UPDATE users SET email_verified = true, updated_at = NOW()
+			WHERE id = $1 AND LOWER(email) = LOWER($2)
+			RETURNING id
+		), released AS (
+			UPDATE users SET email = NULL, updated_at = NOW()
+			WHERE LOWER(email) = LOWER($2) AND id <> $1 AND NOT email_verified
+			  AND EXISTS (SELECT 1 FROM verified)
+		)
+		SELECT EXISTS (SELECT 1 FROM verified)
+	`
+	var verified bool
+	if err := r.db.GetContext(ctx, &verified, query, userID, email); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return false, model.ErrEmailExists
+		}
+		return false, fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	return verified, nil
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+
+	"iamstagram_22520060/internal/cache"
+	"iamstagram_22520060/internal/config"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
+)
+
+// AccountService purges accounts whose deletion grace period has ended.
+// Scheduling and cancelling live in UserService (DELETE /me and login).
+type AccountService struct {
+	accountRepo  repository.AccountRepository
+	followRepo   repository.FollowRepository
+	repostRepo   repository.RepostRepository
+	feedCache    cache.FeedCache
+	mediaService *MediaService
+	db           *sqlx.DB
+	config       *config.Config
+}
+
+func NewAccountService(
+	accountRepo repository.AccountRepository,
+	followRepo repository.FollowRepository,
+	repostRepo repository.RepostRepository,
+	feedCache cache.FeedCache,
+	mediaService *MediaService,
+	db *sqlx.DB,
+	cfg *config.Config,
+) *AccountService {
+	return &AccountService{
+		accountRepo:  accountRepo,
+		followRepo:   followRepo,
+		repostRepo:   repostRepo,
+		feedCache:    feedCache,
+		mediaService: mediaService,
+		db:           db,
+		config:       cfg,
+	}
+}
+
+// PurgeDueAccounts deletes up to AccountPurgeBatchSize accounts that are due.
+// Runs as a scheduler job; an account that fails is retried on the next run.
+func (s *AccountService) PurgeDueAccounts(ctx context.Context) error {
+	userIDs, err := s.accountRepo.GetDueDeletions(ctx, model.AccountPurgeBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	var purged, failed int
+	for _, userID := range userIDs {
+		if err := s.purgeAccount(ctx, userID); err != nil {
+			log.Printf("[AccountService] Purge FAILED: user=%d err=%v", userID, err)
+			failed++
+			continue
+		}
+		purged++
+	}
+
+	log.Printf("[AccountService] Purge run DONE: due=%d purged=%d failed=%d", len(userIDs), purged, failed)
+	return nil
+}
+
+// purgeAccount removes one account. The feeds holding the user's posts and reposts are
+// looked up before the database delete, because the follow and repost rows needed to find
+// them go with the user; the caches are only cleaned once the purge has committed.
+// R2 objects are deleted last, once the rows pointing at them are gone.
+func (s *AccountService) purgeAccount(ctx context.Context, userID int64) error {
+	keys, err := s.accountRepo.GetMediaKeys(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.getFeedEntries(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get feed entries: %w", err)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	purged, err := s.accountRepo.Purge(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	if !purged {
+		log.Printf("[AccountService] Purge skipped, deletion was cancelled: user=%d", userID)
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	s.removeFromFeeds(ctx, userID, entries)
+
+	// Orphaned objects only cost storage, so failures are logged and not retried
+	var deleted int
+	for _, key := range keys {
+		if key == s.config.DefaultAvatarKey {
+			continue
+		}
+		if err := s.mediaService.DeleteObject(ctx, key); err != nil {
+			log.Printf("[AccountService] Failed to delete object: user=%d key=%s err=%v", userID, key, err)
+			continue
+		}
+		deleted++
+	}
+
+	log.Printf("[AccountService] Purged user=%d objects=%d/%d", userID, deleted, len(keys))
+	return nil
+}
+
+// feedEntries lists the feed caches that hold a user's posts and reposts.
+type feedEntries struct {
+	followers []int64
+	// postFeeds maps each of the user's live posts to the feeds it reached: the user's
+	// followers and the followers of everyone who reposted it
+	postFeeds map[int64][][]int64
+	reposts   []cache.PostScore
+}
+
+// getFeedEntries collects the feeds to clean when the user is purged.
+func (s *AccountService) getFeedEntries(ctx context.Context, userID int64) (*feedEntries, error) {
+	followers, err := s.followRepo.GetFollowerIDs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get followers: %w", err)
+	}
+
+	postIDs, err := s.accountRepo.GetLivePostIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reposts, err := s.repostRepo.GetRecentRepostsByUser(ctx, userID, cache.FeedCacheCap)
+	if err != nil {
+		return nil, fmt.Errorf("get reposts: %w", err)
+	}
+
+	entries := &feedEntries{
+		followers: followers,
+		postFeeds: make(map[int64][][]int64, len(postIDs)),
+		reposts:   reposts,
+	}
+
+	// Posts reach feeds through the author's followers and through every reposter's followers
+	reposterFollowers := make(map[int64][]int64)
+	for _, postID := range postIDs {
+		feeds := [][]int64{followers}
+
+		reposters, err := s.repostRepo.GetReposterIDs(ctx, postID)
+		if err != nil {
+			return nil, fmt.Errorf("get reposters: %w", err)
+		}
+		for _, reposterID := range reposters {
+			ids, ok := reposterFollowers[reposterID]
+			if !ok {
+				ids, err = s.followRepo.GetFollowerIDs(ctx, reposterID)
+				if err != nil {
+					return nil, fmt.Errorf("get followers of reposter: %w", err)
+				}
+				reposterFollowers[reposterID] = ids
+			}
+			feeds = append(feeds, ids)
+		}
+		entries.postFeeds[postID] = feeds
+	}
+
+	return entries, nil
+}
+
+// removeFromFeeds takes the user's posts and reposts out of other users' feed caches
+// and drops the user's own feed. Redis failures are logged: a stale ID in a feed is
+// skipped at hydration since the post no longer exists.
+func (s *AccountService) removeFromFeeds(ctx context.Context, userID int64, entries *feedEntries) {
+	var failCount int
+
+	for postID, feeds := range entries.postFeeds {
+		for _, feedOwnerIDs := range feeds {
+			failCount += s.removePost(ctx, feedOwnerIDs, postID)
+		}
+	}
+
+	// The user's reposts of other people's posts
+	for _, p := range entries.reposts {
+		for _, followerID := range entries.followers {
+			if err := s.feedCache.RemoveRepost(ctx, followerID, p.PostID, userID); err != nil {
+				failCount++
+			}
+		}
+	}
+
+	if err := s.feedCache.Clear(ctx, userID); err != nil {
+		failCount++
+	}
+
+	log.Printf("[AccountService] Removed from feeds: user=%d followers=%d posts=%d reposts=%d failed=%d",
+		userID, len(entries.followers), len(entries.postFeeds), len(entries.reposts), failCount)
+}
+
+// removePost removes a post from each of the given feeds and returns the number of failures.
+func (s *AccountService) removePost(ctx context.Context, feedOwnerIDs []int64, postID int64) int {
+	var failCount int
+	for _, feedOwnerID := range feedOwnerIDs {
+		if err := s.feedCache.RemovePost(ctx, feedOwnerID, postID); err != nil {
+			failCount++
+		}
+	}
+	return failCount
+}
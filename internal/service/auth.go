@@ -36,7 +36,7 @@ func (s *AuthService) GenerateTokenPair(ctx context.Context, userID int64, devic
 	}
 
 	refreshTokenRaw := uuid.New().String()
-	refreshTokenHash := s.hashToken(refreshTokenRaw)
+	refreshTokenHash := hashToken(refreshTokenRaw)
 
 	refreshToken := &model.RefreshToken{
 		UserID:    userID,
@@ -64,7 +64,7 @@ func (s *AuthService) GenerateTokenPair(ctx context.Context, userID int64, devic
 
 // RefreshTokens validates the refresh token and rotates a new pair.
 func (s *AuthService) RefreshTokens(ctx context.Context, refreshTokenRaw, deviceInfo, ipAddress string) (*model.TokenPair, int64, error) {
-	tokenHash := s.hashToken(refreshTokenRaw)
+	tokenHash := hashToken(refreshTokenRaw)
 
 	token, err := s.refreshTokenRepo.FindByTokenHash(ctx, tokenHash)
 	if err != nil {
@@ -86,7 +86,7 @@ func (s *AuthService) RefreshTokens(ctx context.Context, refreshTokenRaw, device
 		return nil, 0, err
 	}
 
-	newTokenHash := s.hashToken(newTokenPair.RefreshToken)
+	newTokenHash := hashToken(newTokenPair.RefreshToken)
 	var replacedByID *string
 	if newToken, err := s.refreshTokenRepo.FindByTokenHash(ctx, newTokenHash); err == nil && newToken != nil {
 		replacedByID = &newToken.ID
@@ -100,7 +100,7 @@ func (s *AuthService) RefreshTokens(ctx context.Context, refreshTokenRaw, device
 }
 
 func (s *AuthService) RevokeRefreshToken(ctx context.Context, refreshTokenRaw string) error {
-	tokenHash := s.hashToken(refreshTokenRaw)
+	tokenHash := hashToken(refreshTokenRaw)
 	token, err := s.refreshTokenRepo.FindByTokenHash(ctx, tokenHash)
 	if err != nil {
 		return err
@@ -130,7 +130,9 @@ func (s *AuthService) generateAccessToken(userID int64) (string, error) {
 	return token.SignedString([]byte(s.config.JWTSecret))
 }
 
-func (s *AuthService) hashToken(token string) string {
+// hashToken returns the hex SHA-256 of a raw token. Only hashes are stored
+// (refresh tokens, one-time email tokens), never the raw value.
+func hashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
 	return hex.EncodeToString(hash[:])
 }
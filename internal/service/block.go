@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/queue"
+	"iamstagram_22520060/internal/repository"
+)
+
+// BlockService handles blocking. A block works both ways: neither user sees the other's
+// profile, posts, comments, likes or notifications, and any follow between them is removed.
+type BlockService struct {
+	blockRepo  repository.BlockRepository
+	followRepo repository.FollowRepository
+	userRepo   repository.UserRepository
+	db         *sqlx.DB
+	publisher  queue.Publisher
+}
+
+func NewBlockService(
+	blockRepo repository.BlockRepository,
+	followRepo repository.FollowRepository,
+	userRepo repository.UserRepository,
+	db *sqlx.DB,
+	publisher queue.Publisher,
+) *BlockService {
+	return &BlockService{
+		blockRepo:  blockRepo,
+		followRepo: followRepo,
+		userRepo:   userRepo,
+		db:         db,
+		publisher:  publisher,
+	}
+}
+
+// Block blocks blockedID and removes follows and pending follow requests in both directions.
+func (s *BlockService) Block(ctx context.Context, blockerID, blockedID int64) error {
+	if blockerID == blockedID {
+		return model.ErrCannotBlockSelf
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, blockedID); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	created, err := s.blockRepo.Create(ctx, tx, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return model.ErrAlreadyBlocked
+	}
+
+	if err := s.removeFollow(ctx, tx, blockerID, blockedID); err != nil {
+		return err
+	}
+	if err := s.removeFollow(ctx, tx, blockedID, blockerID); err != nil {
+		return err
+	}
+
+	if err := s.followRepo.DeleteRequestsBetween(ctx, tx, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	// Purge each user's posts and reposts from the other's feed, the same way as an unfollow.
+	// Published even without a follow: the posts may have been cached before it ended.
+	s.publishUnfollowed(ctx, blockerID, blockedID)
+	s.publishUnfollowed(ctx, blockedID, blockerID)
+
+	log.Printf("[BlockService] User %d blocked %d", blockerID, blockedID)
+	return nil
+}
+
+// removeFollow deletes the follow (if any) and fixes both users' counts.
+func (s *BlockService) removeFollow(ctx context.Context, tx *sqlx.Tx, followerID, followeeID int64) error {
+	if err := s.followRepo.Delete(ctx, tx, followerID, followeeID); err != nil {
+		if errors.Is(err, model.ErrNotFollowing) {
+			return nil
+		}
+		return err
+	}
+
+	if err := s.userRepo.IncrementFollowerCount(ctx, tx, followeeID, -1); err != nil {
+		return err
+	}
+	return s.userRepo.IncrementFollowingCount(ctx, tx, followerID, -1)
+}
+
+func (s *BlockService) publishUnfollowed(ctx context.Context, followerID, followeeID int64) {
+	if s.publisher == nil {
+		return
+	}
+
+	event := queue.NewUserUnfollowedEvent(followerID, followeeID)
+	if _, err := s.publisher.Publish(ctx, queue.StreamFeed, event); err != nil {
+		log.Printf("[BlockService] Failed to publish UserUnfollowed event: follower=%d followee=%d err=%v",
+			followerID, followeeID, err)
+	}
+}
+
+// Unblock lifts the block. Removed follows are not restored.
+func (s *BlockService) Unblock(ctx context.Context, blockerID, blockedID int64) error {
+	return s.blockRepo.Delete(ctx, blockerID, blockedID)
+}
+
+// GetBlocked lists the users the user has blocked, most recently blocked first.
+func (s *BlockService) GetBlocked(ctx context.Context, userID int64, cursor *time.Time, limit int) (*model.FollowListResponse, error) {
+	users, nextCursor, err := s.blockRepo.GetBlocked(ctx, userID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextCursorStr *string
+	if nextCursor != nil {
+		str := nextCursor.Format(time.RFC3339)
+		nextCursorStr = &str
+	}
+
+	return &model.FollowListResponse{
+		Users:      users,
+		NextCursor: nextCursorStr,
+		HasMore:    nextCursor != nil,
+	}, nil
+}
+
+// checkNotBlocked returns ErrUserNotFound if the viewer blocked the user or was blocked by
+// them, so blocked users look like they don't exist. Anonymous viewers are never blocked.
+func checkNotBlocked(ctx context.Context, blockRepo repository.BlockRepository, userID int64, viewerID *int64) error {
+	if viewerID == nil || *viewerID == userID {
+		return nil
+	}
+
+	blocked, err := blockRepo.IsBlocked(ctx, *viewerID, userID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
+)
+
+// mockBlockRepository only implements the lookups; other methods panic through the nil interface.
+type mockBlockRepository struct {
+	repository.BlockRepository
+	blocks map[[2]int64]bool // {blocker, blocked}
+}
+
+func (m *mockBlockRepository) IsBlocked(ctx context.Context, userID, otherID int64) (bool, error) {
+	return m.blocks[[2]int64{userID, otherID}] || m.blocks[[2]int64{otherID, userID}], nil
+}
+
+func (m *mockBlockRepository) GetBlockedIDs(ctx context.Context, userID int64) ([]int64, error) {
+	var ids []int64
+	for pair := range m.blocks {
+		switch userID {
+		case pair[0]:
+			ids = append(ids, pair[1])
+		case pair[1]:
+			ids = append(ids, pair[0])
+		}
+	}
+	return ids, nil
+}
+
+func TestCheckNotBlocked(t *testing.T) {
+	// User 1 blocked user 2
+	blockRepo := &mockBlockRepository{blocks: map[[2]int64]bool{{1, 2}: true}}
+	viewer := func(id int64) *int64 { return &id }
+
+	tests := []struct {
+		name     string
+		userID   int64
+		viewerID *int64
+		wantErr  error
+	}{
+		{"anonymous", 2, nil, nil},
+		{"self", 1, viewer(1), nil},
+		{"stranger", 2, viewer(3), nil},
+		{"blocker views blocked", 2, viewer(1), model.ErrUserNotFound},
+		{"blocked views blocker", 1, viewer(2), model.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkNotBlocked(context.Background(), blockRepo, tt.userID, tt.viewerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
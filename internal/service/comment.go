@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -16,27 +18,41 @@ type CommentService struct {
 	commentRepo repository.CommentRepository
 	postRepo    repository.PostRepository
 	userRepo    repository.UserRepository
+	blockRepo   repository.BlockRepository
+	mentionRepo repository.MentionRepository
+	filterRepo  repository.CommentFilterRepository
 	db          *sqlx.DB
 	publisher   queue.Publisher
+	editWindow  time.Duration // 0 = comments can always be edited
 }
 
 func NewCommentService(
 	commentRepo repository.CommentRepository,
 	postRepo repository.PostRepository,
 	userRepo repository.UserRepository,
+	blockRepo repository.BlockRepository,
+	mentionRepo repository.MentionRepository,
+	filterRepo repository.CommentFilterRepository,
 	db *sqlx.DB,
 	publisher queue.Publisher,
+	editWindow time.Duration,
 ) *CommentService {
 	return &CommentService{
 		commentRepo: commentRepo,
 		postRepo:    postRepo,
 		userRepo:    userRepo,
+		blockRepo:   blockRepo,
+		mentionRepo: mentionRepo,
+		filterRepo:  filterRepo,
 		db:          db,
 		publisher:   publisher,
+		editWindow:  editWindow,
 	}
 }
 
 // Create adds a comment to a post. Uses transaction: insert comment + increment counter.
+// Comments caught by the post author's filters or the spam check are stored hidden
+// and send no notifications.
 func (s *CommentService) Create(ctx context.Context, postID, userID int64, req model.CreateCommentRequest) (*model.Comment, error) {
 	// Validate content
 	if len(req.Content) == 0 {
@@ -46,18 +62,22 @@ func (s *CommentService) Create(ctx context.Context, postID, userID int64, req m
 		return nil, model.ErrContentTooLong
 	}
 
-	// Verify post exists
-	exists, err := s.postRepo.Exists(ctx, postID)
+	// Verify post exists and accepts comments (existing comments stay readable)
+	disabled, err := s.postRepo.CommentsDisabled(ctx, postID)
 	if err != nil {
-		return nil, fmt.Errorf("check post exists: %w", err)
+		return nil, err
+	}
+	if disabled {
+		return nil, model.ErrCommentsDisabled
 	}
-	if !exists {
-		return nil, model.ErrPostNotFound
+	if err := s.checkPostVisible(ctx, postID, &userID); err != nil {
+		return nil, err
 	}
 
 	// If parent comment provided, verify it exists and belongs to same post
 	// Facebook-style: if replying to a reply, flatten to top-level and prepend @mention
 	var actualParentID *int64 = req.ParentCommentID
+	var parentAuthorID int64 // Author of the (top-level) comment being replied to
 	if req.ParentCommentID != nil {
 		parent, err := s.commentRepo.GetByID(ctx, *req.ParentCommentID)
 		if err != nil {
@@ -67,10 +87,18 @@ func (s *CommentService) Create(ctx context.Context, postID, userID int64, req m
 			return nil, fmt.Errorf("parent comment does not belong to this post")
 		}
 
+		parentAuthorID = parent.UserID
+
 		// If parent is already a reply, flatten: reply to the top-level comment instead
 		if parent.ParentCommentID != nil {
 			actualParentID = parent.ParentCommentID
 
+			topLevel, err := s.commentRepo.GetByID(ctx, *actualParentID)
+			if err != nil {
+				return nil, err
+			}
+			parentAuthorID = topLevel.UserID
+
 			// Prepend @username mention to the content
 			parentAuthor, err := s.userRepo.GetByID(ctx, parent.UserID)
 			if err == nil {
@@ -79,6 +107,25 @@ func (s *CommentService) Create(ctx context.Context, postID, userID int64, req m
 		}
 	}
 
+	mentions, err := resolveMentions(ctx, s.userRepo, s.blockRepo, userID, req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	authorID, err := s.postRepo.GetAuthorID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The post author's own comments are never filtered
+	var hiddenReason *string
+	if authorID != userID {
+		hiddenReason, err = s.checkNewComment(ctx, authorID, postID, userID, req.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("begin transaction: %w", err)
@@ -86,11 +133,16 @@ func (s *CommentService) Create(ctx context.Context, postID, userID int64, req m
 	defer tx.Rollback()
 
 	// Insert comment (use actualParentID which may be flattened)
-	comment, err := s.commentRepo.Create(ctx, tx, postID, userID, req.Content, actualParentID)
+	comment, err := s.commentRepo.Create(ctx, tx, postID, userID, req.Content, actualParentID, hiddenReason)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.mentionRepo.ReplaceCommentMentions(ctx, tx, postID, comment.ID, mentions); err != nil {
+		return nil, err
+	}
+	comment.Mentions = mentions
+
 	// Increment comment count
 	if err := s.postRepo.IncrementCommentCount(ctx, tx, postID, 1); err != nil {
 		return nil, err
@@ -111,17 +163,33 @@ func (s *CommentService) Create(ctx context.Context, postID, userID int64, req m
 		}
 	}
 
+	if hiddenReason != nil {
+		log.Printf("[CommentService] User %d commented on post %d (hidden: %s)", userID, postID, *hiddenReason)
+		return comment, nil
+	}
+
 	log.Printf("[CommentService] User %d commented on post %d", userID, postID)
 
-	// Publish notification event (after commit, best-effort)
+	// Publish notification events (after commit, best-effort)
 	if s.publisher != nil {
-		authorID, err := s.postRepo.GetAuthorID(ctx, postID)
-		if err == nil && authorID != userID {
+		// A post author replied to on their own post only gets the reply notification
+		if authorID != userID && authorID != parentAuthorID {
 			event := queue.NewPostCommentedEvent(postID, comment.ID, userID, authorID)
 			if _, err := s.publisher.Publish(ctx, queue.StreamFeed, event); err != nil {
 				log.Printf("[CommentService] Failed to publish PostCommented event: %v", err)
 			}
 		}
+
+		if actualParentID != nil && parentAuthorID != userID {
+			event := queue.NewCommentRepliedEvent(postID, *actualParentID, userID, parentAuthorID)
+			if _, err := s.publisher.Publish(ctx, queue.StreamFeed, event); err != nil {
+				log.Printf("[CommentService] Failed to publish CommentReplied event: %v", err)
+			}
+		}
+
+		// The post and parent comment authors already get a comment/reply notification
+		commentID := comment.ID
+		publishMentionEvents(ctx, s.publisher, postID, &commentID, userID, newMentionRecipients(nil, mentions, userID, authorID, parentAuthorID))
 	}
 
 	return comment, nil
@@ -154,7 +222,8 @@ func (s *CommentService) Delete(ctx context.Context, commentID, userID int64) er
 	return nil
 }
 
-// Update updates a comment's content.
+// Update updates a comment's content within the edit window. The previous version is kept
+// for the post author, and mentions are re-parsed.
 func (s *CommentService) Update(ctx context.Context, commentID, userID int64, req model.UpdateCommentRequest) (*model.Comment, error) {
 	// Validate content
 	if len(req.Content) == 0 {
@@ -164,12 +233,66 @@ func (s *CommentService) Update(ctx context.Context, commentID, userID int64, re
 		return nil, model.ErrContentTooLong
 	}
 
+	current, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if current.UserID != userID {
+		return nil, model.ErrNotCommentOwner
+	}
+	if s.editWindow > 0 && time.Since(current.CreatedAt) > s.editWindow {
+		return nil, model.ErrEditWindowExpired
+	}
+
+	// Edits go through the post author's filters too (a hidden comment stays hidden)
+	var hiddenReason *string
+	authorID, err := s.postRepo.GetAuthorID(ctx, current.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if authorID != userID {
+		filters, err := s.filterRepo.Get(ctx, authorID)
+		if err != nil {
+			return nil, err
+		}
+		hiddenReason = commentHiddenReason(req.Content, filters)
+	}
+
+	mentions, err := resolveMentions(ctx, s.userRepo, s.blockRepo, userID, req.Content)
+	if err != nil {
+		return nil, err
+	}
+	prevMentions, err := s.mentionRepo.GetCommentMentions(ctx, []int64{commentID})
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	// Update comment (repository handles ownership check)
-	comment, err := s.commentRepo.Update(ctx, commentID, userID, req.Content)
+	comment, err := s.commentRepo.Update(ctx, tx, commentID, userID, req.Content, hiddenReason)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.mentionRepo.ReplaceCommentMentions(ctx, tx, comment.PostID, commentID, mentions); err != nil {
+		return nil, err
+	}
+	comment.Mentions = mentions
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	// Only users newly mentioned by this edit are notified
+	if comment.HiddenReason == nil {
+		publishMentionEvents(ctx, s.publisher, comment.PostID, &commentID, userID, newMentionRecipients(prevMentions[commentID], mentions, userID))
+	}
+
 	// Fetch author info
 	author, err := s.userRepo.GetByID(ctx, userID)
 	if err == nil {
@@ -181,43 +304,420 @@ func (s *CommentService) Update(ctx context.Context, commentID, userID int64, re
 		}
 	}
 
+	likeStatus, err := s.commentRepo.CheckLikes(ctx, userID, []int64{commentID})
+	if err != nil {
+		log.Printf("[CommentService] Failed to check like status: %v", err)
+	} else {
+		comment.IsLiked = likeStatus[commentID]
+	}
+
 	log.Printf("[CommentService] User %d updated comment %d", userID, commentID)
 	return comment, nil
 }
 
-// GetByPostID returns paginated comments for a post.
-func (s *CommentService) GetByPostID(ctx context.Context, postID int64, cursor *string, limit int) (*model.CommentListResponse, error) {
-	if limit <= 0 {
-		limit = 10
+// GetEditHistory returns a comment with its previous versions. Only the post author can see it.
+func (s *CommentService) GetEditHistory(ctx context.Context, postID, commentID, userID int64) (*model.CommentEditHistoryResponse, error) {
+	if err := s.checkPostOwner(ctx, postID, userID); err != nil {
+		return nil, err
 	}
-	if limit > 50 {
-		limit = 50
+
+	comment, err := s.commentRepo.GetWithAuthor(ctx, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.PostID != postID {
+		return nil, model.ErrCommentNotFound
+	}
+
+	edits, err := s.commentRepo.GetEdits(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CommentEditHistoryResponse{
+		Comment: *comment,
+		Edits:   edits,
+	}, nil
+}
+
+// Like adds a like to a comment. Uses transaction: insert like + increment counter.
+func (s *CommentService) Like(ctx context.Context, postID, commentID, userID int64) error {
+	if err := s.checkPostVisible(ctx, postID, &userID); err != nil {
+		return err
+	}
+
+	comment, err := s.getVisiblePostComment(ctx, postID, commentID, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Insert like (fails if already liked)
+	if err := s.commentRepo.Like(ctx, tx, commentID, userID); err != nil {
+		return err
+	}
+
+	// Increment like count
+	if err := s.commentRepo.IncrementLikeCount(ctx, tx, commentID, 1); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	log.Printf("[CommentService] User %d liked comment %d", userID, commentID)
+
+	// Publish notification event (after commit, best-effort)
+	if s.publisher != nil && comment.UserID != userID {
+		event := queue.NewCommentLikedEvent(postID, commentID, userID, comment.UserID)
+		if _, err := s.publisher.Publish(ctx, queue.StreamFeed, event); err != nil {
+			log.Printf("[CommentService] Failed to publish CommentLiked event: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Unlike removes a like from a comment. Uses transaction: delete like + decrement counter.
+func (s *CommentService) Unlike(ctx context.Context, postID, commentID, userID int64) error {
+	if err := s.checkPostVisible(ctx, postID, &userID); err != nil {
+		return err
+	}
+
+	if _, err := s.getVisiblePostComment(ctx, postID, commentID, userID); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Delete like (fails if not liked)
+	if err := s.commentRepo.Unlike(ctx, tx, commentID, userID); err != nil {
+		return err
+	}
+
+	// Decrement like count
+	if err := s.commentRepo.IncrementLikeCount(ctx, tx, commentID, -1); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	log.Printf("[CommentService] User %d unliked comment %d", userID, commentID)
+	return nil
+}
+
+// Pin pins a top-level comment on the user's post, replacing any previous pin.
+func (s *CommentService) Pin(ctx context.Context, postID, commentID, userID int64) error {
+	if err := s.checkPostOwner(ctx, postID, userID); err != nil {
+		return err
 	}
 
-	// Verify post exists
-	exists, err := s.postRepo.Exists(ctx, postID)
+	comment, err := s.getPostComment(ctx, postID, commentID)
 	if err != nil {
-		return nil, fmt.Errorf("check post exists: %w", err)
+		return err
+	}
+	if comment.ParentCommentID != nil {
+		return model.ErrCannotPinReply
+	}
+
+	if err := s.postRepo.SetPinnedComment(ctx, postID, commentID); err != nil {
+		return err
+	}
+
+	log.Printf("[CommentService] User %d pinned comment %d on post %d", userID, commentID, postID)
+	return nil
+}
+
+// Unpin removes the pin from a comment on the user's post.
+func (s *CommentService) Unpin(ctx context.Context, postID, commentID, userID int64) error {
+	if err := s.checkPostOwner(ctx, postID, userID); err != nil {
+		return err
+	}
+
+	if err := s.postRepo.UnpinComment(ctx, postID, commentID); err != nil {
+		return err
+	}
+
+	log.Printf("[CommentService] User %d unpinned comment %d on post %d", userID, commentID, postID)
+	return nil
+}
+
+// checkPostOwner returns ErrNotPostOwner unless userID wrote the post.
+func (s *CommentService) checkPostOwner(ctx context.Context, postID, userID int64) error {
+	authorID, err := s.postRepo.GetAuthorID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if authorID != userID {
+		return model.ErrNotPostOwner
+	}
+	return nil
+}
+
+// getPostComment returns a comment, reporting comments of other posts as not found.
+func (s *CommentService) getPostComment(ctx context.Context, postID, commentID int64) (*model.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.PostID != postID {
+		return nil, model.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// getVisiblePostComment is getPostComment for comments the viewer must be able to see:
+// hidden comments and comments by blocked users are reported as not found too.
+func (s *CommentService) getVisiblePostComment(ctx context.Context, postID, commentID, viewerID int64) (*model.Comment, error) {
+	comment, err := s.commentRepo.GetWithAuthor(ctx, commentID, viewerID)
+	if err != nil {
+		return nil, err
 	}
-	if !exists {
-		return nil, model.ErrPostNotFound
+	if comment.PostID != postID {
+		return nil, model.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// GetByPostID returns paginated top-level comments for a post, each with its reply count
+// and first few replies, plus the viewer's like status.
+// The pinned comment (if any) comes first on the first page and is not repeated later.
+func (s *CommentService) GetByPostID(ctx context.Context, postID int64, viewerID *int64, cursor *string, limit int) (*model.CommentListResponse, error) {
+	limit = clampCommentLimit(limit)
+
+	if err := s.checkPostVisible(ctx, postID, viewerID); err != nil {
+		return nil, err
+	}
+
+	// Get the post's pin (ErrPostNotFound if it was deleted meanwhile)
+	pinnedID, err := s.postRepo.GetPinnedCommentID(ctx, postID)
+	if err != nil {
+		return nil, err
 	}
 
-	comments, nextCursor, err := s.commentRepo.GetByPostID(ctx, postID, cursor, limit)
+	viewer := viewerOrAnonymous(viewerID)
+	comments, nextCursor, err := s.commentRepo.GetByPostID(ctx, postID, viewer, cursor, limit)
 	if err != nil {
 		return nil, fmt.Errorf("get comments: %w", err)
 	}
 
-	hasMore := nextCursor != nil
+	if pinnedID != nil && cursor == nil {
+		pinned, err := s.commentRepo.GetWithAuthor(ctx, *pinnedID, viewer)
+		switch {
+		case err == nil:
+			pinned.IsPinned = true
+			comments = append([]model.Comment{*pinned}, comments...)
+		case !errors.Is(err, model.ErrCommentNotFound): // Deleted since we read the pin, or hidden from the viewer
+			return nil, fmt.Errorf("get pinned comment: %w", err)
+		}
+	}
+
+	// Embed reply previews for comments that have replies
+	var parentIDs []int64
+	for _, c := range comments {
+		if c.ReplyCount > 0 {
+			parentIDs = append(parentIDs, c.ID)
+		}
+	}
+	if len(parentIDs) > 0 {
+		previews, err := s.commentRepo.GetReplyPreviews(ctx, parentIDs, viewer, model.CommentReplyPreviewCount)
+		if err != nil {
+			return nil, fmt.Errorf("get reply previews: %w", err)
+		}
+		for i := range comments {
+			comments[i].Replies = previews[comments[i].ID]
+		}
+	}
+
+	s.attachLikeStatus(ctx, viewerID, comments)
+
+	return &model.CommentListResponse{
+		Comments:   comments,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != nil,
+	}, nil
+}
+
+// GetReplies returns paginated replies of a comment, oldest first, with the viewer's like status.
+func (s *CommentService) GetReplies(ctx context.Context, postID, commentID int64, viewerID *int64, cursor *string, limit int) (*model.CommentListResponse, error) {
+	limit = clampCommentLimit(limit)
+
+	if err := s.checkPostVisible(ctx, postID, viewerID); err != nil {
+		return nil, err
+	}
+
+	viewer := viewerOrAnonymous(viewerID)
+	parent, err := s.commentRepo.GetWithAuthor(ctx, commentID, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if parent.PostID != postID {
+		return nil, model.ErrCommentNotFound
+	}
+
+	replies, nextCursor, err := s.commentRepo.GetReplies(ctx, commentID, viewer, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get replies: %w", err)
+	}
+	s.attachLikeStatus(ctx, viewerID, replies)
+
+	return &model.CommentListResponse{
+		Comments:   replies,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != nil,
+	}, nil
+}
+
+// ListHidden returns the hidden comments on the user's post for review, newest first.
+func (s *CommentService) ListHidden(ctx context.Context, postID, userID int64, cursor *string, limit int) (*model.CommentListResponse, error) {
+	limit = clampCommentLimit(limit)
+
+	if err := s.checkPostOwner(ctx, postID, userID); err != nil {
+		return nil, err
+	}
 
-	var finalCursor *string
-	if hasMore {
-		finalCursor = nextCursor
+	comments, nextCursor, err := s.commentRepo.GetHidden(ctx, postID, userID, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get hidden comments: %w", err)
 	}
+	s.attachLikeStatus(ctx, &userID, comments)
 
 	return &model.CommentListResponse{
 		Comments:   comments,
-		NextCursor: finalCursor,
-		HasMore:    hasMore,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != nil,
 	}, nil
 }
+
+// Unhide makes a hidden comment on the user's post visible to everyone.
+// Notifications skipped when the comment was hidden are not sent afterwards.
+func (s *CommentService) Unhide(ctx context.Context, postID, commentID, userID int64) error {
+	if err := s.checkPostOwner(ctx, postID, userID); err != nil {
+		return err
+	}
+
+	if err := s.commentRepo.Unhide(ctx, postID, commentID); err != nil {
+		return err
+	}
+
+	log.Printf("[CommentService] User %d unhid comment %d on post %d", userID, commentID, postID)
+	return nil
+}
+
+// GetFilters returns the user's comment filters.
+func (s *CommentService) GetFilters(ctx context.Context, userID int64) (*model.CommentFilterSettings, error) {
+	return s.filterRepo.Get(ctx, userID)
+}
+
+// UpdateFilters replaces the user's comment filters. They apply to new comments and edits only.
+func (s *CommentService) UpdateFilters(ctx context.Context, userID int64, req model.UpdateCommentFiltersRequest) (*model.CommentFilterSettings, error) {
+	words, err := normalizeHiddenWords(req.HiddenWords)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &model.CommentFilterSettings{
+		UserID:        userID,
+		HiddenWords:   words,
+		HideOffensive: req.HideOffensive,
+	}
+	if err := s.filterRepo.Upsert(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[CommentService] User %d updated comment filters (%d words)", userID, len(words))
+	return settings, nil
+}
+
+// checkNewComment runs a new comment through the post author's filters and the spam check.
+// Returns the reason to hide it, or nil.
+func (s *CommentService) checkNewComment(ctx context.Context, postAuthorID, postID, userID int64, content string) (*string, error) {
+	filters, err := s.filterRepo.Get(ctx, postAuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if reason := commentHiddenReason(content, filters); reason != nil {
+		return reason, nil
+	}
+
+	// Same text on several other posts within a short window looks like spam
+	since := time.Now().Add(-model.SpamRepeatWindow)
+	count, err := s.commentRepo.CountRecentDuplicatePosts(ctx, userID, postID, content, since)
+	if err != nil {
+		return nil, err
+	}
+	if count >= model.SpamRepeatOtherPosts {
+		reason := model.HiddenReasonSpam
+		return &reason, nil
+	}
+	return nil, nil
+}
+
+// checkPostVisible returns ErrPostNotFound for posts of private accounts the viewer doesn't follow.
+func (s *CommentService) checkPostVisible(ctx context.Context, postID int64, viewerID *int64) error {
+	visible, err := s.postRepo.IsVisible(ctx, postID, viewerOrAnonymous(viewerID))
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return model.ErrPostNotFound
+	}
+	return nil
+}
+
+// viewerOrAnonymous returns the viewer's ID, or 0 for anonymous requests.
+func viewerOrAnonymous(viewerID *int64) int64 {
+	if viewerID == nil {
+		return 0
+	}
+	return *viewerID
+}
+
+// attachLikeStatus sets IsLiked on comments and their embedded replies (best-effort).
+func (s *CommentService) attachLikeStatus(ctx context.Context, viewerID *int64, comments []model.Comment) {
+	if viewerID == nil || len(comments) == 0 {
+		return
+	}
+
+	var commentIDs []int64
+	for _, c := range comments {
+		commentIDs = append(commentIDs, c.ID)
+		for _, reply := range c.Replies {
+			commentIDs = append(commentIDs, reply.ID)
+		}
+	}
+
+	likeStatus, err := s.commentRepo.CheckLikes(ctx, *viewerID, commentIDs)
+	if err != nil {
+		log.Printf("[CommentService] Failed to check likes: %v", err)
+		return
+	}
+	for i := range comments {
+		comments[i].IsLiked = likeStatus[comments[i].ID]
+		for j := range comments[i].Replies {
+			comments[i].Replies[j].IsLiked = likeStatus[comments[i].Replies[j].ID]
+		}
+	}
+}
+
+func clampCommentLimit(limit int) int {
+	if limit <= 0 {
+		return 10
+	}
+	if limit > 50 {
+		return 50
+	}
+	return limit
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"iamstagram_22520060/internal/model"
+)
+
+// offensiveTerms is the built-in list used when a post author turns on hide_offensive.
+// Terms are lowercase and matched as whole words/phrases.
+var offensiveTerms = []string{
+	// English
+	"fuck", "fucking", "motherfucker", "shit", "bitch", "bastard", "asshole",
+	"dickhead", "cunt", "retard", "slut", "whore", "kill yourself", "kys",
+	// Vietnamese
+	"đm", "đmm", "dm", "dmm", "vcl", "vkl", "vãi lồn", "địt", "đụ", "lồn", "cặc",
+	"đĩ", "óc chó", "ngu như chó", "con chó", "thằng chó", "đồ khốn",
+}
+
+// commentHiddenReason returns why a new comment should be hidden, or nil if it can be shown.
+// Hidden words are checked before the built-in offensive terms.
+func commentHiddenReason(content string, filters *model.CommentFilterSettings) *string {
+	if filters == nil {
+		return nil
+	}
+
+	for _, word := range filters.HiddenWords {
+		if containsTerm(content, word) {
+			reason := model.HiddenReasonHiddenWord
+			return &reason
+		}
+	}
+
+	if filters.HideOffensive {
+		for _, term := range offensiveTerms {
+			if containsTerm(content, term) {
+				reason := model.HiddenReasonOffensive
+				return &reason
+			}
+		}
+	}
+
+	return nil
+}
+
+// containsTerm reports whether text contains term (case-insensitive) as a whole word or phrase:
+// the match may not be glued to letters or digits on either side ("ass" doesn't match "class").
+func containsTerm(text, term string) bool {
+	text = strings.ToLower(text)
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return false
+	}
+
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], term)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		end := start + len(term)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isHashtagRune(before)) && (end == len(text) || !isHashtagRune(after)) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return false
+}
+
+// normalizeHiddenWords trims, lowercases and de-duplicates a hidden word list, dropping empty entries.
+func normalizeHiddenWords(words []string) ([]string, error) {
+	result := []string{}
+	seen := make(map[string]bool)
+
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || seen[word] {
+			continue
+		}
+		if utf8.RuneCountInString(word) > model.MaxHiddenWordLength {
+			return nil, model.ErrHiddenWordTooLong
+		}
+		seen[word] = true
+		result = append(result, word)
+	}
+
+	if len(result) > model.MaxHiddenWords {
+		return nil, model.ErrTooManyHiddenWords
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"iamstagram_22520060/internal/model"
+)
+
+func TestContainsTerm(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		term string
+		want bool
+	}{
+		{name: "whole word", text: "buy cheap followers", term: "cheap", want: true},
+		{name: "case-insensitive", text: "CHEAP stuff", term: "cheap", want: true},
+		{name: "inside word ignored", text: "a classy post", term: "ass", want: false},
+		{name: "later match is whole word", text: "classy ass", term: "ass", want: true},
+		{name: "punctuation boundary", text: "wow, spam!", term: "spam", want: true},
+		{name: "phrase", text: "please follow back now", term: "follow back", want: true},
+		{name: "unicode letters", text: "đồ khốn nạn", term: "đồ khốn", want: true},
+		{name: "unicode inside word ignored", text: "lồng đèn", term: "lồn", want: false},
+		{name: "empty term", text: "anything", term: "  ", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsTerm(tt.text, tt.term); got != tt.want {
+				t.Errorf("containsTerm(%q, %q) = %v, want %v", tt.text, tt.term, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommentHiddenReason(t *testing.T) {
+	filters := &model.CommentFilterSettings{HiddenWords: []string{"giveaway"}, HideOffensive: true}
+
+	if got := commentHiddenReason("Nice photo!", filters); got != nil {
+		t.Errorf("clean comment hidden: %s", *got)
+	}
+	if got := commentHiddenReason("Join my GIVEAWAY", filters); got == nil || *got != model.HiddenReasonHiddenWord {
+		t.Errorf("hidden word not caught: %v", got)
+	}
+	if got := commentHiddenReason("what the fuck", filters); got == nil || *got != model.HiddenReasonOffensive {
+		t.Errorf("offensive term not caught: %v", got)
+	}
+
+	filters.HideOffensive = false
+	if got := commentHiddenReason("what the fuck", filters); got != nil {
+		t.Errorf("offensive term hidden with option off: %s", *got)
+	}
+}
+
+func TestNormalizeHiddenWords(t *testing.T) {
+	got, err := normalizeHiddenWords([]string{" Spam ", "spam", "", "Follow Back"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"spam", "follow back"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := normalizeHiddenWords([]string{strings.Repeat("a", model.MaxHiddenWordLength+1)}); err != model.ErrHiddenWordTooLong {
+		t.Errorf("got %v, want ErrHiddenWordTooLong", err)
+	}
+
+	tooMany := make([]string, model.MaxHiddenWords+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("word%d", i)
+	}
+	if _, err := normalizeHiddenWords(tooMany); err != model.ErrTooManyHiddenWords {
+		t.Errorf("got %v, want ErrTooManyHiddenWords", err)
+	}
+}
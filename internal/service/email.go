@@ -0,0 +1,29 @@
+package service
+
+import (
+	"net/mail"
+	"strings"
+
+	"iamstagram_22520060/internal/model"
+)
+
+// normalizeEmail trims and lowercases an address and checks it is a bare "user@domain"
+// (no display name, no angle brackets).
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || len(email) > model.MaxEmailLength {
+		return "", model.ErrInvalidEmail
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", model.ErrInvalidEmail
+	}
+
+	// ParseAddress accepts "user@localhost"; require a dotted domain
+	domain := email[strings.LastIndex(email, "@")+1:]
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return "", model.ErrInvalidEmail
+	}
+	return email, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"iamstagram_22520060/internal/model"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: " John.Doe@Example.com ", want: "john.doe@example.com"},
+		{in: "a+tag@mail.example.vn", want: "a+tag@mail.example.vn"},
+		{in: "no-at-sign", wantErr: true},
+		{in: "John <john@example.com>", wantErr: true},
+		{in: "john@localhost", wantErr: true},
+		{in: "john@example.", wantErr: true},
+		{in: "", wantErr: true},
+		{in: strings.Repeat("a", model.MaxEmailLength) + "@example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := normalizeEmail(tt.in)
+			if tt.wantErr {
+				if err != model.ErrInvalidEmail {
+					t.Errorf("normalizeEmail(%q) error = %v, want ErrInvalidEmail", tt.in, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("normalizeEmail(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"iamstagram_22520060/internal/config"
+	"iamstagram_22520060/internal/mail"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
+)
+
+// EmailVerificationService manages the account email: setting it and confirming
+// ownership with a mailed single-use token.
+type EmailVerificationService struct {
+	userRepo   repository.UserRepository
+	verifyRepo repository.EmailVerificationRepository
+	mailer     mail.Mailer
+	config     *config.Config
+}
+
+func NewEmailVerificationService(
+	userRepo repository.UserRepository,
+	verifyRepo repository.EmailVerificationRepository,
+	mailer mail.Mailer,
+	cfg *config.Config,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepo:   userRepo,
+		verifyRepo: verifyRepo,
+		mailer:     mailer,
+		config:     cfg,
+	}
+}
+
+// UpdateEmail sets the user's email (unverified) and mails a verification link.
+// An empty email removes it. Setting the current address again just resends the link
+// if it isn't verified yet.
+func (s *EmailVerificationService) UpdateEmail(ctx context.Context, userID int64, email string) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(email) == "" {
+		if err := s.userRepo.UpdateEmail(ctx, userID, nil); err != nil {
+			return nil, err
+		}
+		user.Email = nil
+		user.EmailVerified = false
+		return user, nil
+	}
+
+	normalized, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Email != nil && strings.EqualFold(*user.Email, normalized) {
+		if !user.EmailVerified {
+			if err := s.sendVerification(ctx, user); err != nil {
+				return nil, err
+			}
+		}
+		return user, nil
+	}
+
+	taken, err := s.userRepo.ExistsByEmail(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+	if taken {
+		return nil, model.ErrEmailExists
+	}
+
+	if err := s.userRepo.UpdateEmail(ctx, userID, &normalized); err != nil {
+		return nil, err
+	}
+	user.Email = &normalized
+	user.EmailVerified = false
+
+	if err := s.sendVerification(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SendVerification mails a (new) verification link for the user's current email.
+func (s *EmailVerificationService) SendVerification(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Email == nil {
+		return model.ErrEmailNotSet
+	}
+	if user.EmailVerified {
+		return model.ErrEmailAlreadyVerified
+	}
+	return s.sendVerification(ctx, user)
+}
+
+// Verify consumes a verification token. It fails if the user changed their email
+// after the link was sent.
+func (s *EmailVerificationService) Verify(ctx context.Context, tokenRaw string) error {
+	token, err := s.verifyRepo.FindValid(ctx, hashToken(tokenRaw))
+	if err != nil {
+		return err
+	}
+
+	if err := s.verifyRepo.MarkUsed(ctx, token.ID); err != nil {
+		return err
+	}
+
+	verified, err := s.userRepo.MarkEmailVerified(ctx, token.UserID, token.Email)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return model.ErrVerificationTokenInvalid
+	}
+
+	log.Printf("[EmailVerificationService] User %d verified their email", token.UserID)
+	return nil
+}
+
+// sendVerification issues a token for the user's current email and mails it.
+// The mail itself is sent async; delivery errors are logged and the user can resend.
+func (s *EmailVerificationService) sendVerification(ctx context.Context, user *model.User) error {
+	tokenRaw, base := newOneTimeToken(user.ID, model.EmailVerificationTokenTTL)
+	token := &model.EmailVerificationToken{OneTimeToken: base, Email: *user.Email}
+	if err := s.verifyRepo.Create(ctx, token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s?token=%s", s.config.EmailVerificationURL, url.QueryEscape(tokenRaw))
+	msg := mail.Message{
+		To:      *user.Email,
+		Subject: "Confirm your email for Iamstagram",
+		Body: fmt.Sprintf(
+			"Hi @%s,\n\n"+
+				"Open this link within %d hours to confirm this is your email:\n\n%s\n\n"+
+				"If you didn't add this email to an Iamstagram account, ignore this message.\n",
+			user.Username, int(model.EmailVerificationTokenTTL.Hours()), link,
+		),
+	}
+
+	userID := user.ID
+	go func() {
+		if err := s.mailer.Send(context.Background(), msg); err != nil {
+			log.Printf("[EmailVerificationService] Failed to send verification email to user %d: %v", userID, err)
+		}
+	}()
+
+	log.Printf("[EmailVerificationService] Verification token issued for user %d", user.ID)
+	return nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"iamstagram_22520060/internal/config"
+	"iamstagram_22520060/internal/mail"
+	"iamstagram_22520060/internal/model"
+)
+
+type mockEmailVerificationRepository struct {
+	mockTokenStore[model.EmailVerificationToken]
+}
+
+func newMockEmailVerificationRepository(tokens ...*model.EmailVerificationToken) *mockEmailVerificationRepository {
+	base := func(t *model.EmailVerificationToken) *model.OneTimeToken { return &t.OneTimeToken }
+	return &mockEmailVerificationRepository{newMockTokenStore(base, model.ErrVerificationTokenInvalid, tokens...)}
+}
+
+func (m *mockEmailVerificationRepository) MarkUsed(ctx context.Context, id int64) error {
+	return m.markUsed(id)
+}
+
+func TestEmailVerificationService_UpdateEmail(t *testing.T) {
+	current := "old@example.com"
+	newService := func(taken map[string]bool) (*EmailVerificationService, *mockUserRepository, *mockEmailVerificationRepository) {
+		userRepo := &mockUserRepository{
+			getByIDFn: func(ctx context.Context, id int64) (*model.User, error) {
+				return &model.User{ID: id, Username: "testuser", Email: &current, EmailVerified: true}, nil
+			},
+			takenEmails: taken,
+		}
+		verifyRepo := newMockEmailVerificationRepository()
+		return NewEmailVerificationService(userRepo, verifyRepo, mail.NewLogMailer("", ""), &config.Config{}), userRepo, verifyRepo
+	}
+
+	t.Run("new address is unverified and gets a link", func(t *testing.T) {
+		svc, userRepo, verifyRepo := newService(nil)
+
+		user, err := svc.UpdateEmail(context.Background(), 1, " New@Example.com ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *user.Email != "new@example.com" || user.EmailVerified {
+			t.Errorf("user email = %q verified=%v", *user.Email, user.EmailVerified)
+		}
+		if len(userRepo.updatedEmails) != 1 || *userRepo.updatedEmails[0] != "new@example.com" {
+			t.Errorf("UpdateEmail calls = %v", userRepo.updatedEmails)
+		}
+		if len(verifyRepo.created) != 1 || verifyRepo.created[0].Email != "new@example.com" {
+			t.Errorf("verification tokens = %v", verifyRepo.created)
+		}
+	})
+
+	t.Run("taken address", func(t *testing.T) {
+		svc, userRepo, _ := newService(map[string]bool{"new@example.com": true})
+
+		if _, err := svc.UpdateEmail(context.Background(), 1, "new@example.com"); !errors.Is(err, model.ErrEmailExists) {
+			t.Errorf("error = %v, want ErrEmailExists", err)
+		}
+		if len(userRepo.updatedEmails) != 0 {
+			t.Error("email should not be updated")
+		}
+	})
+
+	t.Run("same verified address is a no-op", func(t *testing.T) {
+		svc, userRepo, verifyRepo := newService(nil)
+
+		if _, err := svc.UpdateEmail(context.Background(), 1, "OLD@example.com"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(userRepo.updatedEmails) != 0 || len(verifyRepo.created) != 0 {
+			t.Error("nothing should change")
+		}
+	})
+
+	t.Run("empty removes the email", func(t *testing.T) {
+		svc, userRepo, _ := newService(nil)
+
+		user, err := svc.UpdateEmail(context.Background(), 1, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if user.Email != nil || len(userRepo.updatedEmails) != 1 || userRepo.updatedEmails[0] != nil {
+			t.Errorf("email not removed: %v", userRepo.updatedEmails)
+		}
+	})
+}
+
+func TestEmailVerificationService_Verify(t *testing.T) {
+	newService := func(stillCurrent bool) *EmailVerificationService {
+		userRepo := &mockUserRepository{
+			markEmailVerifiedFn: func(ctx context.Context, userID int64, email string) (bool, error) {
+				return stillCurrent, nil
+			},
+		}
+		verifyRepo := newMockEmailVerificationRepository(&model.EmailVerificationToken{
+			OneTimeToken: model.OneTimeToken{ID: 1, UserID: 7, TokenHash: hashToken("valid-token"), ExpiresAt: time.Now().Add(time.Hour)},
+			Email:        "john@example.com",
+		})
+		return NewEmailVerificationService(userRepo, verifyRepo, mail.NewLogMailer("", ""), &config.Config{})
+	}
+
+	svc := newService(true)
+	if err := svc.Verify(context.Background(), "valid-token"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.Verify(context.Background(), "valid-token"); !errors.Is(err, model.ErrVerificationTokenInvalid) {
+		t.Errorf("second use: error = %v, want ErrVerificationTokenInvalid", err)
+	}
+
+	// The user changed their email after the link was sent
+	if err := newService(false).Verify(context.Background(), "valid-token"); !errors.Is(err, model.ErrVerificationTokenInvalid) {
+		t.Errorf("stale email: error = %v, want ErrVerificationTokenInvalid", err)
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/queue"
+	"iamstagram_22520060/internal/repository"
+)
+
+// dataExportStaleAfter is when a pending export is considered lost (e.g. the worker crashed)
+const dataExportStaleAfter = time.Hour
+
+// ExportService builds "download your data" archives.
+// POST /me/export only records the request; the worker builds the ZIP, uploads it to R2
+// and notifies the user, who then downloads it through a presigned URL.
+type ExportService struct {
+	exportRepo   repository.ExportRepository
+	userRepo     repository.UserRepository
+	mediaService *MediaService
+	notifService *NotificationService
+	publisher    queue.Publisher
+}
+
+func NewExportService(
+	exportRepo repository.ExportRepository,
+	userRepo repository.UserRepository,
+	mediaService *MediaService,
+	notifService *NotificationService,
+	publisher queue.Publisher,
+) *ExportService {
+	return &ExportService{
+		exportRepo:   exportRepo,
+		userRepo:     userRepo,
+		mediaService: mediaService,
+		notifService: notifService,
+		publisher:    publisher,
+	}
+}
+
+// RequestExport queues a new export. Only one export can be pending at a time.
+func (s *ExportService) RequestExport(ctx context.Context, userID int64) (*model.DataExport, error) {
+	export, err := s.exportRepo.Create(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without the event nothing would ever build it, and it would block new requests
+	event := queue.NewDataExportRequestedEvent(export.ID, userID)
+	if _, err := s.publisher.Publish(ctx, queue.StreamExport, event); err != nil {
+		if markErr := s.exportRepo.MarkFailed(ctx, export.ID); markErr != nil {
+			log.Printf("[ExportService] Failed to mark export failed: export=%d err=%v", export.ID, markErr)
+		}
+		return nil, fmt.Errorf("publish data export event: %w", err)
+	}
+
+	log.Printf("[ExportService] User %d requested data export %d", userID, export.ID)
+	return export, nil
+}
+
+// GetLatest returns the user's most recent export, with a fresh download URL once it's ready.
+func (s *ExportService) GetLatest(ctx context.Context, userID int64) (*model.DataExport, error) {
+	export, err := s.exportRepo.GetLatest(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.Status == model.DataExportReady && export.ObjectKey != nil {
+		filename := fmt.Sprintf("iamstagram-data-%s.zip", export.CreatedAt.Format("2006-01-02"))
+		url, err := s.mediaService.PresignDownload(ctx, *export.ObjectKey, filename, model.DataExportLinkTTL)
+		if err != nil {
+			return nil, err
+		}
+		export.DownloadURL = &url
+	}
+	return export, nil
+}
+
+// BuildExport builds and uploads the archive for a pending export, then notifies the user.
+// Called by the worker; exports that aren't pending (redelivered events) are skipped.
+func (s *ExportService) BuildExport(ctx context.Context, exportID int64) error {
+	export, err := s.exportRepo.GetByID(ctx, exportID)
+	if err != nil {
+		return err
+	}
+	if export.Status != model.DataExportPending {
+		log.Printf("[ExportService] Export %d is %s, skipping", exportID, export.Status)
+		return nil
+	}
+
+	if err := s.build(ctx, export); err != nil {
+		if errors.Is(err, model.ErrExportNotPending) {
+			log.Printf("[ExportService] Export %d was failed as stale while building, discarding it", exportID)
+			return nil
+		}
+		if markErr := s.exportRepo.MarkFailed(ctx, exportID); markErr != nil {
+			log.Printf("[ExportService] Failed to mark export failed: export=%d err=%v", exportID, markErr)
+		}
+		return err
+	}
+
+	if err := s.notifService.CreateSelfNotification(ctx, export.UserID, model.NotificationTypeDataExport); err != nil {
+		log.Printf("[ExportService] Failed to notify user=%d about export=%d: %v", export.UserID, exportID, err)
+	}
+	return nil
+}
+
+// build writes the archive to a temp file (media can be large) and uploads it.
+func (s *ExportService) build(ctx context.Context, export *model.DataExport) error {
+	data, err := s.collect(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := writeExportArchive(ctx, f, data, s.mediaService.OpenPostMedia); err != nil {
+		return err
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("get archive size: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind archive: %w", err)
+	}
+
+	key := fmt.Sprintf("%s/%d/%s.zip", model.DataExportFolder, export.UserID, uuid.NewString())
+	if err := s.mediaService.UploadPrivateObject(ctx, key, f, "application/zip"); err != nil {
+		return err
+	}
+
+	if err := s.exportRepo.MarkReady(ctx, export.ID, key, size, time.Now().Add(model.DataExportRetention)); err != nil {
+		// Don't leave an object nothing points to
+		if delErr := s.mediaService.DeleteObject(ctx, key); delErr != nil {
+			log.Printf("[ExportService] Failed to delete orphaned archive %s: %v", key, delErr)
+		}
+		return err
+	}
+
+	log.Printf("[ExportService] Export %d ready: user=%d posts=%d size=%d", export.ID, export.UserID, len(data.Posts), size)
+	return nil
+}
+
+// CleanupExpired deletes archives past their expiry and fails exports stuck in pending.
+// Runs as a scheduler job.
+func (s *ExportService) CleanupExpired(ctx context.Context) error {
+	exports, err := s.exportRepo.GetExpired(ctx, model.DataExportCleanupBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if export.ObjectKey != nil {
+			if err := s.mediaService.DeleteObject(ctx, *export.ObjectKey); err != nil {
+				log.Printf("[ExportService] Failed to delete archive: export=%d err=%v", export.ID, err)
+				continue
+			}
+		}
+		if err := s.exportRepo.MarkExpired(ctx, export.ID); err != nil {
+			log.Printf("[ExportService] Failed to mark export expired: export=%d err=%v", export.ID, err)
+		}
+	}
+
+	stale, err := s.exportRepo.FailStale(ctx, time.Now().Add(-dataExportStaleAfter))
+	if err != nil {
+		return err
+	}
+
+	if len(exports) > 0 || stale > 0 {
+		log.Printf("[ExportService] Cleanup DONE: expired=%d stale=%d", len(exports), stale)
+	}
+	return nil
+}
+
+// exportData is everything that goes into one archive.
+type exportData struct {
+	Profile       *model.User
+	Posts         []model.ExportPost
+	Comments      []model.ExportComment
+	Likes         []model.ExportLike
+	Followers     []model.ExportFollow
+	Following     []model.ExportFollow
+	Notifications []model.ExportNotification
+}
+
+func (s *ExportService) collect(ctx context.Context, userID int64) (*exportData, error) {
+	var data exportData
+	var err error
+
+	if data.Profile, err = s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.Posts, err = s.exportRepo.GetPosts(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.Comments, err = s.exportRepo.GetComments(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.Likes, err = s.exportRepo.GetLikes(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.Followers, err = s.exportRepo.GetFollowers(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.Following, err = s.exportRepo.GetFollowing(ctx, userID); err != nil {
+		return nil, err
+	}
+	if data.Notifications, err = s.exportRepo.GetNotifications(ctx, userID); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// writeExportArchive writes the ZIP: one JSON file per section plus post media under media/.
+// Media that can't be opened is left out (its "file" stays empty) instead of failing the export.
+func writeExportArchive(
+	ctx context.Context,
+	w io.Writer,
+	data *exportData,
+	openMedia func(ctx context.Context, mediaURL string) (io.ReadCloser, error),
+) error {
+	zw := zip.NewWriter(w)
+
+	for i := range data.Posts {
+		post := &data.Posts[i]
+		for j := range post.Media {
+			m := &post.Media[j]
+			name := fmt.Sprintf("media/%d_%d%s", post.ID, m.Position, path.Ext(m.MediaURL))
+
+			body, err := openMedia(ctx, m.MediaURL)
+			if err != nil {
+				log.Printf("[ExportService] Skipping media of post=%d url=%s: %v", post.ID, m.MediaURL, err)
+				continue
+			}
+			// Images and videos are already compressed
+			entry, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: post.CreatedAt})
+			if err == nil {
+				_, err = io.Copy(entry, body)
+			}
+			body.Close()
+			if err != nil {
+				return fmt.Errorf("write %s: %w", name, err)
+			}
+			m.File = name
+		}
+	}
+
+	files := []struct {
+		name  string
+		value any
+	}{
+		{"profile.json", data.Profile},
+		{"posts.json", nonNil(data.Posts)},
+		{"comments.json", nonNil(data.Comments)},
+		{"likes.json", nonNil(data.Likes)},
+		{"followers.json", nonNil(data.Followers)},
+		{"following.json", nonNil(data.Following)},
+		{"notifications.json", nonNil(data.Notifications)},
+	}
+	for _, file := range files {
+		entry, err := zw.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("create %s: %w", file.name, err)
+		}
+		enc := json.NewEncoder(entry)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.value); err != nil {
+			return fmt.Errorf("write %s: %w", file.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+	return nil
+}
+
+// nonNil makes empty sections encode as [] instead of null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"iamstagram_22520060/internal/model"
+)
+
+func TestWriteExportArchive(t *testing.T) {
+	data := &exportData{
+		Profile: &model.User{ID: 1, Username: "testuser", PasswordHashed: "secret-hash"},
+		Posts: []model.ExportPost{{
+			ID:        7,
+			Caption:   strPtr("hello"),
+			CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Media: []model.ExportMedia{
+				{MediaType: "image", MediaURL: "https://cdn.example.com/posts/a.jpg", Position: 0},
+				{MediaType: "image", MediaURL: "https://cdn.example.com/posts/missing.jpg", Position: 1},
+			},
+		}},
+	}
+	openMedia := func(ctx context.Context, mediaURL string) (io.ReadCloser, error) {
+		if strings.Contains(mediaURL, "missing") {
+			return nil, errors.New("not found")
+		}
+		return io.NopCloser(strings.NewReader("jpeg-bytes")), nil
+	}
+
+	var buf bytes.Buffer
+	if err := writeExportArchive(context.Background(), &buf, data, openMedia); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	for _, name := range []string{"profile.json", "posts.json", "comments.json", "likes.json", "followers.json", "following.json", "notifications.json"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("missing %s", name)
+		}
+	}
+	if files["media/7_0.jpg"] != "jpeg-bytes" {
+		t.Errorf("media/7_0.jpg = %q", files["media/7_0.jpg"])
+	}
+	if _, ok := files["media/7_1.jpg"]; ok {
+		t.Error("media that failed to open should be skipped")
+	}
+	if strings.Contains(files["profile.json"], "secret-hash") {
+		t.Error("profile.json leaks the password hash")
+	}
+	if strings.TrimSpace(files["comments.json"]) != "[]" {
+		t.Errorf("empty section = %q, want []", files["comments.json"])
+	}
+
+	var posts []model.ExportPost
+	if err := json.Unmarshal([]byte(files["posts.json"]), &posts); err != nil {
+		t.Fatalf("posts.json: %v", err)
+	}
+	if len(posts) != 1 || len(posts[0].Media) != 2 {
+		t.Fatalf("posts.json = %+v", posts)
+	}
+	if posts[0].Media[0].File != "media/7_0.jpg" || posts[0].Media[1].File != "" {
+		t.Errorf("media files = %q, %q", posts[0].Media[0].File, posts[0].Media[1].File)
+	}
+}
@@ -27,21 +27,27 @@ const (
 type FeedService struct {
 	feedCache  cache.FeedCache
 	postRepo   repository.PostRepository
+	repostRepo repository.RepostRepository
 	followRepo repository.FollowRepository
 	userRepo   repository.UserRepository
+	blockRepo  repository.BlockRepository
 }
 
 func NewFeedService(
 	feedCache cache.FeedCache,
 	postRepo repository.PostRepository,
+	repostRepo repository.RepostRepository,
 	followRepo repository.FollowRepository,
 	userRepo repository.UserRepository,
+	blockRepo repository.BlockRepository,
 ) *FeedService {
 	return &FeedService{
 		feedCache:  feedCache,
 		postRepo:   postRepo,
+		repostRepo: repostRepo,
 		followRepo: followRepo,
 		userRepo:   userRepo,
+		blockRepo:  blockRepo,
 	}
 }
 
@@ -51,7 +57,7 @@ func NewFeedService(
 // 1. Check if cache exists for user
 // 2. If no cache -> warm it (fetch all posts from followees, up to 500)
 // 3. Get post IDs from cache (using cursor if provided)
-// 4. Hydrate: fetch full post details from DB
+// 4. Hydrate: fetch full post details from DB, plus "reposted by" for reposted posts
 // 5. Build next cursor from last post
 func (s *FeedService) GetFeed(ctx context.Context, userID int64, cursor *string, limit int) (*model.FeedResponse, error) {
 	startTime := time.Now()
@@ -103,10 +109,11 @@ func (s *FeedService) GetFeed(ctx context.Context, userID int64, cursor *string,
 	}
 
 	// Step 4: Hydrate posts from DB
-	posts, err := s.hydratePosts(ctx, userID, postIDs)
+	posts, err := s.HydratePosts(ctx, userID, postIDs)
 	if err != nil {
 		return nil, fmt.Errorf("hydrate posts: %w", err)
 	}
+	s.attachRepostedBy(ctx, userID, posts)
 
 	// Step 5: Build next cursor and check if there are more posts
 	var nextCursor *string
@@ -137,6 +144,7 @@ func (s *FeedService) warmCache(ctx context.Context, userID int64) error {
 	if err != nil {
 		return fmt.Errorf("get followee ids: %w", err)
 	}
+	reposterIDs := followeeIDs
 
 	// Include user's own posts in their feed
 	followeeIDs = append(followeeIDs, userID)
@@ -162,20 +170,92 @@ func (s *FeedService) warmCache(ctx context.Context, userID int64) error {
 		return fmt.Errorf("warm cache: %w", err)
 	}
 
+	// Add followees' reposts of posts that aren't already in the feed as originals
+	reposts, err := s.repostRepo.GetFeedReposts(ctx, reposterIDs, CacheWarmLimit)
+	if err != nil {
+		log.Printf("[FeedService] Failed to get reposts for user=%d: %v", userID, err)
+	} else {
+		inFeed := make(map[int64]bool, len(posts))
+		for _, p := range posts {
+			inFeed[p.PostID] = true
+		}
+		filtered := make([]cache.RepostScore, 0, len(reposts))
+		for _, r := range reposts {
+			if !inFeed[r.PostID] {
+				filtered = append(filtered, r)
+			}
+		}
+		if err := s.feedCache.WarmReposts(ctx, userID, filtered); err != nil {
+			log.Printf("[FeedService] Failed to warm reposts for user=%d: %v", userID, err)
+		}
+	}
+
 	log.Printf("[FeedService] Cache warmed: user=%d posts=%d duration=%v",
 		userID, len(posts), time.Since(startTime))
 
 	return nil
 }
 
-// hydratePosts fetches full post details and enriches with author info.
-func (s *FeedService) hydratePosts(ctx context.Context, viewerID int64, postIDs []int64) ([]model.FeedPost, error) {
+// attachRepostedBy sets RepostedBy on feed posts that reached the user's feed through a repost.
+// Best-effort: posts are shown without attribution if the lookup fails.
+func (s *FeedService) attachRepostedBy(ctx context.Context, userID int64, posts []model.FeedPost) {
+	postIDs := make([]int64, len(posts))
+	for i, p := range posts {
+		postIDs[i] = p.ID
+	}
+
+	attributions, err := s.feedCache.GetRepostAttributions(ctx, userID, postIDs)
+	if err != nil {
+		log.Printf("[FeedService] Failed to get repost attributions for user=%d: %v", userID, err)
+		return
+	}
+	if len(attributions) == 0 {
+		return
+	}
+
+	reposterIDs := make([]int64, 0, len(attributions))
+	for _, id := range attributions {
+		reposterIDs = append(reposterIDs, id)
+	}
+	reposters, err := s.userRepo.GetSummariesByIDs(ctx, reposterIDs)
+	if err != nil {
+		log.Printf("[FeedService] Failed to get reposters: %v", err)
+		return
+	}
+	byID := make(map[int64]model.UserSummary, len(reposters))
+	for _, u := range reposters {
+		byID[u.ID] = u
+	}
+
+	for i := range posts {
+		reposterID, ok := attributions[posts[i].ID]
+		if !ok {
+			continue
+		}
+		if u, ok := byID[reposterID]; ok {
+			posts[i].RepostedBy = &u
+		}
+	}
+}
+
+// HydratePosts fetches full post details and enriches them with author info and the
+// viewer's like/save/repost status. Posts keep the order of postIDs; deleted ones, ones by
+// users blocked by or blocking the viewer, and ones by private accounts the viewer doesn't
+// follow are dropped.
+// Also used by other post lists that render like the feed (e.g. saved posts).
+func (s *FeedService) HydratePosts(ctx context.Context, viewerID int64, postIDs []int64) ([]model.FeedPost, error) {
 	// Fetch posts from DB
 	posts, err := s.postRepo.GetByIDs(ctx, postIDs)
 	if err != nil {
 		return nil, fmt.Errorf("get posts by ids: %w", err)
 	}
 
+	// Blocked posts can still reach the feed through a third user's repost, or a save
+	posts, err = s.dropBlockedAuthors(ctx, viewerID, posts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Collect unique author IDs
 	authorIDSet := make(map[int64]struct{})
 	for _, p := range posts {
@@ -188,6 +268,7 @@ func (s *FeedService) hydratePosts(ctx context.Context, viewerID int64, postIDs
 
 	// Fetch author details
 	authors := make(map[int64]model.UserSummary)
+	privateAuthors := make(map[int64]bool)
 	for _, authorID := range authorIDs {
 		user, err := s.userRepo.GetByID(ctx, authorID)
 		if err != nil {
@@ -200,6 +281,9 @@ func (s *FeedService) hydratePosts(ctx context.Context, viewerID int64, postIDs
 			DisplayName: user.DisplayName,
 			AvatarURL:   user.AvatarURL,
 		}
+		if user.IsPrivate && user.ID != viewerID {
+			privateAuthors[authorID] = true
+		}
 	}
 
 	// Check if viewer follows these authors (for "following" indicator)
@@ -208,12 +292,36 @@ func (s *FeedService) hydratePosts(ctx context.Context, viewerID int64, postIDs
 		log.Printf("[FeedService] Failed to check follows: %v", err)
 	}
 
+	// Private posts can outlive the follow in the cache, or be saved before the account went
+	// private. Without the follow status we can't tell, so they are dropped.
+	if len(privateAuthors) > 0 {
+		kept := posts[:0]
+		for _, p := range posts {
+			if !privateAuthors[p.UserID] || followStatus[p.UserID] {
+				kept = append(kept, p)
+			}
+		}
+		posts = kept
+	}
+
 	// Check which posts the viewer has liked
 	likeStatus, err := s.postRepo.CheckLikes(ctx, viewerID, postIDs)
 	if err != nil {
 		log.Printf("[FeedService] Failed to check likes: %v", err)
 	}
 
+	// Check which posts the viewer has saved
+	saveStatus, err := s.postRepo.CheckSaves(ctx, viewerID, postIDs)
+	if err != nil {
+		log.Printf("[FeedService] Failed to check saves: %v", err)
+	}
+
+	// Check which posts the viewer has reposted
+	repostStatus, err := s.postRepo.CheckReposts(ctx, viewerID, postIDs)
+	if err != nil {
+		log.Printf("[FeedService] Failed to check reposts: %v", err)
+	}
+
 	// Build feed posts
 	feedPosts := make([]model.FeedPost, len(posts))
 	for i, p := range posts {
@@ -224,6 +332,13 @@ func (s *FeedService) hydratePosts(ctx context.Context, viewerID int64, postIDs
 		if likeStatus != nil {
 			p.IsLiked = likeStatus[p.ID]
 		}
+		if saveStatus != nil {
+			p.IsSaved = saveStatus[p.ID]
+		}
+		if repostStatus != nil {
+			p.IsReposted = repostStatus[p.ID]
+		}
+		p.HideLikeCountFrom(&viewerID)
 		feedPosts[i] = model.FeedPost{
 			Post:   p,
 			Author: author,
@@ -233,6 +348,28 @@ func (s *FeedService) hydratePosts(ctx context.Context, viewerID int64, postIDs
 	return feedPosts, nil
 }
 
+func (s *FeedService) dropBlockedAuthors(ctx context.Context, viewerID int64, posts []model.Post) ([]model.Post, error) {
+	blockedIDs, err := s.blockRepo.GetBlockedIDs(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(blockedIDs) == 0 {
+		return posts, nil
+	}
+
+	blocked := make(map[int64]bool, len(blockedIDs))
+	for _, id := range blockedIDs {
+		blocked[id] = true
+	}
+	kept := posts[:0]
+	for _, p := range posts {
+		if !blocked[p.UserID] {
+			kept = append(kept, p)
+		}
+	}
+	return kept, nil
+}
+
 // parseFeedCursor parses "id:timestamp" format cursor.
 // Returns the timestamp (as score) and post ID.
 func parseFeedCursor(cursor string) (float64, int64, error) {
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"iamstagram_22520060/internal/cache"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
+)
+
+// mockFeedCache serves a fixed feed; other methods panic through the nil interface.
+type mockFeedCache struct {
+	cache.FeedCache
+	postIDs      []int64
+	attributions map[int64]int64 // postID -> reposterID
+}
+
+func (m *mockFeedCache) Exists(ctx context.Context, userID int64) (bool, error) {
+	return true, nil
+}
+
+func (m *mockFeedCache) GetFeed(ctx context.Context, userID int64, cursorScore *float64, limit int) ([]int64, []float64, error) {
+	scores := make([]float64, len(m.postIDs))
+	for i := range scores {
+		scores[i] = float64(1000 - i)
+	}
+	return m.postIDs, scores, nil
+}
+
+func (m *mockFeedCache) GetRepostAttributions(ctx context.Context, userID int64, postIDs []int64) (map[int64]int64, error) {
+	return m.attributions, nil
+}
+
+// mockPostRepository only implements what HydratePosts needs.
+type mockPostRepository struct {
+	repository.PostRepository
+	posts map[int64]model.Post
+}
+
+func (m *mockPostRepository) GetByIDs(ctx context.Context, postIDs []int64) ([]model.Post, error) {
+	var posts []model.Post
+	for _, id := range postIDs {
+		if p, ok := m.posts[id]; ok {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+func (m *mockPostRepository) CheckLikes(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error) {
+	return nil, nil
+}
+
+func (m *mockPostRepository) CheckSaves(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error) {
+	return nil, nil
+}
+
+func (m *mockPostRepository) CheckReposts(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error) {
+	return nil, nil
+}
+
+type mockSavedRepository struct {
+	repository.SavedRepository
+	postIDs []int64
+}
+
+func (m *mockSavedRepository) GetSavedPostIDs(ctx context.Context, userID int64, cursor *string, limit int) ([]int64, *string, error) {
+	return m.postIDs, nil, nil
+}
+
+// newVisibilityFeedService sets up viewer 1 following public user 2 and private user 3.
+// Private user 4 isn't followed. Post N is by user N.
+func newVisibilityFeedService(feedCache cache.FeedCache) *FeedService {
+	postRepo := &mockPostRepository{posts: map[int64]model.Post{
+		2: {ID: 2, UserID: 2},
+		3: {ID: 3, UserID: 3},
+		4: {ID: 4, UserID: 4},
+	}}
+	userRepo := &mockUserRepository{getByIDFn: func(ctx context.Context, id int64) (*model.User, error) {
+		return &model.User{ID: id, Username: "user", IsPrivate: id >= 3}, nil
+	}}
+	followRepo := &mockFollowRepository{follows: map[[2]int64]bool{{1, 2}: true, {1, 3}: true}}
+	blockRepo := &mockBlockRepository{}
+	return NewFeedService(feedCache, postRepo, nil, followRepo, userRepo, blockRepo)
+}
+
+func feedPostIDs(posts []model.FeedPost) []int64 {
+	ids := make([]int64, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func TestGetFeed_DropsRepostedPrivatePosts(t *testing.T) {
+	// Post 4 reached the feed through user 2's repost, made while user 4 was public
+	feedCache := &mockFeedCache{postIDs: []int64{2, 3, 4}, attributions: map[int64]int64{4: 2}}
+	feedService := newVisibilityFeedService(feedCache)
+
+	resp, err := feedService.GetFeed(context.Background(), 1, nil, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := feedPostIDs(resp.Posts)
+	if len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("feed posts = %v, want [2 3]", got)
+	}
+}
+
+func TestGetSaved_DropsPrivatePosts(t *testing.T) {
+	// Post 4 was saved before user 4 went private
+	feedService := newVisibilityFeedService(&mockFeedCache{})
+	savedService := NewSavedService(&mockSavedRepository{postIDs: []int64{4, 3, 2}}, nil, feedService, nil)
+
+	resp, err := savedService.GetSaved(context.Background(), 1, nil, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := feedPostIDs(resp.Posts)
+	if len(got) != 2 || got[0] != 3 || got[1] != 2 {
+		t.Errorf("saved posts = %v, want [3 2]", got)
+	}
+}
@@ -2,12 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 
+	"iamstagram_22520060/internal/cache"
 	"iamstagram_22520060/internal/model"
 	"iamstagram_22520060/internal/queue"
 	"iamstagram_22520060/internal/repository"
@@ -16,6 +18,8 @@ import (
 type FollowService struct {
 	followRepo repository.FollowRepository
 	userRepo   repository.UserRepository
+	blockRepo  repository.BlockRepository
+	repostRepo repository.RepostRepository
 	db         *sqlx.DB
 	publisher  queue.Publisher
 }
@@ -23,70 +27,114 @@ type FollowService struct {
 func NewFollowService(
 	followRepo repository.FollowRepository,
 	userRepo repository.UserRepository,
+	blockRepo repository.BlockRepository,
+	repostRepo repository.RepostRepository,
 	db *sqlx.DB,
 	publisher queue.Publisher,
 ) *FollowService {
 	return &FollowService{
 		followRepo: followRepo,
 		userRepo:   userRepo,
+		blockRepo:  blockRepo,
+		repostRepo: repostRepo,
 		db:         db,
 		publisher:  publisher,
 	}
 }
 
-func (s *FollowService) Follow(ctx context.Context, followerID, followeeID int64) error {
+// Follow follows a public account right away. Following a private account only records a
+// request; the follow (with backfill and notification) happens once the owner approves it.
+func (s *FollowService) Follow(ctx context.Context, followerID, followeeID int64) (model.FollowStatus, error) {
 	if followerID == followeeID {
-		return model.ErrCannotFollowSelf
+		return "", model.ErrCannotFollowSelf
 	}
 
-	_, err := s.userRepo.GetByID(ctx, followeeID)
+	followee, err := s.userRepo.GetByID(ctx, followeeID)
 	if err != nil {
-		return err
+		return "", err
+	}
+	if err := checkNotBlocked(ctx, s.blockRepo, followeeID, &followerID); err != nil {
+		return "", err
+	}
+
+	if followee.IsPrivate {
+		if err := s.requestFollow(ctx, followerID, followeeID); err != nil {
+			return "", err
+		}
+		return model.FollowStatusRequested, nil
 	}
 
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
+		return "", fmt.Errorf("begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	inserted, err := s.followRepo.Create(ctx, tx, followerID, followeeID)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	if !inserted {
-		return model.ErrAlreadyFollowing
+		return "", model.ErrAlreadyFollowing
 	}
 
 	if err := s.userRepo.IncrementFollowerCount(ctx, tx, followeeID, 1); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := s.userRepo.IncrementFollowingCount(ctx, tx, followerID, 1); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+		return "", fmt.Errorf("commit transaction: %w", err)
 	}
 
-	// Publish event for async backfill (after commit!)
-	if s.publisher != nil {
-		event := queue.NewUserFollowedEvent(followerID, followeeID)
-		msgID, err := s.publisher.Publish(ctx, queue.StreamFeed, event)
-		if err != nil {
-			log.Printf("[FollowService] Failed to publish UserFollowed event: follower=%d followee=%d err=%v",
-				followerID, followeeID, err)
-		} else {
-			log.Printf("[FollowService] Published UserFollowed: follower=%d followee=%d msgID=%s",
-				followerID, followeeID, msgID)
-		}
+	s.publishFollowed(ctx, followerID, followeeID)
+
+	return model.FollowStatusFollowing, nil
+}
+
+func (s *FollowService) requestFollow(ctx context.Context, followerID, followeeID int64) error {
+	following, err := s.followRepo.Exists(ctx, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+	if following {
+		return model.ErrAlreadyFollowing
 	}
 
+	created, err := s.followRepo.CreateRequest(ctx, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return model.ErrFollowRequestExists
+	}
+
+	log.Printf("[FollowService] User %d requested to follow %d", followerID, followeeID)
 	return nil
 }
 
+// publishFollowed publishes the event for async backfill and notification (after commit!)
+func (s *FollowService) publishFollowed(ctx context.Context, followerID, followeeID int64) {
+	if s.publisher == nil {
+		return
+	}
+
+	event := queue.NewUserFollowedEvent(followerID, followeeID)
+	msgID, err := s.publisher.Publish(ctx, queue.StreamFeed, event)
+	if err != nil {
+		log.Printf("[FollowService] Failed to publish UserFollowed event: follower=%d followee=%d err=%v",
+			followerID, followeeID, err)
+	} else {
+		log.Printf("[FollowService] Published UserFollowed: follower=%d followee=%d msgID=%s",
+			followerID, followeeID, msgID)
+	}
+}
+
+// Unfollow removes a follow, or withdraws a pending follow request.
 func (s *FollowService) Unfollow(ctx context.Context, followerID, followeeID int64) error {
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -95,6 +143,9 @@ func (s *FollowService) Unfollow(ctx context.Context, followerID, followeeID int
 	defer tx.Rollback()
 
 	if err := s.followRepo.Delete(ctx, tx, followerID, followeeID); err != nil {
+		if errors.Is(err, model.ErrNotFollowing) {
+			return s.withdrawRequest(ctx, followerID, followeeID)
+		}
 		return err
 	}
 
@@ -126,6 +177,136 @@ func (s *FollowService) Unfollow(ctx context.Context, followerID, followeeID int
 	return nil
 }
 
+func (s *FollowService) withdrawRequest(ctx context.Context, followerID, followeeID int64) error {
+	if err := s.followRepo.DeleteRequest(ctx, followerID, followeeID); err != nil {
+		if errors.Is(err, model.ErrFollowRequestNotFound) {
+			return model.ErrNotFollowing
+		}
+		return err
+	}
+	return nil
+}
+
+// GetRequests lists the users waiting for the user to approve their follow request, newest first.
+func (s *FollowService) GetRequests(ctx context.Context, userID int64, cursor *time.Time, limit int) (*model.FollowListResponse, error) {
+	users, nextCursor, err := s.followRepo.GetRequests(ctx, userID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextCursorStr *string
+	if nextCursor != nil {
+		str := nextCursor.Format(time.RFC3339)
+		nextCursorStr = &str
+	}
+
+	return &model.FollowListResponse{
+		Users:      users,
+		NextCursor: nextCursorStr,
+		HasMore:    nextCursor != nil,
+	}, nil
+}
+
+// ApproveRequest turns requesterID's pending request into a follow of userID.
+// Publishes the same UserFollowed event as Follow, so the requester's feed is backfilled
+// and userID gets the usual follow notification.
+func (s *FollowService) ApproveRequest(ctx context.Context, userID, requesterID int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.followRepo.AcceptRequest(ctx, tx, requesterID, userID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.IncrementFollowerCount(ctx, tx, userID, 1); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.IncrementFollowingCount(ctx, tx, requesterID, 1); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	s.publishFollowed(ctx, requesterID, userID)
+
+	return nil
+}
+
+// DeclineRequest drops requesterID's pending request. The requester isn't told.
+func (s *FollowService) DeclineRequest(ctx context.Context, userID, requesterID int64) error {
+	return s.followRepo.DeleteRequest(ctx, requesterID, userID)
+}
+
+// SetPrivate switches the account between public and private.
+// Going private removes existing reposts of the account's posts, since private posts can't be
+// reposted. Going public approves every pending request, since there is nothing left to
+// approve them for.
+func (s *FollowService) SetPrivate(ctx context.Context, userID int64, private bool) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.userRepo.SetPrivate(ctx, tx, userID, private); err != nil {
+		return err
+	}
+
+	var removedReposts []cache.RepostScore
+	if private {
+		removedReposts, err = s.repostRepo.DeleteByAuthor(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	var accepted []int64
+	if !private {
+		accepted, err = s.followRepo.AcceptAllRequests(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		if len(accepted) > 0 {
+			if err := s.userRepo.IncrementFollowerCount(ctx, tx, userID, len(accepted)); err != nil {
+				return err
+			}
+		}
+		for _, followerID := range accepted {
+			if err := s.userRepo.IncrementFollowingCount(ctx, tx, followerID, 1); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	for _, followerID := range accepted {
+		s.publishFollowed(ctx, followerID, userID)
+	}
+	// Pull the removed reposts out of the feeds they reached
+	if s.publisher != nil {
+		for _, r := range removedReposts {
+			event := queue.NewPostUnrepostedEvent(r.PostID, userID, r.ReposterID)
+			if _, err := s.publisher.Publish(ctx, queue.StreamFeed, event); err != nil {
+				log.Printf("[FollowService] Failed to publish PostUnreposted event: post=%d reposter=%d err=%v",
+					r.PostID, r.ReposterID, err)
+			}
+		}
+	}
+
+	log.Printf("[FollowService] User %d set private=%t (approved %d requests, removed %d reposts)",
+		userID, private, len(accepted), len(removedReposts))
+	return nil
+}
+
 // GetFollowers retrieves users who follow the specified user with cursor-based pagination.
 //
 // Cursor pagination explanation:
@@ -146,7 +327,11 @@ func (s *FollowService) Unfollow(ctx context.Context, followerID, followeeID int
 // TODO: Profile with real-world data. If performance becomes an issue, consider
 // rewriting with LEFT JOIN to reduce to single query.
 func (s *FollowService) GetFollowers(ctx context.Context, userID int64, cursor *time.Time, limit int, viewerID *int64) (*model.FollowListResponse, error) {
-	users, nextCursor, err := s.followRepo.GetFollowers(ctx, userID, cursor, limit)
+	if err := s.checkCanViewProfile(ctx, userID, viewerID); err != nil {
+		return nil, err
+	}
+
+	users, nextCursor, err := s.followRepo.GetFollowers(ctx, userID, viewerOrAnonymous(viewerID), cursor, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +356,11 @@ func (s *FollowService) GetFollowers(ctx context.Context, userID int64, cursor *
 // GetFollowing retrieves users that the specified user follows with cursor-based pagination.
 // See GetFollowers documentation for cursor pagination and design decision explanations.
 func (s *FollowService) GetFollowing(ctx context.Context, userID int64, cursor *time.Time, limit int, viewerID *int64) (*model.FollowListResponse, error) {
-	users, nextCursor, err := s.followRepo.GetFollowing(ctx, userID, cursor, limit)
+	if err := s.checkCanViewProfile(ctx, userID, viewerID); err != nil {
+		return nil, err
+	}
+
+	users, nextCursor, err := s.followRepo.GetFollowing(ctx, userID, viewerOrAnonymous(viewerID), cursor, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -193,6 +382,38 @@ func (s *FollowService) GetFollowing(ctx context.Context, userID int64, cursor *
 	}, nil
 }
 
+func (s *FollowService) checkCanViewProfile(ctx context.Context, userID int64, viewerID *int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkNotBlocked(ctx, s.blockRepo, userID, viewerID); err != nil {
+		return err
+	}
+	return checkCanViewProfile(ctx, s.followRepo, user, viewerID)
+}
+
+// checkCanViewProfile returns ErrPrivateAccount unless the viewer may see the user's posts
+// and follow lists: everyone may for public accounts, only the owner and approved followers
+// for private ones. Shared by the services serving profile tabs.
+func checkCanViewProfile(ctx context.Context, followRepo repository.FollowRepository, user *model.User, viewerID *int64) error {
+	if !user.IsPrivate || (viewerID != nil && *viewerID == user.ID) {
+		return nil
+	}
+	if viewerID == nil {
+		return model.ErrPrivateAccount
+	}
+
+	following, err := followRepo.Exists(ctx, *viewerID, user.ID)
+	if err != nil {
+		return err
+	}
+	if !following {
+		return model.ErrPrivateAccount
+	}
+	return nil
+}
+
 // enrichWithFollowStatus performs a BATCH check (not N+1!) to determine if the viewer
 // follows each user in the list. It collects all user IDs and makes ONE database query
 // using WHERE followee_id = ANY($1), then maps the results back to the user list.
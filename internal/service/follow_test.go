@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
+)
+
+// mockFollowRepository only implements Exists; other methods panic through the nil interface.
+type mockFollowRepository struct {
+	repository.FollowRepository
+	follows map[[2]int64]bool // {follower, followee}
+}
+
+func (m *mockFollowRepository) Exists(ctx context.Context, followerID, followeeID int64) (bool, error) {
+	return m.follows[[2]int64{followerID, followeeID}], nil
+}
+
+func TestCheckCanViewProfile(t *testing.T) {
+	followRepo := &mockFollowRepository{follows: map[[2]int64]bool{{2, 1}: true}}
+	viewer := func(id int64) *int64 { return &id }
+
+	tests := []struct {
+		name     string
+		private  bool
+		viewerID *int64
+		wantErr  error
+	}{
+		{"public, anonymous", false, nil, nil},
+		{"public, stranger", false, viewer(3), nil},
+		{"private, owner", true, viewer(1), nil},
+		{"private, follower", true, viewer(2), nil},
+		{"private, stranger", true, viewer(3), model.ErrPrivateAccount},
+		{"private, anonymous", true, nil, model.ErrPrivateAccount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.User{ID: 1, IsPrivate: tt.private}
+			err := checkCanViewProfile(context.Background(), followRepo, user, tt.viewerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func (m *mockFollowRepository) CheckFollows(ctx context.Context, followerID int64, followeeIDs []int64) (map[int64]bool, error) {
+	result := make(map[int64]bool, len(followeeIDs))
+	for _, id := range followeeIDs {
+		result[id] = m.follows[[2]int64{followerID, id}]
+	}
+	return result, nil
+}
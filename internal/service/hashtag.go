@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"iamstagram_22520060/internal/cache"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
+)
+
+const (
+	// trendingRecentWindow is the window whose usage is compared against the baseline
+	trendingRecentWindow = 24 * time.Hour
+
+	// trendingBaselineWindow is how far before the recent window the baseline reaches
+	trendingBaselineWindow = 7 * 24 * time.Hour
+
+	// trendingMinRecentUses filters out hashtags with too little activity to trend
+	trendingMinRecentUses = 3
+
+	// trendingMaxHashtags is how many hashtags are kept in the trending list
+	trendingMaxHashtags = 50
+)
+
+// HashtagService handles hashtag pages, search and trending.
+type HashtagService struct {
+	hashtagRepo   repository.HashtagRepository
+	trendingCache cache.TrendingCache
+}
+
+func NewHashtagService(hashtagRepo repository.HashtagRepository, trendingCache cache.TrendingCache) *HashtagService {
+	return &HashtagService{
+		hashtagRepo:   hashtagRepo,
+		trendingCache: trendingCache,
+	}
+}
+
+// GetPosts returns the hashtag page: the hashtag and the posts the viewer may see, newest first.
+func (s *HashtagService) GetPosts(ctx context.Context, tag string, viewerID *int64, cursor *string, limit int) (*model.HashtagPostsResponse, error) {
+	if limit <= 0 {
+		limit = 12
+	}
+	if limit > 36 {
+		limit = 36
+	}
+
+	name := normalizeHashtag(tag)
+	if name == "" {
+		return nil, model.ErrHashtagNotFound
+	}
+
+	hashtag, err := s.hashtagRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails, nextCursor, err := s.hashtagRepo.GetPostThumbnails(ctx, hashtag.ID, viewerOrAnonymous(viewerID), cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get hashtag thumbnails: %w", err)
+	}
+	if thumbnails == nil {
+		thumbnails = []model.PostThumbnail{}
+	}
+
+	return &model.HashtagPostsResponse{
+		Hashtag:    *hashtag,
+		Posts:      thumbnails,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != nil,
+	}, nil
+}
+
+// Search returns hashtags starting with the query, most used first.
+func (s *HashtagService) Search(ctx context.Context, query string, limit int) (*model.HashtagListResponse, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	prefix := normalizeHashtag(query)
+	if prefix == "" {
+		return &model.HashtagListResponse{Hashtags: []model.Hashtag{}}, nil
+	}
+
+	hashtags, err := s.hashtagRepo.Search(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	if hashtags == nil {
+		hashtags = []model.Hashtag{}
+	}
+
+	return &model.HashtagListResponse{Hashtags: hashtags}, nil
+}
+
+// GetTrending returns the latest trending hashtags computed by RefreshTrending.
+func (s *HashtagService) GetTrending(ctx context.Context, limit int) (*model.HashtagListResponse, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > trendingMaxHashtags {
+		limit = trendingMaxHashtags
+	}
+
+	scores, err := s.trendingCache.GetTrendingHashtags(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) == 0 {
+		return &model.HashtagListResponse{Hashtags: []model.Hashtag{}}, nil
+	}
+
+	names := make([]string, len(scores))
+	for i, sc := range scores {
+		names[i] = sc.Name
+	}
+
+	found, err := s.hashtagRepo.GetByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the trending order (GetByNames returns rows in arbitrary order)
+	byName := make(map[string]model.Hashtag, len(found))
+	for _, h := range found {
+		byName[h.Name] = h
+	}
+	hashtags := make([]model.Hashtag, 0, len(found))
+	for _, name := range names {
+		if h, ok := byName[name]; ok && h.PostCount > 0 {
+			hashtags = append(hashtags, h)
+		}
+	}
+
+	return &model.HashtagListResponse{Hashtags: hashtags}, nil
+}
+
+// RefreshTrending recomputes the trending hashtags and stores them in the cache.
+// Called periodically by the scheduler.
+func (s *HashtagService) RefreshTrending(ctx context.Context) error {
+	now := time.Now()
+	recentSince := now.Add(-trendingRecentWindow)
+	baselineSince := recentSince.Add(-trendingBaselineWindow)
+
+	usages, err := s.hashtagRepo.GetUsageCounts(ctx, recentSince, baselineSince, trendingMinRecentUses)
+	if err != nil {
+		return err
+	}
+
+	// The baseline is longer than the recent window, scale it down before comparing
+	ratio := trendingRecentWindow.Hours() / trendingBaselineWindow.Hours()
+
+	scores := make([]cache.HashtagScore, 0, len(usages))
+	for _, u := range usages {
+		score := trendingScore(u.Recent, u.Baseline, ratio)
+		if score <= 0 {
+			continue
+		}
+		scores = append(scores, cache.HashtagScore{Name: u.Name, Score: score})
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+	if len(scores) > trendingMaxHashtags {
+		scores = scores[:trendingMaxHashtags]
+	}
+
+	if err := s.trendingCache.SetTrendingHashtags(ctx, scores); err != nil {
+		return err
+	}
+
+	log.Printf("[HashtagService] RefreshTrending: candidates=%d trending=%d", len(usages), len(scores))
+	return nil
+}
+
+// trendingScore measures how much recent usage exceeds what the baseline predicts.
+// Hashtags that are always popular score low; sudden spikes score high.
+// ratio scales baseline usage to the length of the recent window.
+func trendingScore(recent, baseline int, ratio float64) float64 {
+	expected := float64(baseline) * ratio
+	return (float64(recent) - expected) / math.Sqrt(expected+1)
+}
+
+// normalizeHashtag turns user input ("#GoLang") into the stored form ("golang").
+func normalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
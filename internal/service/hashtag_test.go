@@ -0,0 +1,38 @@
+package service
+
+import "testing"
+
+func TestTrendingScore(t *testing.T) {
+	const ratio = 1.0 / 7
+
+	spike := trendingScore(20, 7, ratio)     // ~1/day before, 20 today
+	steady := trendingScore(100, 700, ratio) // ~100/day before, 100 today
+	quiet := trendingScore(3, 0, ratio)      // brand new, small
+
+	if spike <= steady {
+		t.Errorf("spike score %v should beat steady score %v", spike, steady)
+	}
+	if steady > 0 {
+		t.Errorf("steady usage should not trend, got %v", steady)
+	}
+	if quiet <= 0 {
+		t.Errorf("new hashtag with recent uses should score positive, got %v", quiet)
+	}
+	if spike <= quiet {
+		t.Errorf("spike score %v should beat quiet score %v", spike, quiet)
+	}
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	tests := map[string]string{
+		"#GoLang":  "golang",
+		" travel ": "travel",
+		"#":        "",
+		"":         "",
+	}
+	for in, want := range tests {
+		if got := normalizeHashtag(in); got != want {
+			t.Errorf("normalizeHashtag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"iamstagram_22520060/internal/cache"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
+)
+
+// insightsRollupBatch is how many dirty posts are popped from Redis at a time
+const insightsRollupBatch = 500
+
+// InsightsService collects post impressions and serves author insights.
+type InsightsService struct {
+	insightsCache cache.InsightsCache
+	insightsRepo  repository.InsightsRepository
+	postRepo      repository.PostRepository
+}
+
+func NewInsightsService(
+	insightsCache cache.InsightsCache,
+	insightsRepo repository.InsightsRepository,
+	postRepo repository.PostRepository,
+) *InsightsService {
+	return &InsightsService{
+		insightsCache: insightsCache,
+		insightsRepo:  insightsRepo,
+		postRepo:      postRepo,
+	}
+}
+
+// Ingest records a batch of client events into Redis counters.
+// Events for missing posts and for the viewer's own posts are dropped.
+func (s *InsightsService) Ingest(ctx context.Context, viewerID int64, req model.IngestInsightsRequest) error {
+	if len(req.Events) == 0 {
+		return model.ErrNoInsightEvents
+	}
+	if len(req.Events) > model.MaxInsightEventsPerBatch {
+		return model.ErrTooManyInsightEvents
+	}
+
+	postIDs := make([]int64, 0, len(req.Events))
+	for _, e := range req.Events {
+		if e.PostID <= 0 || (e.Type != model.InsightEventImpression && e.Type != model.InsightEventProfileVisit) {
+			return model.ErrInvalidInsightEvent
+		}
+		postIDs = append(postIDs, e.PostID)
+	}
+
+	authors, err := s.postRepo.GetAuthorIDs(ctx, postIDs)
+	if err != nil {
+		return err
+	}
+
+	return s.insightsCache.Record(ctx, viewerID, countInsightEvents(req.Events, authors, viewerID))
+}
+
+// GetPostInsights returns a post's insights. Only the author may see them.
+func (s *InsightsService) GetPostInsights(ctx context.Context, postID, userID int64) (*model.PostInsights, error) {
+	insights, err := s.insightsRepo.GetPostInsights(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if insights.UserID != userID {
+		return nil, model.ErrNotPostOwner
+	}
+	return insights, nil
+}
+
+// RollupInsights moves pending Redis counters into Postgres. Run periodically by the scheduler.
+// Counters of posts that fail to save are put back and retried on the next run.
+func (s *InsightsService) RollupInsights(ctx context.Context) error {
+	type pending struct {
+		postID   int64
+		counters cache.InsightCounters
+	}
+	var failed []pending
+	var rolled int
+
+	for {
+		postIDs, err := s.insightsCache.PopDirty(ctx, insightsRollupBatch)
+		if err != nil {
+			return err
+		}
+		if len(postIDs) == 0 {
+			break
+		}
+
+		for _, postID := range postIDs {
+			counters, err := s.insightsCache.TakeCounters(ctx, postID)
+			if err != nil {
+				log.Printf("[InsightsService] Failed to take counters: post=%d err=%v", postID, err)
+				continue
+			}
+
+			reach, err := s.insightsCache.GetReach(ctx, postID)
+			if err != nil {
+				log.Printf("[InsightsService] Failed to get reach: post=%d err=%v", postID, err)
+			}
+
+			if err := s.insightsRepo.AddCounters(ctx, postID, counters, reach); err != nil {
+				log.Printf("[InsightsService] Failed to save insights: post=%d err=%v", postID, err)
+				failed = append(failed, pending{postID: postID, counters: counters})
+				continue
+			}
+			rolled++
+		}
+	}
+
+	// Restore after the loop so failing posts aren't popped again in this run
+	for _, p := range failed {
+		if err := s.insightsCache.Restore(ctx, p.postID, p.counters); err != nil {
+			log.Printf("[InsightsService] Failed to restore counters: post=%d err=%v", p.postID, err)
+		}
+	}
+
+	log.Printf("[InsightsService] RollupInsights: posts=%d failed=%d", rolled, len(failed))
+	return nil
+}
+
+// countInsightEvents aggregates events into per-post counters. authors maps live posts
+// to their author; events for other posts and for the viewer's own posts are skipped.
+func countInsightEvents(events []model.InsightEvent, authors map[int64]int64, viewerID int64) map[int64]cache.InsightCounters {
+	counters := make(map[int64]cache.InsightCounters)
+
+	for _, e := range events {
+		authorID, ok := authors[e.PostID]
+		if !ok || authorID == viewerID {
+			continue
+		}
+
+		c := counters[e.PostID]
+		switch {
+		case e.Type == model.InsightEventProfileVisit:
+			c.ProfileVisits++
+		case e.Source == model.ImpressionSourceFeed:
+			c.ImpressionsFeed++
+		case e.Source == model.ImpressionSourceProfile:
+			c.ImpressionsProfile++
+		default:
+			c.ImpressionsOther++
+		}
+		counters[e.PostID] = c
+	}
+
+	return counters
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+
+	"iamstagram_22520060/internal/model"
+)
+
+func TestCountInsightEvents(t *testing.T) {
+	const viewerID = 1
+	authors := map[int64]int64{
+		10: 2,
+		20: 3,
+		30: viewerID, // Viewer's own post
+	}
+	events := []model.InsightEvent{
+		{PostID: 10, Type: model.InsightEventImpression, Source: model.ImpressionSourceFeed},
+		{PostID: 10, Type: model.InsightEventImpression, Source: model.ImpressionSourceFeed},
+		{PostID: 10, Type: model.InsightEventProfileVisit},
+		{PostID: 20, Type: model.InsightEventImpression, Source: model.ImpressionSourceProfile},
+		{PostID: 20, Type: model.InsightEventImpression, Source: "hashtag"},
+		{PostID: 30, Type: model.InsightEventImpression, Source: model.ImpressionSourceFeed},
+		{PostID: 99, Type: model.InsightEventImpression, Source: model.ImpressionSourceFeed}, // Missing post
+	}
+
+	got := countInsightEvents(events, authors, viewerID)
+
+	if len(got) != 2 {
+		t.Fatalf("expected counters for 2 posts, got %d: %+v", len(got), got)
+	}
+	if c := got[10]; c.ImpressionsFeed != 2 || c.ProfileVisits != 1 || c.Impressions() != 2 {
+		t.Errorf("post 10: got %+v", c)
+	}
+	if c := got[20]; c.ImpressionsProfile != 1 || c.ImpressionsOther != 1 || c.ProfileVisits != 0 {
+		t.Errorf("post 20: got %+v", c)
+	}
+}
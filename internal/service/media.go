@@ -3,7 +3,9 @@ package service
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"image"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -11,21 +13,28 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/disintegration/imaging"
 	"github.com/google/uuid"
+	_ "golang.org/x/image/webp" // register WebP decoder for imaging.Decode
 
 	"iamstagram_22520060/internal/config"
 	domain "iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
 )
 
 // MediaService handles media uploads to Cloudflare R2.
 type MediaService struct {
-	s3Client  *s3.Client
-	bucket    string
-	publicURL string
+	s3Client    *s3.Client
+	bucket      string
+	publicURL   string
+	mediaRepo   repository.MediaRepository
+	ffmpegPath  string
+	ffprobePath string
 }
 
 const (
@@ -34,7 +43,7 @@ const (
 )
 
 // NewMediaService constructs an S3-compatible client for Cloudflare R2.
-func NewMediaService(ctx context.Context, cfg *config.Config) (*MediaService, error) {
+func NewMediaService(ctx context.Context, cfg *config.Config, mediaRepo repository.MediaRepository) (*MediaService, error) {
 	if cfg.R2AccountID == "" || cfg.R2AccessKeyID == "" || cfg.R2SecretAccessKey == "" || cfg.R2BucketName == "" || cfg.R2PublicURL == "" {
 		return nil, fmt.Errorf("missing Cloudflare R2 configuration")
 	}
@@ -55,9 +64,12 @@ func NewMediaService(ctx context.Context, cfg *config.Config) (*MediaService, er
 	})
 
 	return &MediaService{
-		s3Client:  s3Client,
-		bucket:    cfg.R2BucketName,
-		publicURL: strings.TrimSuffix(cfg.R2PublicURL, "/"),
+		s3Client:    s3Client,
+		bucket:      cfg.R2BucketName,
+		publicURL:   strings.TrimSuffix(cfg.R2PublicURL, "/"),
+		mediaRepo:   mediaRepo,
+		ffmpegPath:  cfg.FFmpegPath,
+		ffprobePath: cfg.FFprobePath,
 	}, nil
 }
 
@@ -85,13 +97,14 @@ func (s *MediaService) UploadAvatar(ctx context.Context, file multipart.File, he
 
 // PresignPostUpload returns a presigned PUT URL for uploading a single post media object directly to R2.
 // The client should PUT the bytes to UploadURL with the same Content-Type, then use PublicURL in POST /posts.
-func (s *MediaService) PresignPostUpload(ctx context.Context, contentType string) (*domain.PresignPostUploadResponse, error) {
+// The issued key is recorded against the user so post creation can verify ownership.
+func (s *MediaService) PresignPostUpload(ctx context.Context, userID int64, contentType string) (*domain.PresignPostUploadResponse, error) {
 	contentType = strings.TrimSpace(contentType)
 	if contentType == "" {
 		return nil, fmt.Errorf("content_type is required")
 	}
-	if !domain.IsAllowedImageType(contentType) {
-		return nil, domain.ErrInvalidImageType
+	if !domain.IsAllowedPostMediaType(contentType) {
+		return nil, domain.ErrInvalidMediaType
 	}
 
 	ext, err := extFromContentType(contentType)
@@ -115,6 +128,10 @@ func (s *MediaService) PresignPostUpload(ctx context.Context, contentType string
 		return nil, fmt.Errorf("presign put object: %w", err)
 	}
 
+	if err := s.mediaRepo.CreateUpload(ctx, userID, key, contentType); err != nil {
+		return nil, fmt.Errorf("record upload: %w", err)
+	}
+
 	publicURL := fmt.Sprintf("%s/%s", s.publicURL, key)
 	return &domain.PresignPostUploadResponse{
 		UploadURL:  res.URL,
@@ -124,6 +141,198 @@ func (s *MediaService) PresignPostUpload(ctx context.Context, contentType string
 	}, nil
 }
 
+// ResolvePostMedia verifies the media URLs sent to POST /posts and returns them ready for storage.
+//
+// Each URL must:
+//  1. Point at our public bucket URL under the post media folder
+//  2. Have been issued by PresignPostUpload to the same user
+//  3. Not already be attached to another post
+//  4. Exist in R2 (HEAD) with an allowed content type and size under the image/video cap
+//
+// Images get their dimensions from the file header. Everything that needs the whole file
+// (dominant color, video probing and poster frames) is filled in by ProcessPostMedia from
+// the worker once the post exists.
+func (s *MediaService) ResolvePostMedia(ctx context.Context, userID int64, items []domain.CreatePostMediaItem) ([]domain.PostMediaInput, error) {
+	keys := make([]string, len(items))
+	seen := make(map[string]struct{}, len(items))
+	for i, item := range items {
+		key, ok := postMediaKeyFromURL(s.publicURL, item.MediaURL)
+		if !ok {
+			return nil, domain.ErrInvalidMediaURL
+		}
+		if _, dup := seen[key]; dup {
+			return nil, domain.ErrMediaAlreadyUsed
+		}
+		seen[key] = struct{}{}
+		keys[i] = key
+	}
+
+	uploads, err := s.mediaRepo.GetUploadsByKeys(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("get uploads: %w", err)
+	}
+	uploadsByKey := make(map[string]domain.MediaUpload, len(uploads))
+	for _, u := range uploads {
+		uploadsByKey[u.ObjectKey] = u
+	}
+
+	media := make([]domain.PostMediaInput, len(keys))
+	for i, key := range keys {
+		upload, ok := uploadsByKey[key]
+		if !ok || upload.UserID != userID {
+			return nil, domain.ErrMediaNotUploaded
+		}
+		if upload.UsedAt != nil {
+			return nil, domain.ErrMediaAlreadyUsed
+		}
+
+		head, err := s.headObject(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if head.contentType != upload.ContentType || !domain.IsAllowedPostMediaType(head.contentType) {
+			return nil, domain.ErrInvalidMediaType
+		}
+		if head.size > domain.MaxPostMediaSizeFor(head.contentType) {
+			return nil, domain.ErrFileTooLarge
+		}
+
+		media[i] = domain.PostMediaInput{
+			URL:       fmt.Sprintf("%s/%s", s.publicURL, key),
+			Key:       key,
+			MediaType: domain.MediaTypeFromContentType(head.contentType),
+			AltText:   items[i].AltText,
+		}
+
+		if media[i].MediaType == domain.MediaTypeImage {
+			if err := s.processImage(ctx, &media[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return media, nil
+}
+
+// processImage reads only the image header to record its dimensions, so creating a post
+// never downloads the whole file. Objects that don't decode as an image are rejected as an
+// invalid media type. The dominant color is filled in later by analyzeImage.
+func (s *MediaService) processImage(ctx context.Context, m *domain.PostMediaInput) error {
+	body, err := s.OpenPostMedia(ctx, m.URL)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	cfg, _, err := image.DecodeConfig(body)
+	if err != nil {
+		return domain.ErrInvalidMediaType
+	}
+	m.Width = &cfg.Width
+	m.Height = &cfg.Height
+	return nil
+}
+
+// analyzeImage decodes a stored image to record its dominant color, along with its
+// dimensions once EXIF orientation is applied. Runs from the media worker.
+func (s *MediaService) analyzeImage(ctx context.Context, m *domain.PostMediaInput) error {
+	key, ok := postMediaKeyFromURL(s.publicURL, m.URL)
+	if !ok {
+		return domain.ErrInvalidMediaURL
+	}
+	data, err := s.getObject(ctx, key, domain.MaxPostMediaSize)
+	if err != nil {
+		return err
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return domain.ErrInvalidMediaType
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	color := dominantColor(img)
+	m.Width = &width
+	m.Height = &height
+	m.DominantColor = &color
+	return nil
+}
+
+// getObject downloads an object into memory, refusing anything larger than maxSize.
+func (s *MediaService) getObject(ctx context.Context, key string, maxSize int64) ([]byte, error) {
+	out, err := s.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get object: %w", err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(out.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read object: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, domain.ErrFileTooLarge
+	}
+	return data, nil
+}
+
+// dominantColor returns the average color of the image as "#rrggbb".
+// Box-resizing to a single pixel averages every pixel, which is cheap and
+// close enough for a loading placeholder.
+func dominantColor(img image.Image) string {
+	px := imaging.Resize(img, 1, 1, imaging.Box).NRGBAAt(0, 0)
+	return fmt.Sprintf("#%02x%02x%02x", px.R, px.G, px.B)
+}
+
+// objectInfo is the subset of HEAD metadata used for upload verification.
+type objectInfo struct {
+	contentType string
+	size        int64
+}
+
+// headObject fetches object metadata. Returns ErrMediaNotUploaded if the object doesn't exist.
+func (s *MediaService) headObject(ctx context.Context, key string) (*objectInfo, error) {
+	out, err := s.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &notFound) || (errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound) {
+			return nil, domain.ErrMediaNotUploaded
+		}
+		return nil, fmt.Errorf("head object: %w", err)
+	}
+
+	info := &objectInfo{contentType: aws.ToString(out.ContentType)}
+	if out.ContentLength != nil {
+		info.size = *out.ContentLength
+	}
+	if idx := strings.Index(info.contentType, ";"); idx != -1 {
+		info.contentType = strings.TrimSpace(info.contentType[:idx])
+	}
+	return info, nil
+}
+
+// postMediaKeyFromURL extracts the object key from a public post media URL.
+// Returns false for URLs outside our bucket or outside the post media folder.
+func postMediaKeyFromURL(publicURL, mediaURL string) (string, bool) {
+	prefix := publicURL + "/"
+	if !strings.HasPrefix(mediaURL, prefix) {
+		return "", false
+	}
+	key := strings.TrimPrefix(mediaURL, prefix)
+	if !strings.HasPrefix(key, domain.PostMediaFolder+"/") || strings.Contains(key, "..") || strings.ContainsAny(key, "?#") {
+		return "", false
+	}
+	return key, true
+}
+
 func extFromContentType(contentType string) (string, error) {
 	switch contentType {
 	case domain.ContentTypeJPEG:
@@ -134,8 +343,12 @@ func extFromContentType(contentType string) (string, error) {
 		return ".gif", nil
 	case domain.ContentTypeWebP:
 		return ".webp", nil
+	case domain.ContentTypeMP4:
+		return ".mp4", nil
+	case domain.ContentTypeQuickTime:
+		return ".mov", nil
 	default:
-		return "", domain.ErrInvalidImageType
+		return "", domain.ErrInvalidMediaType
 	}
 }
 
@@ -200,6 +413,56 @@ func (s *MediaService) putObject(ctx context.Context, key string, body []byte, c
 	return nil
 }
 
+// OpenPostMedia streams a post media object given its public URL (used by data exports).
+// The caller must close the returned body.
+func (s *MediaService) OpenPostMedia(ctx context.Context, mediaURL string) (io.ReadCloser, error) {
+	key, ok := postMediaKeyFromURL(s.publicURL, mediaURL)
+	if !ok {
+		return nil, domain.ErrInvalidMediaURL
+	}
+
+	out, err := s.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get object: %w", err)
+	}
+	return out.Body, nil
+}
+
+// UploadPrivateObject uploads a file that is only handed out through presigned URLs.
+// body must be seekable so the SDK can sign the payload.
+func (s *MediaService) UploadPrivateObject(ctx context.Context, key string, body io.ReadSeeker, contentType string) error {
+	_, err := s.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:       aws.String(s.bucket),
+		Key:          aws.String(key),
+		Body:         body,
+		ContentType:  aws.String(contentType),
+		CacheControl: aws.String("private, no-store"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload to r2: %w", err)
+	}
+	return nil
+}
+
+// PresignDownload returns a presigned GET URL that downloads the object as filename.
+func (s *MediaService) PresignDownload(ctx context.Context, key, filename string, expires time.Duration) (string, error) {
+	presigner := s3.NewPresignClient(s.s3Client)
+	res, err := presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     aws.String(s.bucket),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String(fmt.Sprintf("attachment; filename=%q", filename)),
+	}, func(po *s3.PresignOptions) {
+		po.Expires = expires
+	})
+	if err != nil {
+		return "", fmt.Errorf("presign get object: %w", err)
+	}
+	return res.URL, nil
+}
+
 // DeleteObject removes an object by key. Callers should ensure the key is not the shared default.
 func (s *MediaService) DeleteObject(ctx context.Context, key string) error {
 	if key == "" {
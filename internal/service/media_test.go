@@ -0,0 +1,140 @@
+package service
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPostMediaKeyFromURL(t *testing.T) {
+	const publicURL = "https://cdn.example.com"
+
+	tests := []struct {
+		name    string
+		url     string
+		wantKey string
+		wantOK  bool
+	}{
+		{
+			name:    "valid post media url",
+			url:     "https://cdn.example.com/posts/abc.jpg",
+			wantKey: "posts/abc.jpg",
+			wantOK:  true,
+		},
+		{
+			name:   "external url",
+			url:    "https://evil.example.com/posts/abc.jpg",
+			wantOK: false,
+		},
+		{
+			name:   "prefix lookalike host",
+			url:    "https://cdn.example.com.evil.com/posts/abc.jpg",
+			wantOK: false,
+		},
+		{
+			name:   "outside post folder",
+			url:    "https://cdn.example.com/avatars/abc.jpg",
+			wantOK: false,
+		},
+		{
+			name:   "path traversal",
+			url:    "https://cdn.example.com/posts/../avatars/abc.jpg",
+			wantOK: false,
+		},
+		{
+			name:   "query string",
+			url:    "https://cdn.example.com/posts/abc.jpg?x=1",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := postMediaKeyFromURL(publicURL, tt.url)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && key != tt.wantKey {
+				t.Errorf("key = %q, want %q", key, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestParseFFprobeOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		wantWidth  int
+		wantHeight int
+		wantMs     int
+		wantErr    bool
+	}{
+		{
+			name:       "landscape",
+			output:     `{"streams":[{"width":1920,"height":1080}],"format":{"duration":"12.345"}}`,
+			wantWidth:  1920,
+			wantHeight: 1080,
+			wantMs:     12345,
+		},
+		{
+			name:       "portrait via rotate tag",
+			output:     `{"streams":[{"width":1920,"height":1080,"tags":{"rotate":"90"}}],"format":{"duration":"3.0"}}`,
+			wantWidth:  1080,
+			wantHeight: 1920,
+			wantMs:     3000,
+		},
+		{
+			name:       "portrait via display matrix",
+			output:     `{"streams":[{"width":1920,"height":1080,"side_data_list":[{"rotation":-90}]}],"format":{"duration":"1.5"}}`,
+			wantWidth:  1080,
+			wantHeight: 1920,
+			wantMs:     1500,
+		},
+		{
+			name:    "no video stream",
+			output:  `{"streams":[],"format":{"duration":"1.0"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			output:  `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := parseFFprobeOutput([]byte(tt.output))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", info)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if info.width != tt.wantWidth || info.height != tt.wantHeight || info.durationMs != tt.wantMs {
+				t.Errorf("got %dx%d %dms, want %dx%d %dms", info.width, info.height, info.durationMs, tt.wantWidth, tt.wantHeight, tt.wantMs)
+			}
+		})
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			c := color.NRGBA{R: 255, A: 255}
+			if x >= 2 {
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	if got := dominantColor(img); got != "#800080" && got != "#7f007f" {
+		t.Errorf("dominantColor() = %q, want half red/half blue average", got)
+	}
+}
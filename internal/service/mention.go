@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/queue"
+	"iamstagram_22520060/internal/repository"
+)
+
+// resolveMentions finds "@username" references in text and resolves them to users.
+// Unknown usernames, and users blocked by or blocking the author, stay plain text.
+// Only the first model.MaxMentionsPerText distinct users are linked.
+func resolveMentions(ctx context.Context, userRepo repository.UserRepository, blockRepo repository.BlockRepository, authorID int64, text string) ([]model.Mention, error) {
+	tokens := extractMentions(text)
+	if len(tokens) == 0 {
+		return []model.Mention{}, nil
+	}
+
+	usernames := make([]string, 0, len(tokens))
+	seen := make(map[string]bool)
+	for _, t := range tokens {
+		if !seen[t.Value] {
+			seen[t.Value] = true
+			usernames = append(usernames, t.Value)
+		}
+	}
+
+	users, err := userRepo.GetSummariesByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, fmt.Errorf("resolve mentions: %w", err)
+	}
+	if len(users) == 0 {
+		return []model.Mention{}, nil
+	}
+
+	blockedIDs, err := blockRepo.GetBlockedIDs(ctx, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve mentions: %w", err)
+	}
+	blocked := make(map[int64]bool, len(blockedIDs))
+	for _, id := range blockedIDs {
+		blocked[id] = true
+	}
+
+	byName := make(map[string]model.UserSummary, len(users))
+	for _, u := range users {
+		if !blocked[u.ID] {
+			byName[u.Username] = u
+		}
+	}
+
+	mentions := make([]model.Mention, 0, len(tokens))
+	linked := make(map[int64]bool)
+	for _, t := range tokens {
+		u, ok := byName[t.Value]
+		if !ok {
+			continue
+		}
+		if !linked[u.ID] && len(linked) >= model.MaxMentionsPerText {
+			continue
+		}
+		linked[u.ID] = true
+		mentions = append(mentions, model.Mention{
+			UserID:   u.ID,
+			Username: u.Username,
+			Offset:   t.Offset,
+			Length:   t.Length,
+		})
+	}
+
+	return mentions, nil
+}
+
+// newMentionRecipients returns the distinct users in next that are not in prev,
+// skipping excluded users (the author, or someone already notified another way).
+func newMentionRecipients(prev, next []model.Mention, exclude ...int64) []int64 {
+	skip := make(map[int64]bool, len(prev)+len(exclude))
+	for _, m := range prev {
+		skip[m.UserID] = true
+	}
+	for _, id := range exclude {
+		skip[id] = true
+	}
+
+	var recipients []int64
+	for _, m := range next {
+		if skip[m.UserID] {
+			continue
+		}
+		skip[m.UserID] = true
+		recipients = append(recipients, m.UserID)
+	}
+	return recipients
+}
+
+// publishMentionEvents publishes a mention notification event per recipient (best-effort).
+func publishMentionEvents(ctx context.Context, publisher queue.Publisher, postID int64, commentID *int64, actorID int64, recipients []int64) {
+	if publisher == nil {
+		return
+	}
+	for _, recipientID := range recipients {
+		event := queue.NewUserMentionedEvent(postID, commentID, actorID, recipientID)
+		if _, err := publisher.Publish(ctx, queue.StreamFeed, event); err != nil {
+			log.Printf("[Mentions] Failed to publish UserMentioned event: post=%d recipient=%d err=%v", postID, recipientID, err)
+		}
+	}
+}
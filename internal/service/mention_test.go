@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"iamstagram_22520060/internal/model"
+)
+
+func TestNewMentionRecipients(t *testing.T) {
+	prev := []model.Mention{{UserID: 1}, {UserID: 2}}
+	next := []model.Mention{{UserID: 2}, {UserID: 3}, {UserID: 3}, {UserID: 4}, {UserID: 5}}
+
+	got := newMentionRecipients(prev, next, 5)
+	want := []int64{3, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newMentionRecipients() = %v, want %v", got, want)
+	}
+
+	if got := newMentionRecipients(next, next); got != nil {
+		t.Errorf("unchanged mentions should notify nobody, got %v", got)
+	}
+}
+
+func TestResolveMentions_SkipsBlockedUsers(t *testing.T) {
+	userRepo := &mockUserRepository{summaries: []model.UserSummary{
+		{ID: 2, Username: "alice"},
+		{ID: 3, Username: "bob"},
+		{ID: 4, Username: "carol"},
+	}}
+	// The author (1) blocked bob; carol blocked the author
+	blockRepo := &mockBlockRepository{blocks: map[[2]int64]bool{{1, 3}: true, {4, 1}: true}}
+
+	mentions, err := resolveMentions(context.Background(), userRepo, blockRepo, 1, "hi @alice @bob @carol")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mentions) != 1 || mentions[0].UserID != 2 {
+		t.Errorf("mentions = %+v, want only alice", mentions)
+	}
+}
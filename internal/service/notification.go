@@ -14,6 +14,7 @@ type NotificationService struct {
 	notifRepo repository.NotificationRepository
 	tokenRepo repository.DeviceTokenRepository
 	userRepo  repository.UserRepository
+	blockRepo repository.BlockRepository
 	expoPush  *ExpoPushClient // Can be nil if push not configured
 }
 
@@ -21,19 +22,21 @@ func NewNotificationService(
 	notifRepo repository.NotificationRepository,
 	tokenRepo repository.DeviceTokenRepository,
 	userRepo repository.UserRepository,
+	blockRepo repository.BlockRepository,
 	expoPush *ExpoPushClient,
 ) *NotificationService {
 	return &NotificationService{
 		notifRepo: notifRepo,
 		tokenRepo: tokenRepo,
 		userRepo:  userRepo,
+		blockRepo: blockRepo,
 		expoPush:  expoPush,
 	}
 }
 
 // GetNotifications returns all notifications for a user.
 // - Follow notifications are returned individually (not aggregated)
-// - Like/Comment notifications are aggregated by post (e.g., "user1 and 5 others liked your post")
+// - Like/Comment/Tag/Mention notifications are aggregated by post (e.g., "user1 and 5 others liked your post")
 // Unread count is computed from the fetched data (no extra query).
 func (s *NotificationService) GetNotifications(ctx context.Context, userID int64, limit int) (*model.NotificationListResponse, error) {
 	if limit <= 0 {
@@ -115,6 +118,15 @@ func (s *NotificationService) CreateNotification(
 		return nil
 	}
 
+	// Events queued before a block must not notify either side
+	blocked, err := s.blockRepo.IsBlocked(ctx, userID, actorID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return nil
+	}
+
 	// Insert notification into database
 	if err := s.notifRepo.Create(ctx, userID, actorID, notifType, postID, commentID); err != nil {
 		return err
@@ -128,6 +140,20 @@ func (s *NotificationService) CreateNotification(
 	return nil
 }
 
+// CreateSelfNotification notifies a user about their own account (e.g. a finished data export).
+// The user is recorded as the actor, which CreateNotification would skip.
+func (s *NotificationService) CreateSelfNotification(ctx context.Context, userID int64, notifType string) error {
+	if err := s.notifRepo.Create(ctx, userID, userID, notifType, nil, nil); err != nil {
+		return err
+	}
+
+	if s.expoPush != nil {
+		go s.sendPushNotification(context.Background(), userID, userID, notifType, nil)
+	}
+
+	return nil
+}
+
 // sendPushNotification sends a push notification to all of the user's devices.
 // This is called asynchronously - errors are logged but don't fail the request.
 func (s *NotificationService) sendPushNotification(ctx context.Context, userID, actorID int64, notifType string, postID *int64) {
@@ -185,6 +211,21 @@ func (s *NotificationService) buildPushMessage(actorUsername, notifType string)
 	case model.NotificationTypeComment:
 		title = "New Comment"
 		body = actorUsername + " commented on your post"
+	case model.NotificationTypeTag:
+		title = "New Tag"
+		body = actorUsername + " tagged you in a post"
+	case model.NotificationTypeMention:
+		title = "New Mention"
+		body = actorUsername + " mentioned you"
+	case model.NotificationTypeCommentLike:
+		title = "New Like"
+		body = actorUsername + " liked your comment"
+	case model.NotificationTypeCommentReply:
+		title = "New Reply"
+		body = actorUsername + " replied to your comment"
+	case model.NotificationTypeDataExport:
+		title = "Your Data Is Ready"
+		body = "Your data export is ready to download"
 	default:
 		title = "Iamstagram"
 		body = "You have a new notification"
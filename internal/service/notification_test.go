@@ -0,0 +1,36 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"iamstagram_22520060/internal/model"
+)
+
+// GetAggregatedNotifications groups by (type, post, comment if per-comment). Types about a
+// specific comment must be per-comment, or likes on different comments of a post merge into
+// one group without a comment_id.
+func TestNotificationGrouping(t *testing.T) {
+	tests := []struct {
+		notifType  string
+		perComment bool
+	}{
+		{model.NotificationTypeLike, false},
+		{model.NotificationTypeComment, false},
+		{model.NotificationTypeTag, false},
+		{model.NotificationTypeMention, true}, // caption mentions have a NULL comment_id and still group per post
+		{model.NotificationTypeCommentLike, true},
+		{model.NotificationTypeCommentReply, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notifType, func(t *testing.T) {
+			if !slices.Contains(model.AggregatedNotificationTypes, tt.notifType) {
+				t.Fatalf("%s is not aggregated", tt.notifType)
+			}
+			if got := slices.Contains(model.PerCommentNotificationTypes, tt.notifType); got != tt.perComment {
+				t.Errorf("per comment = %v, want %v", got, tt.perComment)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"iamstagram_22520060/internal/model"
+)
+
+// newOneTimeToken generates a raw token to send by email and the token to store for it.
+// Shared by password reset and email verification.
+func newOneTimeToken(userID int64, ttl time.Duration) (string, model.OneTimeToken) {
+	tokenRaw := uuid.New().String()
+	return tokenRaw, model.OneTimeToken{
+		UserID:    userID,
+		TokenHash: hashToken(tokenRaw),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"iamstagram_22520060/internal/model"
+)
+
+// mockTokenStore backs the one-time token repository mocks. Token types embed
+// model.OneTimeToken; base returns that part of a token.
+type mockTokenStore[T any] struct {
+	tokens     map[string]*T // by hash
+	created    []*T
+	base       func(*T) *model.OneTimeToken
+	errInvalid error
+}
+
+func newMockTokenStore[T any](base func(*T) *model.OneTimeToken, errInvalid error, tokens ...*T) mockTokenStore[T] {
+	store := mockTokenStore[T]{tokens: make(map[string]*T), base: base, errInvalid: errInvalid}
+	for _, token := range tokens {
+		store.tokens[base(token).TokenHash] = token
+	}
+	return store
+}
+
+func (m *mockTokenStore[T]) Create(ctx context.Context, token *T) error {
+	m.created = append(m.created, token)
+	return nil
+}
+
+func (m *mockTokenStore[T]) FindValid(ctx context.Context, tokenHash string) (*T, error) {
+	token, ok := m.tokens[tokenHash]
+	if !ok || m.base(token).UsedAt != nil || time.Now().After(m.base(token).ExpiresAt) {
+		return nil, m.errInvalid
+	}
+	return token, nil
+}
+
+func (m *mockTokenStore[T]) markUsed(id int64) error {
+	for _, token := range m.tokens {
+		if base := m.base(token); base.ID == id && base.UsedAt == nil {
+			now := time.Now()
+			base.UsedAt = &now
+			return nil
+		}
+	}
+	return m.errInvalid
+}
+
+func TestNewOneTimeToken(t *testing.T) {
+	raw, token := newOneTimeToken(7, time.Hour)
+
+	if token.UserID != 7 {
+		t.Errorf("UserID = %d, want 7", token.UserID)
+	}
+	if token.TokenHash != hashToken(raw) {
+		t.Error("TokenHash is not the hash of the raw token")
+	}
+	if until := time.Until(token.ExpiresAt); until <= 59*time.Minute || until > time.Hour {
+		t.Errorf("expires in %v, want 1h", until)
+	}
+	if other, _ := newOneTimeToken(7, time.Hour); other == raw {
+		t.Error("raw tokens should be unique")
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"iamstagram_22520060/internal/model"
+)
+
+// commonPasswords is a short denylist of the most used (and first guessed) passwords.
+// Entries are lowercase; the check is case-insensitive.
+var commonPasswords = map[string]bool{
+	"password": true, "password1": true, "password12": true, "password123": true,
+	"passw0rd": true, "p@ssw0rd": true, "12345678": true, "123456789": true,
+	"1234567890": true, "0123456789": true, "87654321": true, "11111111": true,
+	"00000000": true, "12341234": true, "11223344": true, "123123123": true,
+	"qwertyui": true, "qwerty123": true, "qwertyuiop": true, "1q2w3e4r": true,
+	"1qaz2wsx": true, "asdfghjk": true, "zxcvbnm1": true, "abcd1234": true,
+	"abc12345": true, "iloveyou": true, "iloveyou1": true, "sunshine": true,
+	"princess": true, "football": true, "baseball": true, "superman": true,
+	"starwars": true, "whatever": true, "trustno1": true, "letmein1": true,
+	"welcome1": true, "welcome123": true, "admin123": true, "changeme": true,
+	"dragon123": true, "monkey123": true, "computer": true, "internet": true,
+	"iamstagram": true, "instagram": true, "matkhau123": true, "anhyeuem": true,
+	"emyeuanh": true, "motconvit": true,
+}
+
+// validatePassword enforces the password policy for new passwords: length within
+// model.MinPasswordLength-model.MaxPasswordLength bytes, not a common password and
+// not the username.
+func validatePassword(password, username string) error {
+	if utf8.RuneCountInString(password) < model.MinPasswordLength {
+		return model.ErrPasswordTooShort
+	}
+	if len(password) > model.MaxPasswordLength {
+		return model.ErrPasswordTooLong
+	}
+
+	lower := strings.ToLower(password)
+	if commonPasswords[lower] || (username != "" && lower == strings.ToLower(username)) {
+		return model.ErrPasswordTooCommon
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/crypto/bcrypt"
+
+	"iamstagram_22520060/internal/config"
+	"iamstagram_22520060/internal/mail"
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
+)
+
+// PasswordResetService handles "forgot password": emailing a single-use token and
+// setting a new password with it.
+type PasswordResetService struct {
+	userRepo         repository.UserRepository
+	resetRepo        repository.PasswordResetRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	mailer           mail.Mailer
+	db               *sqlx.DB
+	config           *config.Config
+}
+
+func NewPasswordResetService(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	mailer mail.Mailer,
+	db *sqlx.DB,
+	cfg *config.Config,
+) *PasswordResetService {
+	return &PasswordResetService{
+		userRepo:         userRepo,
+		resetRepo:        resetRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		mailer:           mailer,
+		db:               db,
+		config:           cfg,
+	}
+}
+
+// RequestReset emails a reset link to the account identified by login (username or email).
+// It returns nil when there is no such account or it has no verified email, so callers
+// can't use it to probe which accounts exist.
+func (s *PasswordResetService) RequestReset(ctx context.Context, login string) error {
+	login = strings.TrimSpace(login)
+
+	var user *model.User
+	var err error
+	if strings.Contains(login, "@") {
+		user, err = s.userRepo.GetByEmail(ctx, login)
+	} else {
+		user, err = s.userRepo.GetByUsername(ctx, login)
+	}
+	if errors.Is(err, model.ErrUserNotFound) {
+		log.Printf("[PasswordResetService] Reset requested for unknown login %q", login)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if user.Email == nil || !user.EmailVerified {
+		log.Printf("[PasswordResetService] Reset requested for user %d without a verified email", user.ID)
+		return nil
+	}
+
+	tokenRaw, base := newOneTimeToken(user.ID, model.PasswordResetTokenTTL)
+	token := &model.PasswordResetToken{OneTimeToken: base}
+	if err := s.resetRepo.Create(ctx, token); err != nil {
+		return err
+	}
+
+	// Send async so the response time doesn't reveal whether an email went out
+	go s.sendResetEmail(context.Background(), *user.Email, user.Username, tokenRaw)
+
+	log.Printf("[PasswordResetService] Reset token issued for user %d", user.ID)
+	return nil
+}
+
+// ConfirmReset sets a new password using a reset token and signs out every session.
+func (s *PasswordResetService) ConfirmReset(ctx context.Context, tokenRaw, newPassword string) error {
+	token, err := s.resetRepo.FindValid(ctx, hashToken(tokenRaw))
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Validate before consuming the token so a rejected password doesn't burn the link
+	if err := validatePassword(newPassword, user.Username); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// All or nothing: a used-up token with the old password, or a new password with
+	// the old sessions still signed in, would both leave the account in a bad state
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.resetRepo.MarkUsed(ctx, tx, token.ID); err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePasswordTx(ctx, tx, user.ID, string(hashedPassword)); err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.RevokeAllForUserTx(ctx, tx, user.ID); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	log.Printf("[PasswordResetService] User %d reset their password", user.ID)
+	return nil
+}
+
+// sendResetEmail delivers the reset link. Errors are logged; the user can request again.
+func (s *PasswordResetService) sendResetEmail(ctx context.Context, to, username, tokenRaw string) {
+	link := fmt.Sprintf("%s?token=%s", s.config.PasswordResetURL, url.QueryEscape(tokenRaw))
+
+	msg := mail.Message{
+		To:      to,
+		Subject: "Reset your Iamstagram password",
+		Body: fmt.Sprintf(
+			"Hi @%s,\n\n"+
+				"Someone asked to reset the password of your Iamstagram account.\n"+
+				"Open this link within %d minutes to choose a new one:\n\n%s\n\n"+
+				"If it wasn't you, ignore this email - your password stays the same.\n",
+			username, int(model.PasswordResetTokenTTL.Minutes()), link,
+		),
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("[PasswordResetService] Failed to send reset email to user @%s: %v", username, err)
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/crypto/bcrypt"
+
+	"iamstagram_22520060/internal/config"
+	"iamstagram_22520060/internal/mail"
+	"iamstagram_22520060/internal/model"
+)
+
+// txOnlyDriver is a database/sql driver that can only begin, commit and roll back
+// transactions, for services that open a tx but run every query through mocked repos.
+type txOnlyDriver struct{}
+
+func (txOnlyDriver) Open(name string) (driver.Conn, error) { return txOnlyConn{}, nil }
+
+type txOnlyConn struct{}
+
+func (txOnlyConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("txOnlyDriver: queries are not supported")
+}
+func (txOnlyConn) Close() error              { return nil }
+func (txOnlyConn) Begin() (driver.Tx, error) { return txOnlyTx{}, nil }
+
+type txOnlyTx struct{}
+
+func (txOnlyTx) Commit() error   { return nil }
+func (txOnlyTx) Rollback() error { return nil }
+
+func newTxOnlyDB() *sqlx.DB {
+	return sqlx.NewDb(sql.OpenDB(txOnlyConnector{}), "postgres")
+}
+
+type txOnlyConnector struct{}
+
+func (txOnlyConnector) Connect(ctx context.Context) (driver.Conn, error) { return txOnlyConn{}, nil }
+func (txOnlyConnector) Driver() driver.Driver                            { return txOnlyDriver{} }
+
+type mockPasswordResetRepository struct {
+	mockTokenStore[model.PasswordResetToken]
+}
+
+func newMockPasswordResetRepository(tokens ...*model.PasswordResetToken) *mockPasswordResetRepository {
+	base := func(t *model.PasswordResetToken) *model.OneTimeToken { return &t.OneTimeToken }
+	return &mockPasswordResetRepository{newMockTokenStore(base, model.ErrResetTokenInvalid, tokens...)}
+}
+
+func (m *mockPasswordResetRepository) MarkUsed(ctx context.Context, tx *sqlx.Tx, id int64) error {
+	return m.markUsed(id)
+}
+
+type mockRefreshTokenRepository struct {
+	revokedAllFor []int64
+}
+
+func (m *mockRefreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	return nil
+}
+
+func (m *mockRefreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	return nil, model.ErrRefreshTokenNotFound
+}
+
+func (m *mockRefreshTokenRepository) Revoke(ctx context.Context, id string, replacedBy *string) error {
+	return nil
+}
+
+func (m *mockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int64) error {
+	m.revokedAllFor = append(m.revokedAllFor, userID)
+	return nil
+}
+
+func (m *mockRefreshTokenRepository) RevokeAllForUserTx(ctx context.Context, tx *sqlx.Tx, userID int64) error {
+	return m.RevokeAllForUser(ctx, userID)
+}
+
+func (m *mockRefreshTokenRepository) DeleteExpired(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func TestPasswordResetService_ConfirmReset(t *testing.T) {
+	newService := func() (*PasswordResetService, *mockUserRepository, *mockPasswordResetRepository, *mockRefreshTokenRepository) {
+		userRepo := &mockUserRepository{
+			getByIDFn: func(ctx context.Context, id int64) (*model.User, error) {
+				return &model.User{ID: id, Username: "testuser"}, nil
+			},
+		}
+		resetRepo := newMockPasswordResetRepository(&model.PasswordResetToken{OneTimeToken: model.OneTimeToken{
+			ID: 1, UserID: 7, TokenHash: hashToken("valid-token"), ExpiresAt: time.Now().Add(time.Hour),
+		}})
+		refreshRepo := &mockRefreshTokenRepository{}
+		svc := NewPasswordResetService(userRepo, resetRepo, refreshRepo, mail.NewLogMailer("", ""), newTxOnlyDB(), &config.Config{})
+		return svc, userRepo, resetRepo, refreshRepo
+	}
+
+	t.Run("success", func(t *testing.T) {
+		svc, userRepo, _, refreshRepo := newService()
+
+		if err := svc.ConfirmReset(context.Background(), "valid-token", "brand-new-secret"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if bcrypt.CompareHashAndPassword([]byte(userRepo.updatedPasswordHash), []byte("brand-new-secret")) != nil {
+			t.Error("stored hash does not match the new password")
+		}
+		if len(refreshRepo.revokedAllFor) != 1 || refreshRepo.revokedAllFor[0] != 7 {
+			t.Errorf("sessions revoked for %v, want [7]", refreshRepo.revokedAllFor)
+		}
+		// Single use
+		if err := svc.ConfirmReset(context.Background(), "valid-token", "another-secret"); !errors.Is(err, model.ErrResetTokenInvalid) {
+			t.Errorf("second use: error = %v, want ErrResetTokenInvalid", err)
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		svc, _, _, _ := newService()
+		if err := svc.ConfirmReset(context.Background(), "nope", "brand-new-secret"); !errors.Is(err, model.ErrResetTokenInvalid) {
+			t.Errorf("error = %v, want ErrResetTokenInvalid", err)
+		}
+	})
+
+	t.Run("weak password keeps the token usable", func(t *testing.T) {
+		svc, userRepo, resetRepo, _ := newService()
+
+		if err := svc.ConfirmReset(context.Background(), "valid-token", "password"); !errors.Is(err, model.ErrPasswordTooCommon) {
+			t.Fatalf("error = %v, want ErrPasswordTooCommon", err)
+		}
+		if userRepo.updatedPasswordHash != "" {
+			t.Error("password should not be updated")
+		}
+		if resetRepo.tokens[hashToken("valid-token")].UsedAt != nil {
+			t.Error("token should not be consumed by a rejected password")
+		}
+	})
+}
+
+func TestPasswordResetService_RequestReset_RequiresVerifiedEmail(t *testing.T) {
+	email := "john@example.com"
+	for _, verified := range []bool{false, true} {
+		userRepo := &mockUserRepository{
+			getByUsernameFn: func(ctx context.Context, username string) (*model.User, error) {
+				return &model.User{ID: 1, Username: username, Email: &email, EmailVerified: verified}, nil
+			},
+		}
+		resetRepo := newMockPasswordResetRepository()
+		svc := NewPasswordResetService(userRepo, resetRepo, &mockRefreshTokenRepository{}, mail.NewLogMailer("", ""), nil, &config.Config{})
+
+		if err := svc.RequestReset(context.Background(), "testuser"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := len(resetRepo.created); got != map[bool]int{false: 0, true: 1}[verified] {
+			t.Errorf("verified=%v: %d tokens created", verified, got)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"iamstagram_22520060/internal/model"
+)
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     error
+	}{
+		{name: "ok", password: "correct horse battery", want: nil},
+		{name: "too short", password: "abc123", want: model.ErrPasswordTooShort},
+		{name: "multi-byte counts runes for min length", password: "mậtkhẩu", want: model.ErrPasswordTooShort},
+		{name: "too long", password: strings.Repeat("x", model.MaxPasswordLength+1), want: model.ErrPasswordTooLong},
+		{name: "common", password: "password123", want: model.ErrPasswordTooCommon},
+		{name: "common any case", password: "PassWord123", want: model.ErrPasswordTooCommon},
+		{name: "same as username", password: "JohnDoe99", want: model.ErrPasswordTooCommon},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validatePassword(tt.password, "johndoe99"); got != tt.want {
+				t.Errorf("validatePassword(%q) = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
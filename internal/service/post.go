@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/jmoiron/sqlx"
 
@@ -13,45 +16,129 @@ import (
 )
 
 type PostService struct {
-	postRepo  repository.PostRepository
-	userRepo  repository.UserRepository
-	publisher queue.Publisher
-	db        *sqlx.DB
+	postRepo     repository.PostRepository
+	userRepo     repository.UserRepository
+	followRepo   repository.FollowRepository
+	blockRepo    repository.BlockRepository
+	tagRepo      repository.TagRepository
+	hashtagRepo  repository.HashtagRepository
+	mentionRepo  repository.MentionRepository
+	repostRepo   repository.RepostRepository
+	mediaService *MediaService
+	publisher    queue.Publisher
+	db           *sqlx.DB
 }
 
 func NewPostService(
 	postRepo repository.PostRepository,
 	userRepo repository.UserRepository,
+	followRepo repository.FollowRepository,
+	blockRepo repository.BlockRepository,
+	tagRepo repository.TagRepository,
+	hashtagRepo repository.HashtagRepository,
+	mentionRepo repository.MentionRepository,
+	repostRepo repository.RepostRepository,
+	mediaService *MediaService,
 	publisher queue.Publisher,
 	db *sqlx.DB,
 ) *PostService {
 	return &PostService{
-		postRepo:  postRepo,
-		userRepo:  userRepo,
-		publisher: publisher,
-		db:        db,
+		postRepo:     postRepo,
+		userRepo:     userRepo,
+		followRepo:   followRepo,
+		blockRepo:    blockRepo,
+		tagRepo:      tagRepo,
+		hashtagRepo:  hashtagRepo,
+		mentionRepo:  mentionRepo,
+		repostRepo:   repostRepo,
+		mediaService: mediaService,
+		publisher:    publisher,
+		db:           db,
 	}
 }
 
 // Create creates a new post and publishes an event for fan-out.
 func (s *PostService) Create(ctx context.Context, userID int64, req model.CreatePostRequest) (*model.Post, error) {
 	// Validate
-	if len(req.MediaURLs) == 0 {
+	items := req.MediaItems()
+	if len(items) == 0 {
 		return nil, model.ErrNoMediaProvided
 	}
-	if len(req.MediaURLs) > model.MaxPostMediaCount {
+	if len(items) > model.MaxPostMediaCount {
 		return nil, model.ErrTooManyMedia
 	}
-	if req.Caption != nil && len(*req.Caption) > model.MaxPostCaptionLength {
-		return nil, model.ErrCaptionTooLong
+	hashtags, err := parseCaption(req.Caption)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if items[i].AltText == nil {
+			continue
+		}
+		alt := strings.TrimSpace(*items[i].AltText)
+		if utf8.RuneCountInString(alt) > model.MaxAltTextLength {
+			return nil, model.ErrAltTextTooLong
+		}
+		if alt == "" {
+			items[i].AltText = nil
+		} else {
+			items[i].AltText = &alt
+		}
+	}
+
+	// Validate tags before touching R2
+	var taggedIDs []int64
+	for _, item := range items {
+		if err := validateMediaTags(item.Tags); err != nil {
+			return nil, err
+		}
+		for _, t := range item.Tags {
+			taggedIDs = append(taggedIDs, t.UserID)
+		}
+	}
+	if err := s.checkUsersExist(ctx, userID, taggedIDs); err != nil {
+		return nil, err
+	}
+
+	mentions, err := s.captionMentions(ctx, userID, req.Caption)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify every media URL is an upload we issued to this user and that it landed in R2
+	media, err := s.mediaService.ResolvePostMedia(ctx, userID, items)
+	if err != nil {
+		return nil, err
+	}
+	for i := range media {
+		media[i].Tags = items[i].Tags
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
 	// Create post in DB
-	post, err := s.postRepo.Create(ctx, userID, req.Caption, req.MediaURLs)
+	post, err := s.postRepo.Create(ctx, tx, userID, req.Caption, req.PostSettings, media)
 	if err != nil {
 		return nil, fmt.Errorf("create post: %w", err)
 	}
 
+	if err := s.hashtagRepo.SetPostHashtags(ctx, tx, post.ID, hashtags); err != nil {
+		return nil, err
+	}
+
+	if err := s.mentionRepo.ReplacePostMentions(ctx, tx, post.ID, mentions); err != nil {
+		return nil, err
+	}
+	post.Mentions = mentions
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
 	// Publish event for async fan-out
 	event := queue.NewPostCreatedEvent(post.ID, userID)
 	msgID, err := s.publisher.Publish(ctx, queue.StreamFeed, event)
@@ -62,6 +149,16 @@ func (s *PostService) Create(ctx context.Context, userID int64, req model.Create
 		log.Printf("[PostService] Published PostCreated: post=%d msgID=%s", post.ID, msgID)
 	}
 
+	// Dominant colors, video metadata and poster frames are filled in the background
+	if _, err := s.publisher.Publish(ctx, queue.StreamMedia, queue.NewPostMediaAddedEvent(post.ID, userID)); err != nil {
+		log.Printf("[PostService] Failed to publish PostMediaAdded event: post=%d err=%v", post.ID, err)
+	}
+
+	s.publishTagEvents(ctx, post.ID, userID, taggedIDs)
+
+	// Users tagged in the post already get a tag notification
+	publishMentionEvents(ctx, s.publisher, post.ID, nil, userID, newMentionRecipients(nil, mentions, append(taggedIDs, userID)...))
+
 	// Fetch author info
 	author, err := s.userRepo.GetByID(ctx, userID)
 	if err == nil {
@@ -76,8 +173,65 @@ func (s *PostService) Create(ctx context.Context, userID int64, req model.Create
 	return post, nil
 }
 
+// ProcessPostMedia computes dominant colors for a new post's images, probes its videos and
+// generates their poster frames. Called by the worker; media already processed (redelivered
+// events) is skipped.
+func (s *PostService) ProcessPostMedia(ctx context.Context, postID int64) error {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if errors.Is(err, model.ErrPostNotFound) {
+		log.Printf("[PostService] Post %d was deleted before its media was processed", postID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, media := range post.Media {
+		var m model.PostMediaInput
+		switch {
+		case media.MediaType == model.MediaTypeImage && media.DominantColor == nil:
+			m = model.PostMediaInput{URL: media.MediaURL, MediaType: media.MediaType}
+			if err := s.mediaService.analyzeImage(ctx, &m); err != nil {
+				log.Printf("[PostService] Failed to analyze image %s: %v", media.MediaURL, err)
+				continue
+			}
+		case media.MediaType == model.MediaTypeVideo && media.ThumbnailKey == nil:
+			m = model.PostMediaInput{URL: media.MediaURL, MediaType: media.MediaType}
+			s.mediaService.processVideo(ctx, &m)
+			if m.Width == nil && m.ThumbnailKey == nil {
+				continue // Nothing to store; failures were logged
+			}
+		default:
+			continue
+		}
+
+		if err := s.postRepo.UpdateMediaMetadata(ctx, media.ID, &m); err != nil {
+			// Nothing points at the poster frame if the post is gone
+			if m.ThumbnailKey != nil {
+				if delErr := s.mediaService.DeleteObject(ctx, *m.ThumbnailKey); delErr != nil {
+					log.Printf("[PostService] Failed to delete orphaned thumbnail %s: %v", *m.ThumbnailKey, delErr)
+				}
+			}
+			if errors.Is(err, model.ErrPostNotFound) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 // GetByID retrieves a single post with full details.
+// Posts of private accounts are reported as not found to viewers who don't follow the author.
 func (s *PostService) GetByID(ctx context.Context, postID int64, viewerID *int64) (*model.Post, error) {
+	visible, err := s.postRepo.IsVisible(ctx, postID, viewerOrAnonymous(viewerID))
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, model.ErrPostNotFound
+	}
+
 	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return nil, err
@@ -94,7 +248,9 @@ func (s *PostService) GetByID(ctx context.Context, postID int64, viewerID *int64
 		}
 	}
 
-	// Check if viewer liked this post
+	post.HideLikeCountFrom(viewerID)
+
+	// Check if viewer liked/saved/reposted this post
 	if viewerID != nil {
 		likeStatus, err := s.postRepo.CheckLikes(ctx, *viewerID, []int64{postID})
 		if err != nil {
@@ -102,19 +258,106 @@ func (s *PostService) GetByID(ctx context.Context, postID int64, viewerID *int64
 		} else {
 			post.IsLiked = likeStatus[postID]
 		}
+
+		saveStatus, err := s.postRepo.CheckSaves(ctx, *viewerID, []int64{postID})
+		if err != nil {
+			log.Printf("[PostService] Failed to check save status: %v", err)
+		} else {
+			post.IsSaved = saveStatus[postID]
+		}
+
+		repostStatus, err := s.postRepo.CheckReposts(ctx, *viewerID, []int64{postID})
+		if err != nil {
+			log.Printf("[PostService] Failed to check repost status: %v", err)
+		} else {
+			post.IsReposted = repostStatus[postID]
+		}
 	}
 
 	return post, nil
 }
 
+// Update edits a published post (owner only): caption and/or settings.
+// Hashtags are re-extracted from the new caption.
+func (s *PostService) Update(ctx context.Context, postID, userID int64, req model.UpdatePostRequest) (*model.Post, error) {
+	existing, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.UserID != userID {
+		return nil, model.ErrNotPostOwner
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var newMentions []int64
+	if req.Caption != nil {
+		caption := req.Caption
+		if strings.TrimSpace(*caption) == "" {
+			caption = nil // Clearing the caption
+		}
+		hashtags, err := parseCaption(caption)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.postRepo.UpdateCaption(ctx, tx, postID, caption); err != nil {
+			return nil, err
+		}
+		if err := s.hashtagRepo.SetPostHashtags(ctx, tx, postID, hashtags); err != nil {
+			return nil, err
+		}
+		mentions, err := s.captionMentions(ctx, userID, caption)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.mentionRepo.ReplacePostMentions(ctx, tx, postID, mentions); err != nil {
+			return nil, err
+		}
+		newMentions = newMentionRecipients(existing.Mentions, mentions, userID)
+	}
+
+	if req.HideLikeCount != nil || req.CommentsDisabled != nil {
+		if err := s.postRepo.UpdateSettings(ctx, tx, postID, req.HideLikeCount, req.CommentsDisabled); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	// Only users newly mentioned by this edit are notified
+	publishMentionEvents(ctx, s.publisher, postID, nil, userID, newMentions)
+
+	return s.GetByID(ctx, postID, &userID)
+}
+
 // Delete soft-deletes a post and publishes an event to remove from feeds.
 func (s *PostService) Delete(ctx context.Context, postID, userID int64) error {
-	// Delete from DB (validates ownership)
-	err := s.postRepo.Delete(ctx, postID, userID)
+	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Delete from DB (validates ownership)
+	if err := s.postRepo.Delete(ctx, tx, postID, userID); err != nil {
 		return err
 	}
 
+	// Deleted posts no longer count towards hashtag pages
+	if err := s.hashtagRepo.DetachPost(ctx, tx, postID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
 	// Publish event for async removal from feeds
 	event := queue.NewPostDeletedEvent(postID, userID)
 	msgID, err := s.publisher.Publish(ctx, queue.StreamFeed, event)
@@ -128,7 +371,8 @@ func (s *PostService) Delete(ctx context.Context, postID, userID int64) error {
 }
 
 // GetUserPosts retrieves post thumbnails for a user's profile.
-func (s *PostService) GetUserPosts(ctx context.Context, userID int64, cursor *string, limit int) (*model.PostListResponse, error) {
+// Returns ErrPrivateAccount if the user is private and the viewer doesn't follow them.
+func (s *PostService) GetUserPosts(ctx context.Context, userID int64, viewerID *int64, cursor *string, limit int) (*model.PostListResponse, error) {
 	if limit <= 0 {
 		limit = 12 // Default for 3x4 grid
 	}
@@ -136,6 +380,10 @@ func (s *PostService) GetUserPosts(ctx context.Context, userID int64, cursor *st
 		limit = 36 // Max for reasonable page size
 	}
 
+	if err := s.checkCanViewProfile(ctx, userID, viewerID); err != nil {
+		return nil, err
+	}
+
 	thumbnails, nextCursor, err := s.postRepo.GetUserThumbnails(ctx, userID, cursor, limit)
 	if err != nil {
 		return nil, fmt.Errorf("get user thumbnails: %w", err)
@@ -159,12 +407,12 @@ func (s *PostService) GetUserPosts(ctx context.Context, userID int64, cursor *st
 
 // Like adds a like to a post. Uses transaction: insert like + increment counter.
 func (s *PostService) Like(ctx context.Context, postID, userID int64) error {
-	// Verify post exists first
-	exists, err := s.postRepo.Exists(ctx, postID)
+	// Verify post exists (and the user may see it) first
+	visible, err := s.postRepo.IsVisible(ctx, postID, userID)
 	if err != nil {
-		return fmt.Errorf("check post exists: %w", err)
+		return err
 	}
-	if !exists {
+	if !visible {
 		return model.ErrPostNotFound
 	}
 
@@ -230,8 +478,103 @@ func (s *PostService) Unlike(ctx context.Context, postID, userID int64) error {
 	return nil
 }
 
+// Repost shares someone else's post with the user's followers.
+// Posts of private accounts can't be reposted: the repost would reach non-followers.
+// Uses transaction: insert repost + increment counter, then publishes an event for fan-out.
+func (s *PostService) Repost(ctx context.Context, postID, userID int64) error {
+	visible, err := s.postRepo.IsVisible(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return model.ErrPostNotFound
+	}
+
+	authorID, err := s.postRepo.GetAuthorID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if authorID == userID {
+		return model.ErrCannotRepostOwn
+	}
+
+	author, err := s.userRepo.GetByID(ctx, authorID)
+	if err != nil {
+		return err
+	}
+	if author.IsPrivate {
+		return model.ErrCannotRepostPrivate
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	created, err := s.repostRepo.Create(ctx, tx, userID, postID)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return model.ErrAlreadyReposted
+	}
+
+	if err := s.postRepo.IncrementRepostCount(ctx, tx, postID, 1); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	log.Printf("[PostService] User %d reposted post %d", userID, postID)
+
+	// Publish event for async fan-out to the reposter's followers
+	event := queue.NewPostRepostedEvent(postID, authorID, userID)
+	if _, err := s.publisher.Publish(ctx, queue.StreamFeed, event); err != nil {
+		log.Printf("[PostService] Failed to publish PostReposted event: post=%d err=%v", postID, err)
+	}
+
+	return nil
+}
+
+// Unrepost undoes a repost. Uses transaction: delete repost + decrement counter.
+func (s *PostService) Unrepost(ctx context.Context, postID, userID int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.repostRepo.Delete(ctx, tx, userID, postID); err != nil {
+		return err
+	}
+
+	if err := s.postRepo.IncrementRepostCount(ctx, tx, postID, -1); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	log.Printf("[PostService] User %d unreposted post %d", userID, postID)
+
+	// Publish event for async removal from feeds the repost reached
+	authorID, err := s.postRepo.GetAuthorID(ctx, postID)
+	if err == nil {
+		event := queue.NewPostUnrepostedEvent(postID, authorID, userID)
+		if _, err := s.publisher.Publish(ctx, queue.StreamFeed, event); err != nil {
+			log.Printf("[PostService] Failed to publish PostUnreposted event: post=%d err=%v", postID, err)
+		}
+	}
+
+	return nil
+}
+
 // GetPostLikers returns paginated list of users who liked a post.
-func (s *PostService) GetPostLikers(ctx context.Context, postID int64, cursor *string, limit int) (*model.LikersListResponse, error) {
+func (s *PostService) GetPostLikers(ctx context.Context, postID, viewerID int64, cursor *string, limit int) (*model.LikersListResponse, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -239,16 +582,16 @@ func (s *PostService) GetPostLikers(ctx context.Context, postID int64, cursor *s
 		limit = 50
 	}
 
-	// Verify post exists
-	exists, err := s.postRepo.Exists(ctx, postID)
+	// Verify post exists and is visible to the viewer
+	visible, err := s.postRepo.IsVisible(ctx, postID, viewerID)
 	if err != nil {
-		return nil, fmt.Errorf("check post exists: %w", err)
+		return nil, err
 	}
-	if !exists {
+	if !visible {
 		return nil, model.ErrPostNotFound
 	}
 
-	users, nextCursor, err := s.postRepo.GetPostLikers(ctx, postID, cursor, limit)
+	users, nextCursor, err := s.postRepo.GetPostLikers(ctx, postID, viewerID, cursor, limit)
 	if err != nil {
 		return nil, fmt.Errorf("get post likers: %w", err)
 	}
@@ -267,3 +610,231 @@ func (s *PostService) GetPostLikers(ctx context.Context, postID int64, cursor *s
 	}, nil
 }
 
+// ReplaceMediaTags replaces all tags on one media item of a post (owner only).
+// Newly tagged users are notified; users that were already tagged on the post are not notified again.
+func (s *PostService) ReplaceMediaTags(ctx context.Context, postID, mediaID, userID int64, tags []model.MediaTagInput) (*model.Post, error) {
+	// GetAuthorID also finds deleted posts
+	exists, err := s.postRepo.Exists(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("check post exists: %w", err)
+	}
+	if !exists {
+		return nil, model.ErrPostNotFound
+	}
+
+	authorID, err := s.postRepo.GetAuthorID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if authorID != userID {
+		return nil, model.ErrNotPostOwner
+	}
+
+	mediaPostID, err := s.tagRepo.GetMediaPostID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if mediaPostID != postID {
+		return nil, model.ErrMediaNotFound
+	}
+
+	if err := validateMediaTags(tags); err != nil {
+		return nil, err
+	}
+	newIDs := make([]int64, len(tags))
+	for i, t := range tags {
+		newIDs[i] = t.UserID
+	}
+	if err := s.checkUsersExist(ctx, userID, newIDs); err != nil {
+		return nil, err
+	}
+
+	previous, err := s.tagRepo.GetTaggedUserIDsByPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.tagRepo.ReplaceMediaTags(ctx, tx, mediaID, tags); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	alreadyTagged := make(map[int64]bool, len(previous))
+	for _, id := range previous {
+		alreadyTagged[id] = true
+	}
+	var added []int64
+	for _, id := range newIDs {
+		if !alreadyTagged[id] {
+			added = append(added, id)
+		}
+	}
+	s.publishTagEvents(ctx, postID, userID, added)
+
+	return s.GetByID(ctx, postID, &userID)
+}
+
+// RemoveOwnTag removes the current user's tags from a post.
+func (s *PostService) RemoveOwnTag(ctx context.Context, postID, userID int64) error {
+	exists, err := s.postRepo.Exists(ctx, postID)
+	if err != nil {
+		return fmt.Errorf("check post exists: %w", err)
+	}
+	if !exists {
+		return model.ErrPostNotFound
+	}
+
+	return s.tagRepo.DeleteUserTagsOnPost(ctx, postID, userID)
+}
+
+// GetTaggedPosts retrieves thumbnails of posts a user is tagged in (profile "tagged" tab).
+// Like the posts tab, the tab of a private account is only shown to its followers.
+func (s *PostService) GetTaggedPosts(ctx context.Context, userID int64, viewerID *int64, cursor *string, limit int) (*model.PostListResponse, error) {
+	if limit <= 0 {
+		limit = 12
+	}
+	if limit > 36 {
+		limit = 36
+	}
+
+	if err := s.checkCanViewProfile(ctx, userID, viewerID); err != nil {
+		return nil, err
+	}
+
+	thumbnails, nextCursor, err := s.tagRepo.GetTaggedThumbnails(ctx, userID, viewerOrAnonymous(viewerID), cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get tagged thumbnails: %w", err)
+	}
+	if thumbnails == nil {
+		thumbnails = []model.PostThumbnail{}
+	}
+
+	return &model.PostListResponse{
+		Posts:      thumbnails,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != nil,
+	}, nil
+}
+
+func (s *PostService) checkCanViewProfile(ctx context.Context, userID int64, viewerID *int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkNotBlocked(ctx, s.blockRepo, userID, viewerID); err != nil {
+		return err
+	}
+	return checkCanViewProfile(ctx, s.followRepo, user, viewerID)
+}
+
+// checkUsersExist returns ErrTaggedUserMissing if any of the IDs isn't a user, or is a user
+// blocked by or blocking the author.
+func (s *PostService) checkUsersExist(ctx context.Context, authorID int64, userIDs []int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	unique := make(map[int64]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		unique[id] = struct{}{}
+	}
+	ids := make([]int64, 0, len(unique))
+	for id := range unique {
+		ids = append(ids, id)
+	}
+
+	users, err := s.userRepo.GetSummariesByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("get tagged users: %w", err)
+	}
+	if len(users) != len(ids) {
+		return model.ErrTaggedUserMissing
+	}
+
+	blockedIDs, err := s.blockRepo.GetBlockedIDs(ctx, authorID)
+	if err != nil {
+		return err
+	}
+	for _, id := range blockedIDs {
+		if _, ok := unique[id]; ok {
+			return model.ErrTaggedUserMissing
+		}
+	}
+	return nil
+}
+
+// publishTagEvents notifies tagged users (best-effort, after commit).
+// A user tagged on several media items of the same post gets a single notification.
+func (s *PostService) publishTagEvents(ctx context.Context, postID, actorID int64, taggedIDs []int64) {
+	if s.publisher == nil {
+		return
+	}
+
+	seen := make(map[int64]bool, len(taggedIDs))
+	for _, id := range taggedIDs {
+		if id == actorID || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		event := queue.NewUserTaggedEvent(postID, actorID, id)
+		if _, err := s.publisher.Publish(ctx, queue.StreamFeed, event); err != nil {
+			log.Printf("[PostService] Failed to publish UserTagged event: post=%d user=%d err=%v", postID, id, err)
+		}
+	}
+}
+
+// validateMediaTags checks tag count, coordinates and duplicates for a single media item.
+func validateMediaTags(tags []model.MediaTagInput) error {
+	if len(tags) > model.MaxTagsPerMedia {
+		return model.ErrTooManyTags
+	}
+
+	seen := make(map[int64]bool, len(tags))
+	for _, t := range tags {
+		if t.X < 0 || t.X > 1 || t.Y < 0 || t.Y > 1 {
+			return model.ErrInvalidTagPoint
+		}
+		if seen[t.UserID] {
+			return model.ErrDuplicateTag
+		}
+		seen[t.UserID] = true
+	}
+	return nil
+}
+
+// captionMentions resolves the @mentions in an optional caption.
+func (s *PostService) captionMentions(ctx context.Context, authorID int64, caption *string) ([]model.Mention, error) {
+	if caption == nil {
+		return []model.Mention{}, nil
+	}
+	return resolveMentions(ctx, s.userRepo, s.blockRepo, authorID, *caption)
+}
+
+// parseCaption validates a caption and returns its normalized hashtags.
+func parseCaption(caption *string) ([]string, error) {
+	if caption == nil {
+		return []string{}, nil
+	}
+	if len(*caption) > model.MaxPostCaptionLength {
+		return nil, model.ErrCaptionTooLong
+	}
+
+	hashtags := extractHashtags(*caption, model.MaxHashtagLength)
+	if len(hashtags) > model.MaxHashtagsPerPost {
+		return nil, model.ErrTooManyHashtags
+	}
+	if hashtags == nil {
+		hashtags = []string{}
+	}
+	return hashtags, nil
+}
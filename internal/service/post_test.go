@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
+)
+
+func TestValidateMediaTags(t *testing.T) {
+	tooMany := make([]model.MediaTagInput, model.MaxTagsPerMedia+1)
+	for i := range tooMany {
+		tooMany[i] = model.MediaTagInput{UserID: int64(i + 1), X: 0.5, Y: 0.5}
+	}
+
+	tests := []struct {
+		name    string
+		tags    []model.MediaTagInput
+		wantErr error
+	}{
+		{name: "no tags", tags: nil},
+		{name: "corners are valid", tags: []model.MediaTagInput{{UserID: 1, X: 0, Y: 0}, {UserID: 2, X: 1, Y: 1}}},
+		{name: "x out of range", tags: []model.MediaTagInput{{UserID: 1, X: 1.2, Y: 0.5}}, wantErr: model.ErrInvalidTagPoint},
+		{name: "negative y", tags: []model.MediaTagInput{{UserID: 1, X: 0.5, Y: -0.1}}, wantErr: model.ErrInvalidTagPoint},
+		{name: "duplicate user", tags: []model.MediaTagInput{{UserID: 1, X: 0.1, Y: 0.1}, {UserID: 1, X: 0.9, Y: 0.9}}, wantErr: model.ErrDuplicateTag},
+		{name: "too many", tags: tooMany, wantErr: model.ErrTooManyTags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMediaTags(tt.tags)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateMediaTags() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// mockMediaPostRepository serves a single post and records media metadata updates.
+type mockMediaPostRepository struct {
+	repository.PostRepository
+	post    *model.Post
+	updated []int64
+}
+
+func (m *mockMediaPostRepository) GetByID(ctx context.Context, postID int64) (*model.Post, error) {
+	if m.post == nil || m.post.ID != postID {
+		return nil, model.ErrPostNotFound
+	}
+	return m.post, nil
+}
+
+func (m *mockMediaPostRepository) UpdateMediaMetadata(ctx context.Context, mediaID int64, media *model.PostMediaInput) error {
+	m.updated = append(m.updated, mediaID)
+	return nil
+}
+
+func TestPostService_ProcessPostMedia(t *testing.T) {
+	thumbKey := "post-thumbnails/a.jpg"
+	color := "#336699"
+	// Missing binaries make probing fail fast, like a broken upload would
+	mediaService := &MediaService{ffprobePath: "/nonexistent/ffprobe", ffmpegPath: "/nonexistent/ffmpeg"}
+
+	tests := []struct {
+		name string
+		post *model.Post
+	}{
+		{"deleted post", nil},
+		{"processed images and videos are skipped", &model.Post{ID: 1, Media: []model.PostMedia{
+			{ID: 10, MediaType: model.MediaTypeImage, DominantColor: &color},
+			{ID: 11, MediaType: model.MediaTypeVideo, ThumbnailKey: &thumbKey},
+		}}},
+		{"failed processing stores nothing", &model.Post{ID: 1, Media: []model.PostMedia{
+			{ID: 12, MediaType: model.MediaTypeVideo, MediaURL: "https://cdn.example.com/posts/v.mp4"},
+			{ID: 13, MediaType: model.MediaTypeImage, MediaURL: "https://elsewhere.example.com/a.jpg"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postRepo := &mockMediaPostRepository{post: tt.post}
+			svc := NewPostService(postRepo, nil, nil, nil, nil, nil, nil, nil, mediaService, nil, nil)
+
+			if err := svc.ProcessPostMedia(context.Background(), 1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(postRepo.updated) != 0 {
+				t.Errorf("updated media %v, want none", postRepo.updated)
+			}
+		})
+	}
+}
+
+// mockDeletedPostRepository serves a soft-deleted post: GetAuthorID still finds it.
+type mockDeletedPostRepository struct {
+	repository.PostRepository
+	authorID int64
+}
+
+func (m *mockDeletedPostRepository) Exists(ctx context.Context, postID int64) (bool, error) {
+	return false, nil
+}
+
+func (m *mockDeletedPostRepository) GetAuthorID(ctx context.Context, postID int64) (int64, error) {
+	return m.authorID, nil
+}
+
+func TestPostService_ReplaceMediaTags_DeletedPost(t *testing.T) {
+	// A nil tag repository fails the test if the tags are touched
+	svc := NewPostService(&mockDeletedPostRepository{authorID: 1}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	_, err := svc.ReplaceMediaTags(context.Background(), 10, 100, 1, []model.MediaTagInput{{UserID: 2, X: 0.5, Y: 0.5}})
+	if !errors.Is(err, model.ErrPostNotFound) {
+		t.Errorf("error = %v, want ErrPostNotFound", err)
+	}
+}
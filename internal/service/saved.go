@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+
+	"iamstagram_22520060/internal/model"
+	"iamstagram_22520060/internal/repository"
+)
+
+// SavedService handles private bookmarks and collections.
+type SavedService struct {
+	savedRepo   repository.SavedRepository
+	postRepo    repository.PostRepository
+	feedService *FeedService // Saved lists render like the feed
+	db          *sqlx.DB
+}
+
+func NewSavedService(
+	savedRepo repository.SavedRepository,
+	postRepo repository.PostRepository,
+	feedService *FeedService,
+	db *sqlx.DB,
+) *SavedService {
+	return &SavedService{
+		savedRepo:   savedRepo,
+		postRepo:    postRepo,
+		feedService: feedService,
+		db:          db,
+	}
+}
+
+// Save bookmarks a post.
+func (s *SavedService) Save(ctx context.Context, postID, userID int64) error {
+	visible, err := s.postRepo.IsVisible(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return model.ErrPostNotFound
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	created, err := s.savedRepo.Save(ctx, tx, userID, postID)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return model.ErrAlreadySaved
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	log.Printf("[SavedService] User %d saved post %d", userID, postID)
+	return nil
+}
+
+// Unsave removes a bookmark. The post is also removed from all of the user's collections.
+func (s *SavedService) Unsave(ctx context.Context, postID, userID int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.savedRepo.Unsave(ctx, tx, userID, postID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	log.Printf("[SavedService] User %d unsaved post %d", userID, postID)
+	return nil
+}
+
+// GetSaved returns the user's saved posts, most recently saved first.
+func (s *SavedService) GetSaved(ctx context.Context, userID int64, cursor *string, limit int) (*model.FeedResponse, error) {
+	limit = clampSavedLimit(limit)
+
+	postIDs, nextCursor, err := s.savedRepo.GetSavedPostIDs(ctx, userID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildResponse(ctx, userID, postIDs, nextCursor)
+}
+
+// ListCollections returns the user's collections.
+func (s *SavedService) ListCollections(ctx context.Context, userID int64) (*model.CollectionListResponse, error) {
+	collections, err := s.savedRepo.GetCollections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if collections == nil {
+		collections = []model.Collection{}
+	}
+	return &model.CollectionListResponse{Collections: collections}, nil
+}
+
+// CreateCollection creates an empty named collection.
+func (s *SavedService) CreateCollection(ctx context.Context, userID int64, req model.CollectionRequest) (*model.Collection, error) {
+	name, err := validateCollectionName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.savedRepo.CountCollections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= model.MaxCollectionsPerUser {
+		return nil, model.ErrTooManyCollections
+	}
+
+	collection, err := s.savedRepo.CreateCollection(ctx, userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[SavedService] User %d created collection %d", userID, collection.ID)
+	return collection, nil
+}
+
+// RenameCollection renames one of the user's collections.
+func (s *SavedService) RenameCollection(ctx context.Context, collectionID, userID int64, req model.CollectionRequest) (*model.Collection, error) {
+	name, err := validateCollectionName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.savedRepo.RenameCollection(ctx, collectionID, userID, name); err != nil {
+		return nil, err
+	}
+
+	return s.savedRepo.GetCollection(ctx, collectionID, userID)
+}
+
+// DeleteCollection deletes one of the user's collections. Its posts stay saved.
+func (s *SavedService) DeleteCollection(ctx context.Context, collectionID, userID int64) error {
+	if err := s.savedRepo.DeleteCollection(ctx, collectionID, userID); err != nil {
+		return err
+	}
+
+	log.Printf("[SavedService] User %d deleted collection %d", userID, collectionID)
+	return nil
+}
+
+// GetCollectionPosts returns a collection with its posts, most recently added first.
+func (s *SavedService) GetCollectionPosts(ctx context.Context, collectionID, userID int64, cursor *string, limit int) (*model.CollectionPostsResponse, error) {
+	limit = clampSavedLimit(limit)
+
+	collection, err := s.savedRepo.GetCollection(ctx, collectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	postIDs, nextCursor, err := s.savedRepo.GetCollectionPostIDs(ctx, collectionID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.buildResponse(ctx, userID, postIDs, nextCursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CollectionPostsResponse{
+		Collection: *collection,
+		Posts:      page.Posts,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}, nil
+}
+
+// AddToCollection adds a post to one of the user's collections, saving it if needed.
+func (s *SavedService) AddToCollection(ctx context.Context, collectionID, postID, userID int64) error {
+	if _, err := s.savedRepo.GetCollection(ctx, collectionID, userID); err != nil {
+		return err
+	}
+
+	visible, err := s.postRepo.IsVisible(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return model.ErrPostNotFound
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// A post in a collection is always saved
+	if _, err := s.savedRepo.Save(ctx, tx, userID, postID); err != nil {
+		return err
+	}
+	if err := s.savedRepo.AddToCollection(ctx, tx, collectionID, postID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	log.Printf("[SavedService] User %d added post %d to collection %d", userID, postID, collectionID)
+	return nil
+}
+
+// RemoveFromCollection removes a post from one of the user's collections. The post stays saved.
+func (s *SavedService) RemoveFromCollection(ctx context.Context, collectionID, postID, userID int64) error {
+	if _, err := s.savedRepo.GetCollection(ctx, collectionID, userID); err != nil {
+		return err
+	}
+
+	return s.savedRepo.RemoveFromCollection(ctx, collectionID, postID)
+}
+
+// buildResponse hydrates a page of post IDs into the feed shape.
+func (s *SavedService) buildResponse(ctx context.Context, userID int64, postIDs []int64, nextCursor *string) (*model.FeedResponse, error) {
+	if len(postIDs) == 0 {
+		return &model.FeedResponse{Posts: []model.FeedPost{}}, nil
+	}
+
+	posts, err := s.feedService.HydratePosts(ctx, userID, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("hydrate posts: %w", err)
+	}
+
+	return &model.FeedResponse{
+		Posts:      posts,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != nil,
+	}, nil
+}
+
+func clampSavedLimit(limit int) int {
+	if limit <= 0 {
+		return FeedDefaultLimit
+	}
+	if limit > FeedMaxLimit {
+		return FeedMaxLimit
+	}
+	return limit
+}
+
+// validateCollectionName trims a collection name and checks its length.
+func validateCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", model.ErrCollectionNameRequired
+	}
+	if utf8.RuneCountInString(name) > model.MaxCollectionNameLength {
+		return "", model.ErrCollectionNameTooLong
+	}
+	return name, nil
+}
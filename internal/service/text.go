@@ -0,0 +1,96 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// textToken is a "#tag" or "@name" reference found in user text.
+// Offset and Length are in runes and cover the prefix character.
+type textToken struct {
+	Value  string // Text after the prefix, as written
+	Offset int
+	Length int
+}
+
+// scanTokens finds prefix-started tokens in text. A token starts at a prefix rune
+// that is at the beginning of the text or follows a rune that can't be part of a
+// token (so "a#b" and "mail@host" are ignored), and runs while isTokenRune holds.
+func scanTokens(text string, prefix rune, isTokenRune func(rune) bool) []textToken {
+	runes := []rune(text)
+	var tokens []textToken
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != prefix {
+			continue
+		}
+		if i > 0 && (isTokenRune(runes[i-1]) || runes[i-1] == prefix) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isTokenRune(runes[end]) {
+			end++
+		}
+		if end == i+1 {
+			continue
+		}
+
+		tokens = append(tokens, textToken{
+			Value:  string(runes[i+1 : end]),
+			Offset: i,
+			Length: end - i,
+		})
+		i = end - 1
+	}
+
+	return tokens
+}
+
+func isHashtagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_'
+}
+
+// extractHashtags returns the unique, normalized (lowercase) hashtags in text,
+// in order of first appearance. Purely numeric tags ("#2024") are not hashtags,
+// and tags longer than maxLen runes are ignored.
+func extractHashtags(text string, maxLen int) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, t := range scanTokens(text, '#', isHashtagRune) {
+		name := strings.ToLower(t.Value)
+		if len([]rune(name)) > maxLen || !strings.ContainsFunc(name, func(r rune) bool { return !unicode.IsDigit(r) }) {
+			continue
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func isUsernameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+// extractMentions returns the "@username" tokens in text, in order of appearance.
+// Trailing dots are sentence punctuation ("thanks @bob.") and are not part of the name.
+func extractMentions(text string) []textToken {
+	var mentions []textToken
+
+	for _, t := range scanTokens(text, '@', isUsernameRune) {
+		name := strings.TrimRight(t.Value, ".")
+		if name == "" {
+			continue
+		}
+		t.Length -= len([]rune(t.Value)) - len([]rune(name))
+		t.Value = name
+		mentions = append(mentions, t)
+	}
+
+	return mentions
+}
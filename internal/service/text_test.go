@@ -0,0 +1,66 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "", want: nil},
+		{name: "simple", text: "Sunset #beach #Summer", want: []string{"beach", "summer"}},
+		{name: "dedupe case-insensitive", text: "#Go #go #GO", want: []string{"go"}},
+		{name: "adjacent punctuation", text: "love it!#travel, (#food).", want: []string{"travel", "food"}},
+		{name: "inside word ignored", text: "issue#12 abc#def", want: nil},
+		{name: "numeric only ignored", text: "#2024 #2024recap", want: []string{"2024recap"}},
+		{name: "unicode letters", text: "#phởHàNội #café", want: []string{"phởhànội", "café"}},
+		{name: "double hash", text: "##double", want: nil},
+		{name: "lone hash", text: "# nothing", want: nil},
+		{name: "underscore", text: "#throw_back", want: []string{"throw_back"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractHashtags(tt.text, 100)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractHashtags(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractHashtagsMaxLength(t *testing.T) {
+	if got := extractHashtags("#abcdef #abc", 5); !reflect.DeepEqual(got, []string{"abc"}) {
+		t.Errorf("got %v, want [abc]", got)
+	}
+}
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []textToken
+	}{
+		{name: "empty", text: "", want: nil},
+		{name: "simple", text: "hi @alice", want: []textToken{{Value: "alice", Offset: 3, Length: 6}}},
+		{name: "dots and underscores", text: "@john.doe_1 ok", want: []textToken{{Value: "john.doe_1", Offset: 0, Length: 11}}},
+		{name: "trailing dot", text: "thanks @bob.", want: []textToken{{Value: "bob", Offset: 7, Length: 4}}},
+		{name: "email ignored", text: "mail me at bob@example.com", want: nil},
+		{name: "rune offsets", text: "Cảm ơn @an!", want: []textToken{{Value: "an", Offset: 7, Length: 3}}},
+		{name: "repeated", text: "@a @a", want: []textToken{{Value: "a", Offset: 0, Length: 2}, {Value: "a", Offset: 3, Length: 2}}},
+		{name: "only dots", text: "@... what", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractMentions(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractMentions(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"golang.org/x/crypto/bcrypt"
 
@@ -15,12 +18,14 @@ import (
 type UserService struct {
 	repo       repository.UserRepository
 	followRepo repository.FollowRepository
+	blockRepo  repository.BlockRepository
 }
 
-func NewUserService(repo repository.UserRepository, followRepo repository.FollowRepository) *UserService {
+func NewUserService(repo repository.UserRepository, followRepo repository.FollowRepository, blockRepo repository.BlockRepository) *UserService {
 	return &UserService{
 		repo:       repo,
 		followRepo: followRepo,
+		blockRepo:  blockRepo,
 	}
 }
 
@@ -34,10 +39,30 @@ func (s *UserService) Register(ctx context.Context, req *model.RegisterRequest)
 		return nil, fmt.Errorf("password is required")
 	}
 
+	if err := validateUsername(req.Username); err != nil {
+		return nil, err
+	}
+
 	if (req.AvatarURL == nil) != (req.AvatarKey == nil) {
 		return nil, fmt.Errorf("avatar_url and avatar_key must both be provided or both omitted")
 	}
 
+	var email *string
+	if req.Email != nil && strings.TrimSpace(*req.Email) != "" {
+		normalized, err := normalizeEmail(*req.Email)
+		if err != nil {
+			return nil, err
+		}
+		taken, err := s.repo.ExistsByEmail(ctx, normalized)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check email: %w", err)
+		}
+		if taken {
+			return nil, model.ErrEmailExists
+		}
+		email = &normalized
+	}
+
 	// Check if username already exists
 	exists, err := s.repo.ExistsByUsername(ctx, req.Username)
 	if err != nil {
@@ -47,6 +72,15 @@ func (s *UserService) Register(ctx context.Context, req *model.RegisterRequest)
 		return nil, model.ErrUsernameExists
 	}
 
+	// Recently released handles stay with their previous owner for a while
+	held, err := s.repo.IsUsernameHeld(ctx, req.Username, 0, time.Now().Add(-model.UsernameHoldPeriod))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check username: %w", err)
+	}
+	if held {
+		return nil, model.ErrUsernameExists
+	}
+
 	// Hash the password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -57,6 +91,7 @@ func (s *UserService) Register(ctx context.Context, req *model.RegisterRequest)
 	user := &model.User{
 		Username:       req.Username,
 		PasswordHashed: string(hashedPassword),
+		Email:          email,
 		IsNewUser:      true, // New users need onboarding
 		AvatarURL:      req.AvatarURL,
 		AvatarKey:      req.AvatarKey,
@@ -69,20 +104,34 @@ func (s *UserService) Register(ctx context.Context, req *model.RegisterRequest)
 
 	// Save to database
 	if err := s.repo.Create(ctx, user); err != nil {
+		if errors.Is(err, model.ErrEmailExists) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	return user, nil
 }
 
-// Login authenticates a user with username and password.
+// Login authenticates a user with username (or email) and password.
 func (s *UserService) Login(ctx context.Context, req *model.LoginRequest) (*model.User, error) {
-	// Get user by username
-	user, err := s.repo.GetByUsername(ctx, req.Username)
+	// Usernames can't contain '@', so anything with one is an email
+	var user *model.User
+	var err error
+	byEmail := strings.Contains(req.Username, "@")
+	if byEmail {
+		user, err = s.repo.GetByEmail(ctx, strings.TrimSpace(req.Username))
+	} else {
+		user, err = s.repo.GetByUsername(ctx, req.Username)
+	}
 	if err != nil {
 		// Don't reveal whether username exists or not
 		return nil, model.ErrInvalidCredentials
 	}
+	// Anyone can type an address into their profile; only a verified one identifies the account
+	if byEmail && !user.EmailVerified {
+		return nil, model.ErrInvalidCredentials
+	}
 
 	// Compare password with hash
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHashed), []byte(req.Password))
@@ -90,9 +139,66 @@ func (s *UserService) Login(ctx context.Context, req *model.LoginRequest) (*mode
 		return nil, model.ErrInvalidCredentials
 	}
 	
+	// Logging in during the grace period restores an account pending deletion
+	if user.DeletionScheduledAt != nil {
+		if err := s.repo.CancelDeletion(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		user.DeletionScheduledAt = nil
+	}
+
 	return user, nil
 }
 
+// ScheduleDeletion verifies the password and schedules the account for deletion after
+// AccountDeletionGracePeriod. Asking again keeps the original date.
+// Signing out every session is up to the caller (see AuthHandler.DeleteAccount).
+func (s *UserService) ScheduleDeletion(ctx context.Context, userID int64, password string) (time.Time, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHashed), []byte(password)); err != nil {
+		return time.Time{}, model.ErrIncorrectPassword
+	}
+	if user.DeletionScheduledAt != nil {
+		return *user.DeletionScheduledAt, nil
+	}
+
+	at := time.Now().Add(model.AccountDeletionGracePeriod)
+	if err := s.repo.ScheduleDeletion(ctx, userID, at); err != nil {
+		return time.Time{}, err
+	}
+	return at, nil
+}
+
+// ChangePassword verifies the current password and stores a bcrypt hash of the new one.
+// Revoking sessions is up to the caller (see AuthHandler.ChangePassword).
+func (s *UserService) ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string) error {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHashed), []byte(currentPassword)); err != nil {
+		return model.ErrIncorrectPassword
+	}
+	if newPassword == currentPassword {
+		return model.ErrPasswordUnchanged
+	}
+	if err := validatePassword(newPassword, user.Username); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return s.repo.UpdatePassword(ctx, userID, string(hashedPassword))
+}
+
 // CompleteOnboarding marks the user as having completed the onboarding process.
 func (s *UserService) CompleteOnboarding(ctx context.Context, userID int64) error {
 	// We could add validation here (e.g., check if user follows > 0 people),
@@ -103,6 +209,131 @@ func (s *UserService) CompleteOnboarding(ctx context.Context, userID int64) erro
 	return nil
 }
 
+// UpdateProfile applies a partial profile update and returns the updated user.
+// There is no cache of user summaries (feeds cache post IDs only), so nothing needs invalidating.
+func (s *UserService) UpdateProfile(ctx context.Context, userID int64, req *model.UpdateProfileRequest) (*model.User, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Accounts created without a display name keep NULL until one is set
+	displayName := user.DisplayName
+	if req.DisplayName != nil {
+		trimmed := strings.TrimSpace(*req.DisplayName)
+		if trimmed == "" {
+			return nil, model.ErrDisplayNameRequired
+		}
+		if utf8.RuneCountInString(trimmed) > model.MaxDisplayNameLength {
+			return nil, model.ErrDisplayNameTooLong
+		}
+		displayName = &trimmed
+	}
+
+	bio := user.Bio
+	if req.Bio != nil {
+		trimmed := strings.TrimSpace(*req.Bio)
+		if utf8.RuneCountInString(trimmed) > model.MaxBioLength {
+			return nil, model.ErrBioTooLong
+		}
+		bio = nil
+		if trimmed != "" {
+			bio = &trimmed
+		}
+	}
+
+	if err := s.repo.UpdateProfile(ctx, userID, displayName, bio); err != nil {
+		return nil, err
+	}
+
+	user.DisplayName = displayName
+	user.Bio = bio
+	return user, nil
+}
+
+// UpdateAvatar points the user's avatar at a freshly uploaded object.
+// Returns the previous avatar key so the caller can delete the old object.
+func (s *UserService) UpdateAvatar(ctx context.Context, userID int64, avatarURL, avatarKey string) (string, error) {
+	oldKey, err := s.repo.UpdateAvatar(ctx, userID, avatarURL, avatarKey)
+	if err != nil {
+		return "", err
+	}
+	return oldKey, nil
+}
+
+// ChangeUsername renames the user. The old handle is kept in username_history: it keeps
+// resolving to this user and can't be taken by anyone else during model.UsernameHoldPeriod.
+func (s *UserService) ChangeUsername(ctx context.Context, userID int64, username string) (*model.User, error) {
+	username = strings.TrimSpace(username)
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Username == username {
+		return nil, model.ErrUsernameUnchanged
+	}
+
+	now := time.Now()
+	changes, err := s.repo.CountUsernameChangesSince(ctx, userID, now.Add(-model.UsernameChangeWindow))
+	if err != nil {
+		return nil, err
+	}
+	if changes >= model.UsernameChangeLimit {
+		return nil, model.ErrUsernameChangeLimit
+	}
+
+	exists, err := s.repo.ExistsByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check username: %w", err)
+	}
+	// A case-only change matches the user's own current name
+	if exists && !strings.EqualFold(user.Username, username) {
+		return nil, model.ErrUsernameExists
+	}
+
+	// The user may take back their own old handle, but not someone else's
+	held, err := s.repo.IsUsernameHeld(ctx, username, userID, now.Add(-model.UsernameHoldPeriod))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check username: %w", err)
+	}
+	if held {
+		return nil, model.ErrUsernameExists
+	}
+
+	if err := s.repo.ChangeUsername(ctx, userID, username); err != nil {
+		return nil, err
+	}
+
+	user.Username = username
+	return user, nil
+}
+
+// GetProfileByUsername resolves a handle to a profile. Recently changed handles resolve to
+// their current owner with Redirected set so clients can update links.
+func (s *UserService) GetProfileByUsername(ctx context.Context, username string, viewerID *int64) (*model.UsernameLookupResponse, error) {
+	redirected := false
+	user, err := s.repo.GetByUsername(ctx, username)
+	if errors.Is(err, model.ErrUserNotFound) {
+		user, err = s.repo.GetByPreviousUsername(ctx, username, time.Now().Add(-model.UsernameHoldPeriod))
+		redirected = true
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNotBlocked(ctx, s.blockRepo, user.ID, viewerID); err != nil {
+		return nil, err
+	}
+
+	return &model.UsernameLookupResponse{
+		ProfileResponse: s.buildProfile(ctx, user, viewerID),
+		Redirected:      redirected,
+	}, nil
+}
+
 // GetByID retrieves a user by ID.
 func (s *UserService) GetByID(ctx context.Context, id int64) (*model.User, error) {
 	user, err := s.repo.GetByID(ctx, id)
@@ -137,20 +368,43 @@ func (s *UserService) GetProfile(ctx context.Context, userID int64, viewerID *in
 	if err != nil {
 		return nil, err
 	}
+	if err := checkNotBlocked(ctx, s.blockRepo, userID, viewerID); err != nil {
+		return nil, err
+	}
+
+	return s.buildProfile(ctx, user, viewerID), nil
+}
+
+// buildProfile adds the viewer's follow status to a user and hides private fields from other viewers.
+func (s *UserService) buildProfile(ctx context.Context, user *model.User, viewerID *int64) *model.ProfileResponse {
+	// Email and deletion status are private to the account owner
+	if viewerID == nil || *viewerID != user.ID {
+		user.Email = nil
+		user.EmailVerified = false
+		user.DeletionScheduledAt = nil
+	}
 
 	profile := &model.ProfileResponse{
 		User:        user,
 		IsFollowing: false,
 	}
 
-	if viewerID != nil && *viewerID != userID {
-		isFollowing, err := s.followRepo.Exists(ctx, *viewerID, userID)
+	if viewerID != nil && *viewerID != user.ID {
+		isFollowing, err := s.followRepo.Exists(ctx, *viewerID, user.ID)
 		if err == nil {
 			profile.IsFollowing = isFollowing
 		}
+
+		// Lets clients show "Requested" instead of "Follow" on private profiles
+		if user.IsPrivate && !profile.IsFollowing {
+			isRequested, err := s.followRepo.RequestExists(ctx, *viewerID, user.ID)
+			if err == nil {
+				profile.IsRequested = isRequested
+			}
+		}
 	}
 
-	return profile, nil
+	return profile
 }
 
 // Search finds users by username with optional follow status enrichment.
@@ -158,7 +412,7 @@ func (s *UserService) GetProfile(ctx context.Context, userID int64, viewerID *in
 // follow relationships for multiple users. See GetFollowers for detailed explanation
 // of the two-query approach vs JOIN trade-offs.
 func (s *UserService) Search(ctx context.Context, query string, limit int, viewerID *int64) ([]model.UserSummary, error) {
-	users, err := s.repo.Search(ctx, query, limit)
+	users, err := s.repo.Search(ctx, query, viewerOrAnonymous(viewerID), limit)
 	if err != nil {
 		return nil, err
 	}
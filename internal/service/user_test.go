@@ -29,6 +29,22 @@ type mockUserRepository struct {
 	getByIDFn          func(ctx context.Context, id int64) (*model.User, error)
 	getByUsernameFn    func(ctx context.Context, username string) (*model.User, error)
 	existsByUsernameFn func(ctx context.Context, username string) (bool, error)
+	updateProfileFn    func(ctx context.Context, userID int64, displayName, bio *string) error
+	changeUsernameFn   func(ctx context.Context, userID int64, username string) error
+	usernameChanges    int             // Returned by CountUsernameChangesSince
+	heldUsernames      map[string]bool // Handles recently released by other users
+
+	updatedPasswordHash string // Set by UpdatePassword
+
+	getByEmailFn        func(ctx context.Context, email string) (*model.User, error)
+	markEmailVerifiedFn func(ctx context.Context, userID int64, email string) (bool, error)
+	takenEmails         map[string]bool // Returned by ExistsByEmail
+	updatedEmails       []*string       // Recorded by UpdateEmail
+
+	summaries []model.UserSummary // Looked up by GetSummariesByUsernames
+
+	scheduledDeletion *time.Time // Set by ScheduleDeletion
+	cancelledDeletion bool       // Set by CancelDeletion
 
 	// Track calls for assertions
 	createCalls []createCall
@@ -38,6 +54,10 @@ type createCall struct {
 	User *model.User
 }
 
+func equalStrPtr(a, b *string) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
 func strPtr(s string) *string {
 	return &s
 }
@@ -64,6 +84,29 @@ func (m *mockUserRepository) GetByUsername(ctx context.Context, username string)
 	return nil, model.ErrUserNotFound
 }
 
+func (m *mockUserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	if m.getByEmailFn != nil {
+		return m.getByEmailFn(ctx, email)
+	}
+	return nil, model.ErrUserNotFound
+}
+
+func (m *mockUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	return m.takenEmails[email], nil
+}
+
+func (m *mockUserRepository) UpdateEmail(ctx context.Context, userID int64, email *string) error {
+	m.updatedEmails = append(m.updatedEmails, email)
+	return nil
+}
+
+func (m *mockUserRepository) MarkEmailVerified(ctx context.Context, userID int64, email string) (bool, error) {
+	if m.markEmailVerifiedFn != nil {
+		return m.markEmailVerifiedFn(ctx, userID, email)
+	}
+	return true, nil
+}
+
 func (m *mockUserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
 	if m.existsByUsernameFn != nil {
 		return m.existsByUsernameFn(ctx, username)
@@ -71,7 +114,7 @@ func (m *mockUserRepository) ExistsByUsername(ctx context.Context, username stri
 	return false, nil
 }
 
-func (m *mockUserRepository) Search(ctx context.Context, query string, limit int) ([]model.UserSummary, error) {
+func (m *mockUserRepository) Search(ctx context.Context, query string, viewerID int64, limit int) ([]model.UserSummary, error) {
 	return nil, nil
 }
 
@@ -83,6 +126,79 @@ func (m *mockUserRepository) IncrementFollowingCount(ctx context.Context, tx *sq
 	return nil
 }
 
+func (m *mockUserRepository) SetIsNewUser(ctx context.Context, userID int64, isNew bool) error {
+	return nil
+}
+
+func (m *mockUserRepository) UpdateProfile(ctx context.Context, userID int64, displayName, bio *string) error {
+	if m.updateProfileFn != nil {
+		return m.updateProfileFn(ctx, userID, displayName, bio)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) UpdateAvatar(ctx context.Context, userID int64, avatarURL, avatarKey string) (string, error) {
+	return "", nil
+}
+
+func (m *mockUserRepository) UpdatePassword(ctx context.Context, userID int64, passwordHashed string) error {
+	m.updatedPasswordHash = passwordHashed
+	return nil
+}
+
+func (m *mockUserRepository) UpdatePasswordTx(ctx context.Context, tx *sqlx.Tx, userID int64, passwordHashed string) error {
+	return m.UpdatePassword(ctx, userID, passwordHashed)
+}
+
+func (m *mockUserRepository) SetPrivate(ctx context.Context, tx *sqlx.Tx, userID int64, private bool) error {
+	return nil
+}
+
+func (m *mockUserRepository) ScheduleDeletion(ctx context.Context, userID int64, at time.Time) error {
+	m.scheduledDeletion = &at
+	return nil
+}
+
+func (m *mockUserRepository) CancelDeletion(ctx context.Context, userID int64) error {
+	m.cancelledDeletion = true
+	return nil
+}
+
+func (m *mockUserRepository) ChangeUsername(ctx context.Context, userID int64, username string) error {
+	if m.changeUsernameFn != nil {
+		return m.changeUsernameFn(ctx, userID, username)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) CountUsernameChangesSince(ctx context.Context, userID int64, since time.Time) (int, error) {
+	return m.usernameChanges, nil
+}
+
+func (m *mockUserRepository) IsUsernameHeld(ctx context.Context, username string, exceptUserID int64, since time.Time) (bool, error) {
+	return m.heldUsernames[username], nil
+}
+
+func (m *mockUserRepository) GetByPreviousUsername(ctx context.Context, username string, since time.Time) (*model.User, error) {
+	return nil, model.ErrUserNotFound
+}
+
+func (m *mockUserRepository) GetSummariesByIDs(ctx context.Context, userIDs []int64) ([]model.UserSummary, error) {
+	return nil, nil
+}
+
+func (m *mockUserRepository) GetSummariesByUsernames(ctx context.Context, usernames []string) ([]model.UserSummary, error) {
+	var found []model.UserSummary
+	for _, u := range m.summaries {
+		for _, name := range usernames {
+			if u.Username == name {
+				found = append(found, u)
+			}
+		}
+	}
+	return found, nil
+}
+
 // =============================================================================
 // REGISTER TESTS
 // =============================================================================
@@ -101,7 +217,7 @@ func TestUserService_Register_Success(t *testing.T) {
 			return nil
 		},
 	}
-	svc := NewUserService(mockRepo, nil)
+	svc := NewUserService(mockRepo, nil, nil)
 
 	req := &model.RegisterRequest{
 		Username:    "testuser",
@@ -156,7 +272,7 @@ func TestUserService_Register_UsernameExists(t *testing.T) {
 			return true, nil // Username already exists
 		},
 	}
-	svc := NewUserService(mockRepo, nil)
+	svc := NewUserService(mockRepo, nil, nil)
 
 	req := &model.RegisterRequest{
 		Username: "existinguser",
@@ -187,7 +303,7 @@ func TestUserService_Register_CheckUsernameError(t *testing.T) {
 			return false, dbError // Database error
 		},
 	}
-	svc := NewUserService(mockRepo, nil)
+	svc := NewUserService(mockRepo, nil, nil)
 
 	req := &model.RegisterRequest{
 		Username: "testuser",
@@ -216,7 +332,7 @@ func TestUserService_Register_CreateError(t *testing.T) {
 			return dbError
 		},
 	}
-	svc := NewUserService(mockRepo, nil)
+	svc := NewUserService(mockRepo, nil, nil)
 
 	req := &model.RegisterRequest{
 		Username: "testuser",
@@ -240,7 +356,7 @@ func TestUserService_Register_WithoutDisplayName(t *testing.T) {
 			return nil
 		},
 	}
-	svc := NewUserService(mockRepo, nil)
+	svc := NewUserService(mockRepo, nil, nil)
 
 	req := &model.RegisterRequest{
 		Username: "testuser",
@@ -280,7 +396,7 @@ func TestUserService_Register_MissingFields(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mockUserRepository{}
-			svc := NewUserService(mockRepo, nil)
+			svc := NewUserService(mockRepo, nil, nil)
 
 			_, err := svc.Register(context.Background(), &tt.req)
 			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
@@ -307,7 +423,7 @@ func TestUserService_Register_AvatarProvided(t *testing.T) {
 		},
 	}
 
-	svc := NewUserService(mockRepo, nil)
+	svc := NewUserService(mockRepo, nil, nil)
 	req := &model.RegisterRequest{
 		Username:  "user1",
 		Password:  "password123",
@@ -348,7 +464,7 @@ func TestUserService_Register_AvatarMissingPair(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mockUserRepository{}
-			svc := NewUserService(mockRepo, nil)
+			svc := NewUserService(mockRepo, nil, nil)
 
 			_, err := svc.Register(context.Background(), &tt.req)
 			if err == nil || !strings.Contains(err.Error(), "avatar_url and avatar_key") {
@@ -371,7 +487,7 @@ func TestUserService_Register_NoAvatarProvided(t *testing.T) {
 		},
 	}
 
-	svc := NewUserService(mockRepo, nil)
+	svc := NewUserService(mockRepo, nil, nil)
 	req := &model.RegisterRequest{Username: "user2", Password: "password123"}
 
 	user, err := svc.Register(context.Background(), req)
@@ -384,10 +500,78 @@ func TestUserService_Register_NoAvatarProvided(t *testing.T) {
 	}
 }
 
+func TestUserService_Register_Email(t *testing.T) {
+	mockRepo := &mockUserRepository{takenEmails: map[string]bool{"taken@example.com": true}}
+	svc := NewUserService(mockRepo, nil, nil)
+
+	user, err := svc.Register(context.Background(), &model.RegisterRequest{
+		Username: "user3", Password: "password123", Email: strPtr(" User3@Example.com "),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Email == nil || *user.Email != "user3@example.com" || user.EmailVerified {
+		t.Errorf("email = %v verified=%v, want unverified user3@example.com", user.Email, user.EmailVerified)
+	}
+
+	_, err = svc.Register(context.Background(), &model.RegisterRequest{
+		Username: "user4", Password: "password123", Email: strPtr("Taken@example.com"),
+	})
+	if !errors.Is(err, model.ErrEmailExists) {
+		t.Errorf("error = %v, want ErrEmailExists", err)
+	}
+
+	_, err = svc.Register(context.Background(), &model.RegisterRequest{
+		Username: "user5", Password: "password123", Email: strPtr("not-an-email"),
+	})
+	if !errors.Is(err, model.ErrInvalidEmail) {
+		t.Errorf("error = %v, want ErrInvalidEmail", err)
+	}
+}
+
 // =============================================================================
 // LOGIN TESTS - Table-Driven (THE Go idiom)
 // =============================================================================
 
+func TestUserService_Login_ByEmail(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.MinCost)
+	var lookedUp string
+	mockRepo := &mockUserRepository{
+		getByEmailFn: func(ctx context.Context, email string) (*model.User, error) {
+			lookedUp = email
+			return &model.User{ID: 1, Username: "testuser", PasswordHashed: string(hash), Email: &email, EmailVerified: true}, nil
+		},
+		getByUsernameFn: func(ctx context.Context, username string) (*model.User, error) {
+			t.Errorf("GetByUsername called for %q", username)
+			return nil, model.ErrUserNotFound
+		},
+	}
+	svc := NewUserService(mockRepo, nil, nil)
+
+	user, err := svc.Login(context.Background(), &model.LoginRequest{Username: "john@example.com", Password: "correctpassword"})
+	if err != nil || user == nil {
+		t.Fatalf("login by email failed: %v", err)
+	}
+	if lookedUp != "john@example.com" {
+		t.Errorf("looked up %q", lookedUp)
+	}
+}
+
+func TestUserService_Login_ByUnverifiedEmail(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.MinCost)
+	mockRepo := &mockUserRepository{
+		getByEmailFn: func(ctx context.Context, email string) (*model.User, error) {
+			return &model.User{ID: 1, Username: "testuser", PasswordHashed: string(hash), Email: &email}, nil
+		},
+	}
+	svc := NewUserService(mockRepo, nil, nil)
+
+	_, err := svc.Login(context.Background(), &model.LoginRequest{Username: "john@example.com", Password: "correctpassword"})
+	if !errors.Is(err, model.ErrInvalidCredentials) {
+		t.Errorf("error = %v, want ErrInvalidCredentials", err)
+	}
+}
+
 func TestUserService_Login(t *testing.T) {
 	validPassword := "correctpassword"
 	validHash, _ := bcrypt.GenerateFromPassword([]byte(validPassword), bcrypt.MinCost)
@@ -453,7 +637,7 @@ func TestUserService_Login(t *testing.T) {
 			mockRepo := &mockUserRepository{
 				getByUsernameFn: tt.mockGetByUser,
 			}
-			svc := NewUserService(mockRepo, nil)
+			svc := NewUserService(mockRepo, nil, nil)
 
 			req := &model.LoginRequest{
 				Username: tt.username,
@@ -528,7 +712,7 @@ func TestUserService_GetByID(t *testing.T) {
 			mockRepo := &mockUserRepository{
 				getByIDFn: tt.mockGetFn,
 			}
-			svc := NewUserService(mockRepo, nil)
+			svc := NewUserService(mockRepo, nil, nil)
 
 			user, err := svc.GetByID(context.Background(), tt.userID)
 
@@ -547,3 +731,303 @@ func TestUserService_GetByID(t *testing.T) {
 		})
 	}
 }
+
+// =============================================================================
+// UPDATEPROFILE TESTS
+// =============================================================================
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	existing := &model.User{DisplayName: strPtr("Old Name"), Bio: strPtr("old bio")}
+
+	tests := []struct {
+		name            string
+		existing        *model.User
+		req             model.UpdateProfileRequest
+		wantErr         error
+		wantDisplayName *string
+		wantBio         *string
+	}{
+		{
+			name:            "only bio changes",
+			req:             model.UpdateProfileRequest{Bio: strPtr("  new bio  ")},
+			wantDisplayName: strPtr("Old Name"),
+			wantBio:         strPtr("new bio"),
+		},
+		{
+			name:            "empty bio clears it",
+			req:             model.UpdateProfileRequest{Bio: strPtr(" ")},
+			wantDisplayName: strPtr("Old Name"),
+			wantBio:         nil,
+		},
+		{
+			name:            "only bio changes keeps missing display name",
+			existing:        &model.User{},
+			req:             model.UpdateProfileRequest{Bio: strPtr("new bio")},
+			wantDisplayName: nil,
+			wantBio:         strPtr("new bio"),
+		},
+		{
+			name:            "display name trimmed",
+			req:             model.UpdateProfileRequest{DisplayName: strPtr(" New Name ")},
+			wantDisplayName: strPtr("New Name"),
+			wantBio:         strPtr("old bio"),
+		},
+		{
+			name:    "blank display name",
+			req:     model.UpdateProfileRequest{DisplayName: strPtr("   ")},
+			wantErr: model.ErrDisplayNameRequired,
+		},
+		{
+			name:    "display name too long",
+			req:     model.UpdateProfileRequest{DisplayName: strPtr(strings.Repeat("a", model.MaxDisplayNameLength+1))},
+			wantErr: model.ErrDisplayNameTooLong,
+		},
+		{
+			name:    "bio too long",
+			req:     model.UpdateProfileRequest{Bio: strPtr(strings.Repeat("b", model.MaxBioLength+1))},
+			wantErr: model.ErrBioTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var savedName, savedBio *string
+			updated := false
+			mockRepo := &mockUserRepository{
+				getByIDFn: func(ctx context.Context, id int64) (*model.User, error) {
+					user := *existing
+					if tt.existing != nil {
+						user = *tt.existing
+					}
+					user.ID = id
+					return &user, nil
+				},
+				updateProfileFn: func(ctx context.Context, userID int64, displayName, bio *string) error {
+					updated = true
+					savedName, savedBio = displayName, bio
+					return nil
+				},
+			}
+			svc := NewUserService(mockRepo, nil, nil)
+
+			user, err := svc.UpdateProfile(context.Background(), 1, &tt.req)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				if updated {
+					t.Error("repository should not be called on validation error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !equalStrPtr(savedName, tt.wantDisplayName) || !equalStrPtr(user.DisplayName, tt.wantDisplayName) {
+				t.Errorf("display name = %v, want %v", savedName, tt.wantDisplayName)
+			}
+			if !equalStrPtr(savedBio, tt.wantBio) {
+				t.Errorf("bio = %v, want %v", savedBio, tt.wantBio)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// CHANGEUSERNAME TESTS
+// =============================================================================
+
+func TestUserService_ChangeUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		changes  int
+		taken    bool
+		held     bool
+		wantErr  error
+	}{
+		{name: "success", username: "newname"},
+		{name: "invalid format", username: "bad name", wantErr: model.ErrInvalidUsername},
+		{name: "reserved word", username: "admin", wantErr: model.ErrUsernameReserved},
+		{name: "same as current", username: "oldname", wantErr: model.ErrUsernameUnchanged},
+		{name: "limit reached", username: "newname", changes: model.UsernameChangeLimit, wantErr: model.ErrUsernameChangeLimit},
+		{name: "taken by another user", username: "newname", taken: true, wantErr: model.ErrUsernameExists},
+		{name: "case-only change of own name", username: "OldName", taken: true},
+		{name: "recently released by another user", username: "newname", held: true, wantErr: model.ErrUsernameExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renamed := ""
+			mockRepo := &mockUserRepository{
+				getByIDFn: func(ctx context.Context, id int64) (*model.User, error) {
+					return &model.User{ID: id, Username: "oldname"}, nil
+				},
+				existsByUsernameFn: func(ctx context.Context, username string) (bool, error) {
+					return tt.taken, nil
+				},
+				changeUsernameFn: func(ctx context.Context, userID int64, username string) error {
+					renamed = username
+					return nil
+				},
+				usernameChanges: tt.changes,
+				heldUsernames:   map[string]bool{"newname": tt.held},
+			}
+			svc := NewUserService(mockRepo, nil, nil)
+
+			user, err := svc.ChangeUsername(context.Background(), 1, tt.username)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				if renamed != "" {
+					t.Error("ChangeUsername should not reach the repository on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if renamed != tt.username || user.Username != tt.username {
+				t.Errorf("renamed to %q (user %q), want %q", renamed, user.Username, tt.username)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// CHANGEPASSWORD TESTS
+// =============================================================================
+
+func TestUserService_ChangePassword(t *testing.T) {
+	currentHash, _ := bcrypt.GenerateFromPassword([]byte("old-secret-pw"), bcrypt.MinCost)
+
+	tests := []struct {
+		name    string
+		current string
+		newPass string
+		wantErr error
+	}{
+		{name: "success", current: "old-secret-pw", newPass: "new-secret-pw"},
+		{name: "wrong current password", current: "nope", newPass: "new-secret-pw", wantErr: model.ErrIncorrectPassword},
+		{name: "unchanged", current: "old-secret-pw", newPass: "old-secret-pw", wantErr: model.ErrPasswordUnchanged},
+		{name: "policy violation", current: "old-secret-pw", newPass: "password", wantErr: model.ErrPasswordTooCommon},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{
+				getByIDFn: func(ctx context.Context, id int64) (*model.User, error) {
+					return &model.User{ID: id, Username: "testuser", PasswordHashed: string(currentHash)}, nil
+				},
+			}
+			svc := NewUserService(mockRepo, nil, nil)
+
+			err := svc.ChangePassword(context.Background(), 1, tt.current, tt.newPass)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				if mockRepo.updatedPasswordHash != "" {
+					t.Error("password should not be updated on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if bcrypt.CompareHashAndPassword([]byte(mockRepo.updatedPasswordHash), []byte(tt.newPass)) != nil {
+				t.Error("stored hash does not match the new password")
+			}
+		})
+	}
+}
+
+func TestUserService_ScheduleDeletion(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret-pw"), bcrypt.MinCost)
+	existing := time.Now().Add(48 * time.Hour)
+
+	tests := []struct {
+		name      string
+		password  string
+		scheduled *time.Time
+		wantErr   error
+		wantNew   bool
+	}{
+		{name: "schedules after grace period", password: "secret-pw", wantNew: true},
+		{name: "wrong password", password: "nope", wantErr: model.ErrIncorrectPassword},
+		{name: "already scheduled keeps date", password: "secret-pw", scheduled: &existing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{
+				getByIDFn: func(ctx context.Context, id int64) (*model.User, error) {
+					return &model.User{ID: id, Username: "testuser", PasswordHashed: string(hash), DeletionScheduledAt: tt.scheduled}, nil
+				},
+			}
+			svc := NewUserService(mockRepo, nil, nil)
+
+			at, err := svc.ScheduleDeletion(context.Background(), 1, tt.password)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				if mockRepo.scheduledDeletion != nil {
+					t.Error("deletion should not be scheduled on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.wantNew {
+				if !at.Equal(*tt.scheduled) || mockRepo.scheduledDeletion != nil {
+					t.Errorf("got %v (stored %v), want existing date %v", at, mockRepo.scheduledDeletion, *tt.scheduled)
+				}
+				return
+			}
+			if mockRepo.scheduledDeletion == nil || !mockRepo.scheduledDeletion.Equal(at) {
+				t.Fatalf("stored %v, returned %v", mockRepo.scheduledDeletion, at)
+			}
+			if d := time.Until(at); d < model.AccountDeletionGracePeriod-time.Minute || d > model.AccountDeletionGracePeriod {
+				t.Errorf("scheduled %v from now, want %v", d, model.AccountDeletionGracePeriod)
+			}
+		})
+	}
+}
+
+func TestUserService_Login_CancelsDeletion(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret-pw"), bcrypt.MinCost)
+	scheduled := time.Now().Add(24 * time.Hour)
+	mockRepo := &mockUserRepository{
+		getByUsernameFn: func(ctx context.Context, username string) (*model.User, error) {
+			return &model.User{ID: 1, Username: username, PasswordHashed: string(hash), DeletionScheduledAt: &scheduled}, nil
+		},
+	}
+	svc := NewUserService(mockRepo, nil, nil)
+
+	// A failed login must not cancel anything
+	if _, err := svc.Login(context.Background(), &model.LoginRequest{Username: "testuser", Password: "nope"}); err == nil {
+		t.Fatal("expected login to fail")
+	}
+	if mockRepo.cancelledDeletion {
+		t.Fatal("deletion cancelled by a failed login")
+	}
+
+	user, err := svc.Login(context.Background(), &model.LoginRequest{Username: "testuser", Password: "secret-pw"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mockRepo.cancelledDeletion {
+		t.Error("deletion not cancelled")
+	}
+	if user.DeletionScheduledAt != nil {
+		t.Error("returned user still has a deletion date")
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"iamstagram_22520060/internal/model"
+)
+
+// reservedUsernames can't be registered or renamed to: they collide with app routes
+// or could be used to impersonate the service.
+var reservedUsernames = map[string]bool{
+	"admin": true, "administrator": true, "root": true, "system": true, "support": true,
+	"help": true, "staff": true, "moderator": true, "official": true, "security": true,
+	"iamstagram": true, "instagram": true, "api": true, "auth": true, "me": true,
+	"users": true, "posts": true, "feed": true, "explore": true, "hashtags": true,
+	"media": true, "notifications": true, "settings": true, "login": true,
+	"logout": true, "register": true, "signup": true, "null": true, "undefined": true,
+}
+
+// validateUsername enforces the username format shared by registration and renames:
+// model.MinUsernameLength-model.MaxUsernameLength characters of letters, digits, '.' and '_',
+// no leading, trailing or consecutive dots, and not a reserved word.
+func validateUsername(username string) error {
+	n := utf8.RuneCountInString(username)
+	if n < model.MinUsernameLength || n > model.MaxUsernameLength {
+		return model.ErrInvalidUsername
+	}
+
+	for _, r := range username {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !isDigit && r != '.' && r != '_' {
+			return model.ErrInvalidUsername
+		}
+	}
+
+	if strings.HasPrefix(username, ".") || strings.HasSuffix(username, ".") || strings.Contains(username, "..") {
+		return model.ErrInvalidUsername
+	}
+
+	if reservedUsernames[strings.ToLower(username)] {
+		return model.ErrUsernameReserved
+	}
+	return nil
+}
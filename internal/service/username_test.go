@@ -0,0 +1,37 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"iamstagram_22520060/internal/model"
+)
+
+func TestValidateUsername(t *testing.T) {
+	tests := []struct {
+		username string
+		want     error
+	}{
+		{"john_doe", nil},
+		{"john.doe99", nil},
+		{"JohnDoe", nil},
+		{"ab", model.ErrInvalidUsername},
+		{strings.Repeat("a", model.MaxUsernameLength+1), model.ErrInvalidUsername},
+		{"john doe", model.ErrInvalidUsername},
+		{"john-doe", model.ErrInvalidUsername},
+		{"nguyễn", model.ErrInvalidUsername},
+		{".john", model.ErrInvalidUsername},
+		{"john.", model.ErrInvalidUsername},
+		{"john..doe", model.ErrInvalidUsername},
+		{"admin", model.ErrUsernameReserved},
+		{"Settings", model.ErrUsernameReserved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			if got := validateUsername(tt.username); got != tt.want {
+				t.Errorf("validateUsername(%q) = %v, want %v", tt.username, got, tt.want)
+			}
+		})
+	}
+}
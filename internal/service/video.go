@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/google/uuid"
+
+	domain "iamstagram_22520060/internal/model"
+)
+
+const (
+	videoProbeTimeout      = 30 * time.Second
+	posterFrameMaxSize     = 1080
+	posterFrameOffsetSec   = 1.0
+	posterFrameCacheCtrl   = "public, max-age=31536000, immutable"
+	posterFrameJPEGQuality = 85
+)
+
+// videoInfo is the subset of ffprobe output we store in post_details.
+type videoInfo struct {
+	width      int
+	height     int
+	durationMs int
+}
+
+// processVideo fills in dimensions, duration and a poster-frame thumbnail for a video item.
+// Runs from the worker after the post is created. Failures are logged and leave the
+// fields empty: clients fall back to the video URL itself.
+func (s *MediaService) processVideo(ctx context.Context, m *domain.PostMediaInput) {
+	info, err := s.probeVideo(ctx, m.URL)
+	if err != nil {
+		log.Printf("[MediaService] Failed to probe video %s: %v", m.URL, err)
+	} else {
+		m.Width = &info.width
+		m.Height = &info.height
+		if info.durationMs > 0 {
+			m.DurationMs = &info.durationMs
+		}
+	}
+
+	offset := posterFrameOffsetSec
+	if info != nil && float64(info.durationMs)/1000 <= offset {
+		offset = 0
+	}
+
+	thumb, color, err := s.generatePosterFrame(ctx, m.URL, offset)
+	if err != nil {
+		log.Printf("[MediaService] Failed to generate poster frame for %s: %v", m.URL, err)
+		return
+	}
+	m.ThumbnailURL = &thumb.URL
+	m.ThumbnailKey = &thumb.Key
+	m.DominantColor = &color
+}
+
+// probeVideo runs ffprobe against the public URL. ffprobe uses range requests,
+// so only the container headers are downloaded.
+func (s *MediaService) probeVideo(ctx context.Context, url string) (*videoInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, videoProbeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.ffprobePath,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:stream_tags=rotate:stream_side_data=rotation:format=duration",
+		"-of", "json",
+		url,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe: %w: %s", err, stderr.String())
+	}
+
+	return parseFFprobeOutput(out)
+}
+
+// generatePosterFrame extracts a single frame at offsetSec, scales it down and uploads it as JPEG.
+// Also returns the frame's dominant color.
+func (s *MediaService) generatePosterFrame(ctx context.Context, url string, offsetSec float64) (*domain.UploadResult, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, videoProbeTimeout)
+	defer cancel()
+
+	// -ss before -i seeks on the input so only the needed segment is fetched
+	cmd := exec.CommandContext(ctx, s.ffmpegPath,
+		"-v", "error",
+		"-ss", strconv.FormatFloat(offsetSec, 'f', 3, 64),
+		"-i", url,
+		"-frames:v", "1",
+		"-f", "image2pipe",
+		"-vcodec", "mjpeg",
+		"pipe:1",
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	frame, err := cmd.Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("ffmpeg: %w: %s", err, stderr.String())
+	}
+	if len(frame) == 0 {
+		return nil, "", fmt.Errorf("ffmpeg returned no frame")
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(frame))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode poster frame: %w", err)
+	}
+	img = imaging.Fit(img, posterFrameMaxSize, posterFrameMaxSize, imaging.Lanczos)
+
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, imaging.JPEG, imaging.JPEGQuality(posterFrameJPEGQuality)); err != nil {
+		return nil, "", fmt.Errorf("failed to encode poster frame: %w", err)
+	}
+
+	key := fmt.Sprintf("%s/%s.jpg", domain.PostThumbnailFolder, uuid.NewString())
+	if err := s.putObject(ctx, key, buf.Bytes(), domain.ContentTypeJPEG, posterFrameCacheCtrl); err != nil {
+		return nil, "", err
+	}
+
+	return &domain.UploadResult{URL: fmt.Sprintf("%s/%s", s.publicURL, key), Key: key}, dominantColor(img), nil
+}
+
+// parseFFprobeOutput reads ffprobe's JSON output. Width and height are swapped
+// for videos recorded in portrait with a 90/270 degree rotation flag, so they
+// describe the frame as displayed.
+func parseFFprobeOutput(data []byte) (*videoInfo, error) {
+	var out struct {
+		Streams []struct {
+			Width        int               `json:"width"`
+			Height       int               `json:"height"`
+			Tags         map[string]string `json:"tags"`
+			SideDataList []struct {
+				Rotation float64 `json:"rotation"`
+			} `json:"side_data_list"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decode ffprobe output: %w", err)
+	}
+	if len(out.Streams) == 0 || out.Streams[0].Width <= 0 || out.Streams[0].Height <= 0 {
+		return nil, fmt.Errorf("no video stream found")
+	}
+
+	stream := out.Streams[0]
+	info := &videoInfo{width: stream.Width, height: stream.Height}
+
+	rotation := 0.0
+	if r, err := strconv.ParseFloat(stream.Tags["rotate"], 64); err == nil {
+		rotation = r
+	}
+	for _, sd := range stream.SideDataList {
+		if sd.Rotation != 0 {
+			rotation = sd.Rotation
+		}
+	}
+	if int(math.Abs(rotation))%180 == 90 {
+		info.width, info.height = info.height, info.width
+	}
+
+	if d, err := strconv.ParseFloat(out.Format.Duration, 64); err == nil && d > 0 {
+		info.durationMs = int(math.Round(d * 1000))
+	}
+
+	return info, nil
+}
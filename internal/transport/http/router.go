@@ -21,6 +21,11 @@ type RouterConfig struct {
 	MediaHandler        *handler.MediaHandler
 	CommentHandler      *handler.CommentHandler
 	NotificationHandler *handler.NotificationHandler
+	HashtagHandler      *handler.HashtagHandler
+	SavedHandler        *handler.SavedHandler
+	InsightsHandler     *handler.InsightsHandler
+	ExportHandler       *handler.ExportHandler
+	BlockHandler        *handler.BlockHandler
 	JWTSecret           string
 }
 
@@ -42,15 +47,28 @@ func NewRouter(cfg RouterConfig) chi.Router {
 		r.Post("/register", cfg.AuthHandler.Register)
 		r.Post("/login", cfg.AuthHandler.Login)
 		r.Post("/refresh", cfg.AuthHandler.Refresh)
+		r.Post("/password-reset", cfg.AuthHandler.RequestPasswordReset)
+		r.Post("/password-reset/confirm", cfg.AuthHandler.ConfirmPasswordReset)
+		r.Post("/verify-email", cfg.AuthHandler.VerifyEmail)
 	})
 
 	// Public user endpoints with optional authentication
 	r.Route("/users", func(r chi.Router) {
 		r.With(authmw.OptionalAuthMiddleware(cfg.JWTSecret)).Get("/search", cfg.UserHandler.Search)
+		r.With(authmw.OptionalAuthMiddleware(cfg.JWTSecret)).Get("/by-username/{username}", cfg.UserHandler.GetByUsername)
 		r.With(authmw.OptionalAuthMiddleware(cfg.JWTSecret)).Get("/{id}", cfg.UserHandler.GetProfile)
 		r.With(authmw.OptionalAuthMiddleware(cfg.JWTSecret)).Get("/{id}/followers", cfg.FollowHandler.GetFollowers)
 		r.With(authmw.OptionalAuthMiddleware(cfg.JWTSecret)).Get("/{id}/following", cfg.FollowHandler.GetFollowing)
 		r.With(authmw.OptionalAuthMiddleware(cfg.JWTSecret)).Get("/{id}/posts", cfg.PostHandler.GetUserPosts)
+		r.With(authmw.OptionalAuthMiddleware(cfg.JWTSecret)).Get("/{id}/tagged", cfg.PostHandler.GetTaggedPosts)
+	})
+
+	// Public hashtag endpoints with optional authentication
+	r.Route("/hashtags", func(r chi.Router) {
+		r.Use(authmw.OptionalAuthMiddleware(cfg.JWTSecret))
+		r.Get("/search", cfg.HashtagHandler.Search)
+		r.Get("/trending", cfg.HashtagHandler.GetTrending)
+		r.Get("/{tag}/posts", cfg.HashtagHandler.GetPosts)
 	})
 
 	// Public post endpoint with optional authentication
@@ -62,7 +80,33 @@ func NewRouter(cfg RouterConfig) chi.Router {
 
 		// Current user endpoints
 		r.Get("/me", cfg.AuthHandler.Me)
+		r.Patch("/me", cfg.UserHandler.UpdateProfile)
+		r.Delete("/me", cfg.AuthHandler.DeleteAccount)
+		r.Put("/me/avatar", cfg.UserHandler.UpdateAvatar)
+		r.Patch("/me/username", cfg.UserHandler.ChangeUsername)
+		r.Post("/me/password", cfg.AuthHandler.ChangePassword)
+		r.Put("/me/email", cfg.AuthHandler.UpdateEmail)
+		r.Post("/me/email/verification", cfg.AuthHandler.ResendVerification)
 		r.Patch("/me/onboarding", cfg.UserHandler.CompleteOnboarding)
+		r.Post("/me/export", cfg.ExportHandler.RequestExport)
+		r.Get("/me/export", cfg.ExportHandler.GetExport)
+		r.Put("/me/privacy", cfg.FollowHandler.UpdatePrivacy)
+		r.Get("/me/follow-requests", cfg.FollowHandler.GetRequests)
+		r.Post("/me/follow-requests/{id}/approve", cfg.FollowHandler.ApproveRequest)
+		r.Delete("/me/follow-requests/{id}", cfg.FollowHandler.DeclineRequest)
+		r.Get("/me/blocks", cfg.BlockHandler.GetBlocked)
+
+		// Saved posts and collections (private to the current user)
+		r.Get("/me/saved", cfg.SavedHandler.GetSaved)
+		r.Get("/me/collections", cfg.SavedHandler.ListCollections)
+		r.Post("/me/collections", cfg.SavedHandler.CreateCollection)
+		r.Patch("/me/collections/{id}", cfg.SavedHandler.RenameCollection)
+		r.Delete("/me/collections/{id}", cfg.SavedHandler.DeleteCollection)
+		r.Get("/me/collections/{id}/posts", cfg.SavedHandler.GetCollectionPosts)
+		r.Post("/me/collections/{id}/posts/{postId}", cfg.SavedHandler.AddToCollection)
+		r.Delete("/me/collections/{id}/posts/{postId}", cfg.SavedHandler.RemoveFromCollection)
+		r.Get("/me/comment-filters", cfg.CommentHandler.GetFilters)
+		r.Put("/me/comment-filters", cfg.CommentHandler.UpdateFilters)
 
 		// Auth actions that require authentication
 		r.Post("/auth/logout", cfg.AuthHandler.Logout)
@@ -71,24 +115,51 @@ func NewRouter(cfg RouterConfig) chi.Router {
 		// Follow/unfollow actions require authentication
 		r.Post("/users/{id}/follow", cfg.FollowHandler.Follow)
 		r.Delete("/users/{id}/follow", cfg.FollowHandler.Unfollow)
+		r.Post("/users/{id}/block", cfg.BlockHandler.Block)
+		r.Delete("/users/{id}/block", cfg.BlockHandler.Unblock)
 
 		// Feed endpoint
 		r.Get("/feed", cfg.FeedHandler.GetFeed)
 
 		// Post endpoints
 		r.Post("/posts", cfg.PostHandler.Create)
+		r.Patch("/posts/{id}", cfg.PostHandler.Update)
 		r.Delete("/posts/{id}", cfg.PostHandler.Delete)
 
+		// Tag endpoints
+		r.Put("/posts/{id}/media/{mediaId}/tags", cfg.PostHandler.UpdateMediaTags)
+		r.Delete("/posts/{id}/tags/me", cfg.PostHandler.RemoveMyTag)
+
 		// Like endpoints
 		r.Post("/posts/{id}/likes", cfg.PostHandler.Like)
 		r.Delete("/posts/{id}/likes", cfg.PostHandler.Unlike)
 		r.Get("/posts/{id}/likes", cfg.PostHandler.GetLikes)
 
+		// Save endpoints
+		r.Post("/posts/{id}/save", cfg.SavedHandler.Save)
+		r.Delete("/posts/{id}/save", cfg.SavedHandler.Unsave)
+
+		// Repost endpoints
+		r.Post("/posts/{id}/repost", cfg.PostHandler.Repost)
+		r.Delete("/posts/{id}/repost", cfg.PostHandler.Unrepost)
+
+		// Insights endpoints
+		r.Post("/posts/impressions", cfg.InsightsHandler.Ingest)
+		r.Get("/posts/{id}/insights", cfg.InsightsHandler.GetPostInsights)
+
 		// Comment endpoints
 		r.Post("/posts/{id}/comments", cfg.CommentHandler.Create)
 		r.Patch("/posts/{id}/comments/{commentId}", cfg.CommentHandler.Update)
 		r.Delete("/posts/{id}/comments/{commentId}", cfg.CommentHandler.Delete)
+		r.Get("/posts/{id}/comments/{commentId}/edits", cfg.CommentHandler.EditHistory)
 		r.Get("/posts/{id}/comments", cfg.CommentHandler.List)
+		r.Get("/posts/{id}/comments/{commentId}/replies", cfg.CommentHandler.ListReplies)
+		r.Get("/posts/{id}/comments/hidden", cfg.CommentHandler.ListHidden)
+		r.Post("/posts/{id}/comments/{commentId}/unhide", cfg.CommentHandler.Unhide)
+		r.Post("/posts/{id}/comments/{commentId}/likes", cfg.CommentHandler.Like)
+		r.Delete("/posts/{id}/comments/{commentId}/likes", cfg.CommentHandler.Unlike)
+		r.Post("/posts/{id}/comments/{commentId}/pin", cfg.CommentHandler.Pin)
+		r.Delete("/posts/{id}/comments/{commentId}/pin", cfg.CommentHandler.Unpin)
 
 		// Media endpoints (direct-to-R2 uploads)
 		r.Post("/media/posts/presign", cfg.MediaHandler.PresignPostUpload)
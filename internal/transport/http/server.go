@@ -8,11 +8,13 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"iamstagram_22520060/internal/cache"
 	"iamstagram_22520060/internal/config"
 	"iamstagram_22520060/internal/database"
 	"iamstagram_22520060/internal/handler"
+	"iamstagram_22520060/internal/mail"
 	"iamstagram_22520060/internal/queue"
 	iredis "iamstagram_22520060/internal/redis"
 	"iamstagram_22520060/internal/repository"
@@ -48,6 +50,8 @@ func Run() error {
 
 	// Create Redis components
 	feedCache := cache.NewFeedCache(redisClient.Client)
+	trendingCache := cache.NewTrendingCache(redisClient.Client)
+	insightsCache := cache.NewInsightsCache(redisClient.Client)
 	publisher := queue.NewPublisher(redisClient.Client)
 	consumer := queue.NewConsumer(redisClient.Client)
 
@@ -59,45 +63,116 @@ func Run() error {
 	commentRepo := repository.NewCommentRepository(db)
 	notifRepo := repository.NewNotificationRepository(db)
 	deviceTokenRepo := repository.NewDeviceTokenRepository(db)
+	mediaRepo := repository.NewMediaRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	hashtagRepo := repository.NewHashtagRepository(db)
+	mentionRepo := repository.NewMentionRepository(db)
+	savedRepo := repository.NewSavedRepository(db)
+	repostRepo := repository.NewRepostRepository(db)
+	insightsRepo := repository.NewInsightsRepository(db)
+	commentFilterRepo := repository.NewCommentFilterRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	emailVerificationRepo := repository.NewEmailVerificationRepository(db)
+	accountRepo := repository.NewAccountRepository(db)
+	exportRepo := repository.NewExportRepository(db)
+	blockRepo := repository.NewBlockRepository(db)
+
+	// Outgoing mail: SMTP when configured, otherwise log-only for local runs
+	var mailer mail.Mailer
+	if cfg.SMTPHost != "" {
+		mailer = mail.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+		log.Printf("Mailer: SMTP via %s:%s", cfg.SMTPHost, cfg.SMTPPort)
+	} else {
+		mailer = mail.NewLogMailer(cfg.MailFrom, cfg.MailDir)
+		log.Println("Mailer: SMTP_HOST not set, emails are only logged")
+	}
 
 	// Create services (with publisher for event-driven services)
-	userService := service.NewUserService(userRepo, followRepo)
+	userService := service.NewUserService(userRepo, followRepo, blockRepo)
 	authService := service.NewAuthService(refreshTokenRepo, cfg)
-	followService := service.NewFollowService(followRepo, userRepo, db, publisher)
-	mediaService, err := service.NewMediaService(ctx, cfg)
+	passwordResetService := service.NewPasswordResetService(userRepo, passwordResetRepo, refreshTokenRepo, mailer, db, cfg)
+	emailVerificationService := service.NewEmailVerificationService(userRepo, emailVerificationRepo, mailer, cfg)
+	followService := service.NewFollowService(followRepo, userRepo, blockRepo, repostRepo, db, publisher)
+	mediaService, err := service.NewMediaService(ctx, cfg, mediaRepo)
 	if err != nil {
 		return fmt.Errorf("failed to initialize media service: %w", err)
 	}
-	postService := service.NewPostService(postRepo, userRepo, publisher, db)
-	feedService := service.NewFeedService(feedCache, postRepo, followRepo, userRepo)
-	commentService := service.NewCommentService(commentRepo, postRepo, userRepo, db, publisher)
+	postService := service.NewPostService(postRepo, userRepo, followRepo, blockRepo, tagRepo, hashtagRepo, mentionRepo, repostRepo, mediaService, publisher, db)
+	feedService := service.NewFeedService(feedCache, postRepo, repostRepo, followRepo, userRepo, blockRepo)
+	commentService := service.NewCommentService(commentRepo, postRepo, userRepo, blockRepo, mentionRepo, commentFilterRepo, db, publisher, time.Duration(cfg.CommentEditWindow)*time.Second)
+	hashtagService := service.NewHashtagService(hashtagRepo, trendingCache)
+	savedService := service.NewSavedService(savedRepo, postRepo, feedService, db)
+	insightsService := service.NewInsightsService(insightsCache, insightsRepo, postRepo)
+	blockService := service.NewBlockService(blockRepo, followRepo, userRepo, db, publisher)
+	accountService := service.NewAccountService(accountRepo, followRepo, repostRepo, feedCache, mediaService, db, cfg)
 
 	// Initialize Expo Push client for push notifications
 	// Unlike FCM, Expo Push doesn't require any credentials!
 	expoPushClient := service.NewExpoPushClient()
 	log.Println("Expo Push client initialized - push notifications enabled")
-	notifService := service.NewNotificationService(notifRepo, deviceTokenRepo, userRepo, expoPushClient)
+	notifService := service.NewNotificationService(notifRepo, deviceTokenRepo, userRepo, blockRepo, expoPushClient)
+	exportService := service.NewExportService(exportRepo, userRepo, mediaService, notifService, publisher)
 
 	// Create worker components
 	workerHandler := worker.NewHandler(feedCache, followRepo, postRepo)
 	workerHandler.SetNotificationCreator(notifService) // Enable notification handling
+	workerHandler.SetRepostProvider(repostRepo)        // Enable repost fan-out
+	workerHandler.SetExportBuilder(exportService)      // Enable data exports
+	workerHandler.SetMediaProcessor(postService)       // Enable video processing
 	workerManager := worker.NewManager(consumer, workerHandler, worker.DefaultManagerConfig())
+	exportManager := worker.NewManager(consumer, workerHandler, worker.ExportManagerConfig())
+	mediaManager := worker.NewManager(consumer, workerHandler, worker.MediaManagerConfig())
 
 	// Start worker goroutines
 	if err := workerManager.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start worker manager: %w", err)
 	}
+	if err := exportManager.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start export worker manager: %w", err)
+	}
+	if err := mediaManager.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start media worker manager: %w", err)
+	}
 	log.Println("Worker manager started")
 
+	// Periodic jobs
+	scheduler := worker.NewScheduler()
+	scheduler.Register(worker.Job{
+		Name:     "trending_hashtags",
+		Interval: 10 * time.Minute,
+		Run:      hashtagService.RefreshTrending,
+	})
+	scheduler.Register(worker.Job{
+		Name:     "post_insights_rollup",
+		Interval: 5 * time.Minute,
+		Run:      insightsService.RollupInsights,
+	})
+	scheduler.Register(worker.Job{
+		Name:     "account_purge",
+		Interval: time.Hour,
+		Run:      accountService.PurgeDueAccounts,
+	})
+	scheduler.Register(worker.Job{
+		Name:     "data_export_cleanup",
+		Interval: time.Hour,
+		Run:      exportService.CleanupExpired,
+	})
+	scheduler.Start(ctx)
+
 	// Create handlers
-	authHandler := handler.NewAuthHandler(userService, authService, mediaService, cfg)
-	userHandler := handler.NewUserHandler(userService)
+	authHandler := handler.NewAuthHandler(userService, authService, passwordResetService, emailVerificationService, mediaService, cfg)
+	userHandler := handler.NewUserHandler(userService, mediaService, cfg)
 	followHandler := handler.NewFollowHandler(followService)
 	feedHandler := handler.NewFeedHandler(feedService)
 	postHandler := handler.NewPostHandler(postService)
 	mediaHandler := handler.NewMediaHandler(mediaService)
 	commentHandler := handler.NewCommentHandler(commentService)
 	notifHandler := handler.NewNotificationHandler(notifService)
+	hashtagHandler := handler.NewHashtagHandler(hashtagService)
+	savedHandler := handler.NewSavedHandler(savedService)
+	insightsHandler := handler.NewInsightsHandler(insightsService)
+	exportHandler := handler.NewExportHandler(exportService)
+	blockHandler := handler.NewBlockHandler(blockService)
 
 	// Create router with dependencies
 	router := NewRouter(RouterConfig{
@@ -109,6 +184,11 @@ func Run() error {
 		MediaHandler:        mediaHandler,
 		CommentHandler:      commentHandler,
 		NotificationHandler: notifHandler,
+		HashtagHandler:      hashtagHandler,
+		SavedHandler:        savedHandler,
+		InsightsHandler:     insightsHandler,
+		ExportHandler:       exportHandler,
+		BlockHandler:        blockHandler,
 		JWTSecret:           cfg.JWTSecret,
 	})
 
@@ -121,23 +201,49 @@ func Run() error {
 	log.Printf("  POST   /auth/logout           - Logout (protected)")
 	log.Printf("  POST   /auth/logout-all       - Logout all devices (protected)")
 	log.Printf("  GET    /me                    - Get current user (protected)")
+	log.Printf("  GET    /me/saved              - Get saved posts (protected)")
+	log.Printf("  GET    /me/collections        - List collections (protected)")
+	log.Printf("  POST   /me/collections        - Create collection (protected)")
+	log.Printf("  PATCH  /me/collections/:id    - Rename collection (protected)")
+	log.Printf("  DELETE /me/collections/:id    - Delete collection (protected)")
+	log.Printf("  GET    /me/collections/:id/posts - Get collection posts (protected)")
+	log.Printf("  POST   /me/collections/:id/posts/:postId - Add post to collection (protected)")
+	log.Printf("  DELETE /me/collections/:id/posts/:postId - Remove post from collection (protected)")
 	log.Printf("  GET    /users/search          - Search users (optional auth)")
 	log.Printf("  GET    /users/:id             - Get user profile (optional auth)")
 	log.Printf("  GET    /users/:id/followers   - Get user followers (optional auth)")
 	log.Printf("  GET    /users/:id/following   - Get users following (optional auth)")
 	log.Printf("  GET    /users/:id/posts       - Get user posts (optional auth)")
+	log.Printf("  GET    /users/:id/tagged      - Get posts user is tagged in (optional auth)")
 	log.Printf("  POST   /users/:id/follow      - Follow user (protected)")
 	log.Printf("  DELETE /users/:id/follow      - Unfollow user (protected)")
+	log.Printf("  POST   /users/:id/block       - Block user (protected)")
+	log.Printf("  DELETE /users/:id/block       - Unblock user (protected)")
+	log.Printf("  GET    /me/blocks             - List blocked users (protected)")
 	log.Printf("  GET    /feed                  - Get feed (protected)")
 	log.Printf("  POST   /posts                 - Create post (protected)")
 	log.Printf("  GET    /posts/:id             - Get post (optional auth)")
+	log.Printf("  PATCH  /posts/:id             - Edit post caption (protected)")
 	log.Printf("  DELETE /posts/:id             - Delete post (protected)")
+	log.Printf("  PUT    /posts/:id/media/:mediaId/tags - Replace media tags (protected)")
+	log.Printf("  DELETE /posts/:id/tags/me     - Remove own tag (protected)")
 	log.Printf("  POST   /posts/:id/likes       - Like post (protected)")
 	log.Printf("  DELETE /posts/:id/likes       - Unlike post (protected)")
 	log.Printf("  GET    /posts/:id/likes       - Get post likers (protected)")
+	log.Printf("  POST   /posts/:id/save        - Save post (protected)")
+	log.Printf("  DELETE /posts/:id/save        - Unsave post (protected)")
+	log.Printf("  POST   /posts/impressions     - Record impressions (protected)")
+	log.Printf("  GET    /posts/:id/insights    - Post insights, author only (protected)")
+	log.Printf("  POST   /posts/:id/repost      - Repost to followers (protected)")
+	log.Printf("  DELETE /posts/:id/repost      - Undo repost (protected)")
 	log.Printf("  POST   /posts/:id/comments    - Create comment (protected)")
 	log.Printf("  DELETE /posts/:id/comments/:id- Delete comment (protected)")
 	log.Printf("  GET    /posts/:id/comments    - Get comments (protected)")
+	log.Printf("  POST   /posts/:id/comments/:commentId/likes - Like comment (protected)")
+	log.Printf("  DELETE /posts/:id/comments/:commentId/likes - Unlike comment (protected)")
+	log.Printf("  GET    /hashtags/search       - Search hashtags (optional auth)")
+	log.Printf("  GET    /hashtags/trending     - Trending hashtags (optional auth)")
+	log.Printf("  GET    /hashtags/:tag/posts   - Get hashtag posts (optional auth)")
 
 	// Setup graceful shutdown
 	server := &stdhttp.Server{
@@ -162,8 +268,11 @@ func Run() error {
 	case <-shutdown:
 		log.Println("Shutting down gracefully...")
 
-		// Stop worker manager first
+		// Stop background jobs and worker manager first
+		scheduler.Stop()
 		workerManager.Stop()
+		exportManager.Stop()
+		mediaManager.Stop()
 
 		// Shutdown HTTP server
 		if err := server.Shutdown(ctx); err != nil {
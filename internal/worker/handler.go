@@ -25,6 +25,15 @@ type RecentPostsProvider interface {
 	GetRecentPostsByUser(ctx context.Context, userID int64, limit int) ([]cache.PostScore, error)
 }
 
+// RepostProvider defines the interface for fetching reposts.
+// Used to fan reposts in and out of feeds.
+type RepostProvider interface {
+	// GetReposterIDs returns everyone who reposted a post.
+	GetReposterIDs(ctx context.Context, postID int64) ([]int64, error)
+	// GetRecentRepostsByUser returns a user's recent reposts as (postID, repost timestamp) pairs.
+	GetRecentRepostsByUser(ctx context.Context, userID int64, limit int) ([]cache.PostScore, error)
+}
+
 // NotificationCreator defines the interface for creating notifications.
 // This allows the worker to create notifications without depending on the service directly.
 type NotificationCreator interface {
@@ -32,12 +41,32 @@ type NotificationCreator interface {
 	CreateNotification(ctx context.Context, userID, actorID int64, notifType string, postID, commentID *int64) error
 }
 
+// ExportBuilder builds a requested personal data export.
+type ExportBuilder interface {
+	// BuildExport builds and uploads the archive, then notifies its owner.
+	BuildExport(ctx context.Context, exportID int64) error
+}
+
+// MediaProcessor fills in metadata that is too slow to compute while creating a post.
+type MediaProcessor interface {
+	// ProcessPostMedia computes image dominant colors, probes videos and generates poster frames.
+	ProcessPostMedia(ctx context.Context, postID int64) error
+}
+
+const (
+	backfillLimit = 20  // How many recent posts (and reposts) to backfill on follow
+	removeLimit   = 100 // Higher limit on unfollow since we want to remove all their posts
+)
+
 // Handler processes feed events from the queue.
 type Handler struct {
 	feedCache        cache.FeedCache
 	followerProvider FollowerProvider
 	postsProvider    RecentPostsProvider
+	repostProvider   RepostProvider      // Can be nil if reposts not wired
 	notifCreator     NotificationCreator // Can be nil if notifications not wired
+	exportBuilder    ExportBuilder       // Can be nil if data exports not wired
+	mediaProcessor   MediaProcessor      // Can be nil if media processing not wired
 }
 
 // NewHandler creates a new event handler.
@@ -58,6 +87,21 @@ func (h *Handler) SetNotificationCreator(nc NotificationCreator) {
 	h.notifCreator = nc
 }
 
+// SetRepostProvider sets the repost provider (optional, for repost fan-out).
+func (h *Handler) SetRepostProvider(rp RepostProvider) {
+	h.repostProvider = rp
+}
+
+// SetExportBuilder sets the export builder (optional, for data export events).
+func (h *Handler) SetExportBuilder(eb ExportBuilder) {
+	h.exportBuilder = eb
+}
+
+// SetMediaProcessor sets the media processor (optional, for post media events).
+func (h *Handler) SetMediaProcessor(mp MediaProcessor) {
+	h.mediaProcessor = mp
+}
+
 // HandleEvent routes an event to the appropriate handler based on type.
 func (h *Handler) HandleEvent(ctx context.Context, event queue.FeedEvent) error {
 	startTime := time.Now()
@@ -72,11 +116,29 @@ func (h *Handler) HandleEvent(ctx context.Context, event queue.FeedEvent) error
 		err = h.handleUserFollowed(ctx, event)
 	case queue.EventUserUnfollowed:
 		err = h.handleUserUnfollowed(ctx, event)
+	case queue.EventPostReposted:
+		err = h.handlePostReposted(ctx, event)
+	case queue.EventPostUnreposted:
+		err = h.handlePostUnreposted(ctx, event)
 	// Notification events
 	case queue.EventPostLiked:
 		err = h.handlePostLiked(ctx, event)
 	case queue.EventPostCommented:
 		err = h.handlePostCommented(ctx, event)
+	case queue.EventUserTagged:
+		err = h.handleUserTagged(ctx, event)
+	case queue.EventUserMentioned:
+		err = h.handleUserMentioned(ctx, event)
+	case queue.EventCommentLiked:
+		err = h.handleCommentLiked(ctx, event)
+	case queue.EventCommentReplied:
+		err = h.handleCommentReplied(ctx, event)
+	// Account events
+	case queue.EventDataExportRequested:
+		err = h.handleDataExportRequested(ctx, event)
+	// Media events
+	case queue.EventPostMediaAdded:
+		err = h.handlePostMediaAdded(ctx, event)
 	default:
 		log.Printf("[Worker] Unknown event type: %s", event.Type)
 		return fmt.Errorf("unknown event type: %s", event.Type)
@@ -153,8 +215,11 @@ func (h *Handler) handlePostDeleted(ctx context.Context, event queue.FeedEvent)
 		log.Printf("[Worker] PostDeleted: failed to remove from author's own feed err=%v", err)
 	}
 
-	log.Printf("[Worker] PostDeleted DONE: post=%d fanout=%d failed=%d",
-		event.PostID, len(followers)+1, failCount)
+	// Remove from feeds the post reached through reposts
+	reposted := h.removeReposted(ctx, event.PostID)
+
+	log.Printf("[Worker] PostDeleted DONE: post=%d fanout=%d reposted=%d failed=%d",
+		event.PostID, len(followers)+1, reposted, failCount)
 
 	return nil
 }
@@ -164,17 +229,11 @@ func (h *Handler) handleUserFollowed(ctx context.Context, event queue.FeedEvent)
 	log.Printf("[Worker] UserFollowed: follower=%d followee=%d", event.FollowerID, event.FolloweeID)
 
 	// Fetch recent posts from the followee
-	const backfillLimit = 20 // How many recent posts to backfill
 	posts, err := h.postsProvider.GetRecentPostsByUser(ctx, event.FolloweeID, backfillLimit)
 	if err != nil {
 		return fmt.Errorf("get recent posts: %w", err)
 	}
 
-	if len(posts) == 0 {
-		log.Printf("[Worker] UserFollowed: followee=%d has no posts to backfill", event.FolloweeID)
-		return nil
-	}
-
 	log.Printf("[Worker] UserFollowed: backfilling %d posts to follower=%d", len(posts), event.FollowerID)
 
 	// Add each post to follower's feed
@@ -190,6 +249,8 @@ func (h *Handler) handleUserFollowed(ctx context.Context, event queue.FeedEvent)
 	log.Printf("[Worker] UserFollowed DONE: follower=%d backfilled=%d failed=%d",
 		event.FollowerID, len(posts), failCount)
 
+	h.backfillReposts(ctx, event.FollowerID, event.FolloweeID)
+
 	// Create follow notification for the followee
 	if h.notifCreator != nil {
 		err := h.notifCreator.CreateNotification(ctx, event.FolloweeID, event.FollowerID, "follow", nil, nil)
@@ -208,18 +269,11 @@ func (h *Handler) handleUserUnfollowed(ctx context.Context, event queue.FeedEven
 	log.Printf("[Worker] UserUnfollowed: follower=%d followee=%d", event.FollowerID, event.FolloweeID)
 
 	// Fetch posts from the followee that might be in the follower's feed
-	// We use a higher limit since we want to remove all their posts
-	const removeLimit = 100
 	posts, err := h.postsProvider.GetRecentPostsByUser(ctx, event.FolloweeID, removeLimit)
 	if err != nil {
 		return fmt.Errorf("get posts to remove: %w", err)
 	}
 
-	if len(posts) == 0 {
-		log.Printf("[Worker] UserUnfollowed: followee=%d has no posts to remove", event.FolloweeID)
-		return nil
-	}
-
 	log.Printf("[Worker] UserUnfollowed: removing %d posts from follower=%d", len(posts), event.FollowerID)
 
 	// Remove each post from follower's feed
@@ -235,9 +289,135 @@ func (h *Handler) handleUserUnfollowed(ctx context.Context, event queue.FeedEven
 	log.Printf("[Worker] UserUnfollowed DONE: follower=%d removed=%d failed=%d",
 		event.FollowerID, len(posts), failCount)
 
+	h.removeFolloweeReposts(ctx, event.FollowerID, event.FolloweeID)
+
+	return nil
+}
+
+// handlePostReposted fans out a reposted post to the reposter's followers' feed caches.
+// Followers who already have the post (original or an earlier repost) are skipped.
+func (h *Handler) handlePostReposted(ctx context.Context, event queue.FeedEvent) error {
+	log.Printf("[Worker] PostReposted: post=%d author=%d reposter=%d", event.PostID, event.AuthorID, event.ActorID)
+
+	followers, err := h.followerProvider.GetFollowerIDs(ctx, event.ActorID)
+	if err != nil {
+		return fmt.Errorf("get followers: %w", err)
+	}
+
+	var addedCount, failCount int
+	for _, followerID := range followers {
+		if followerID == event.AuthorID {
+			continue // The author doesn't need their own post back
+		}
+		added, err := h.feedCache.AddRepost(ctx, followerID, event.PostID, event.ActorID, event.Timestamp)
+		if err != nil {
+			log.Printf("[Worker] PostReposted: failed to add to user=%d err=%v", followerID, err)
+			failCount++
+			continue
+		}
+		if added {
+			addedCount++
+		}
+	}
+
+	log.Printf("[Worker] PostReposted DONE: post=%d followers=%d added=%d failed=%d",
+		event.PostID, len(followers), addedCount, failCount)
+
+	return nil
+}
+
+// handlePostUnreposted removes a post from the feeds it reached through this repost.
+func (h *Handler) handlePostUnreposted(ctx context.Context, event queue.FeedEvent) error {
+	log.Printf("[Worker] PostUnreposted: post=%d reposter=%d", event.PostID, event.ActorID)
+
+	followers, err := h.followerProvider.GetFollowerIDs(ctx, event.ActorID)
+	if err != nil {
+		return fmt.Errorf("get followers: %w", err)
+	}
+
+	var failCount int
+	for _, followerID := range followers {
+		if err := h.feedCache.RemoveRepost(ctx, followerID, event.PostID, event.ActorID); err != nil {
+			log.Printf("[Worker] PostUnreposted: failed to remove from user=%d err=%v", followerID, err)
+			failCount++
+		}
+	}
+
+	log.Printf("[Worker] PostUnreposted DONE: post=%d followers=%d failed=%d",
+		event.PostID, len(followers), failCount)
+
 	return nil
 }
 
+// removeReposted removes a deleted post from the feeds of everyone following one of its reposters.
+// Returns the number of feeds touched.
+func (h *Handler) removeReposted(ctx context.Context, postID int64) int {
+	if h.repostProvider == nil {
+		return 0
+	}
+
+	reposters, err := h.repostProvider.GetReposterIDs(ctx, postID)
+	if err != nil {
+		log.Printf("[Worker] PostDeleted: failed to get reposters of post=%d err=%v", postID, err)
+		return 0
+	}
+
+	var count int
+	for _, reposterID := range reposters {
+		followers, err := h.followerProvider.GetFollowerIDs(ctx, reposterID)
+		if err != nil {
+			log.Printf("[Worker] PostDeleted: failed to get followers of reposter=%d err=%v", reposterID, err)
+			continue
+		}
+		for _, followerID := range followers {
+			if err := h.feedCache.RemovePost(ctx, followerID, postID); err != nil {
+				log.Printf("[Worker] PostDeleted: failed to remove from user=%d err=%v", followerID, err)
+				continue
+			}
+			count++
+		}
+	}
+	return count
+}
+
+// backfillReposts adds the followee's recent reposts to the new follower's feed.
+func (h *Handler) backfillReposts(ctx context.Context, followerID, followeeID int64) {
+	if h.repostProvider == nil {
+		return
+	}
+
+	reposts, err := h.repostProvider.GetRecentRepostsByUser(ctx, followeeID, backfillLimit)
+	if err != nil {
+		log.Printf("[Worker] UserFollowed: failed to get reposts of followee=%d err=%v", followeeID, err)
+		return
+	}
+
+	for _, p := range reposts {
+		if _, err := h.feedCache.AddRepost(ctx, followerID, p.PostID, followeeID, p.Timestamp); err != nil {
+			log.Printf("[Worker] UserFollowed: failed to add repost post=%d err=%v", p.PostID, err)
+		}
+	}
+}
+
+// removeFolloweeReposts removes posts that reached the follower's feed through the followee's reposts.
+func (h *Handler) removeFolloweeReposts(ctx context.Context, followerID, followeeID int64) {
+	if h.repostProvider == nil {
+		return
+	}
+
+	reposts, err := h.repostProvider.GetRecentRepostsByUser(ctx, followeeID, removeLimit)
+	if err != nil {
+		log.Printf("[Worker] UserUnfollowed: failed to get reposts of followee=%d err=%v", followeeID, err)
+		return
+	}
+
+	for _, p := range reposts {
+		if err := h.feedCache.RemoveRepost(ctx, followerID, p.PostID, followeeID); err != nil {
+			log.Printf("[Worker] UserUnfollowed: failed to remove repost post=%d err=%v", p.PostID, err)
+		}
+	}
+}
+
 // handlePostLiked creates a notification for the post author when someone likes their post.
 func (h *Handler) handlePostLiked(ctx context.Context, event queue.FeedEvent) error {
 	log.Printf("[Worker] PostLiked: post=%d actor=%d recipient=%d", event.PostID, event.ActorID, event.RecipientID)
@@ -284,3 +464,129 @@ func (h *Handler) handlePostCommented(ctx context.Context, event queue.FeedEvent
 	log.Printf("[Worker] PostCommented DONE: notification created")
 	return nil
 }
+
+// handleCommentLiked creates a notification for the comment author when someone likes their comment.
+func (h *Handler) handleCommentLiked(ctx context.Context, event queue.FeedEvent) error {
+	log.Printf("[Worker] CommentLiked: post=%d actor=%d recipient=%d", event.PostID, event.ActorID, event.RecipientID)
+
+	if h.notifCreator == nil {
+		log.Printf("[Worker] CommentLiked: notification creator not set, skipping")
+		return nil
+	}
+
+	if event.ActorID == event.RecipientID {
+		return nil
+	}
+
+	postID := event.PostID
+	err := h.notifCreator.CreateNotification(ctx, event.RecipientID, event.ActorID, "comment_like", &postID, event.CommentID)
+	if err != nil {
+		return fmt.Errorf("create comment like notification: %w", err)
+	}
+
+	log.Printf("[Worker] CommentLiked DONE: notification created")
+	return nil
+}
+
+// handleCommentReplied creates a notification for the parent comment's author when someone replies.
+func (h *Handler) handleCommentReplied(ctx context.Context, event queue.FeedEvent) error {
+	log.Printf("[Worker] CommentReplied: post=%d actor=%d recipient=%d", event.PostID, event.ActorID, event.RecipientID)
+
+	if h.notifCreator == nil {
+		log.Printf("[Worker] CommentReplied: notification creator not set, skipping")
+		return nil
+	}
+
+	if event.ActorID == event.RecipientID {
+		return nil
+	}
+
+	postID := event.PostID
+	err := h.notifCreator.CreateNotification(ctx, event.RecipientID, event.ActorID, "comment_replied", &postID, event.CommentID)
+	if err != nil {
+		return fmt.Errorf("create comment reply notification: %w", err)
+	}
+
+	log.Printf("[Worker] CommentReplied DONE: notification created")
+	return nil
+}
+
+// handleUserTagged creates a notification for a user tagged in a post.
+func (h *Handler) handleUserTagged(ctx context.Context, event queue.FeedEvent) error {
+	log.Printf("[Worker] UserTagged: post=%d actor=%d recipient=%d", event.PostID, event.ActorID, event.RecipientID)
+
+	if h.notifCreator == nil {
+		log.Printf("[Worker] UserTagged: notification creator not set, skipping")
+		return nil
+	}
+
+	if event.ActorID == event.RecipientID {
+		return nil
+	}
+
+	postID := event.PostID
+	err := h.notifCreator.CreateNotification(ctx, event.RecipientID, event.ActorID, "tag", &postID, nil)
+	if err != nil {
+		return fmt.Errorf("create tag notification: %w", err)
+	}
+
+	log.Printf("[Worker] UserTagged DONE: notification created")
+	return nil
+}
+
+// handleUserMentioned creates a notification for a user @mentioned in a caption or comment.
+func (h *Handler) handleUserMentioned(ctx context.Context, event queue.FeedEvent) error {
+	log.Printf("[Worker] UserMentioned: post=%d actor=%d recipient=%d", event.PostID, event.ActorID, event.RecipientID)
+
+	if h.notifCreator == nil {
+		log.Printf("[Worker] UserMentioned: notification creator not set, skipping")
+		return nil
+	}
+
+	if event.ActorID == event.RecipientID {
+		return nil
+	}
+
+	postID := event.PostID
+	err := h.notifCreator.CreateNotification(ctx, event.RecipientID, event.ActorID, "mention", &postID, event.CommentID)
+	if err != nil {
+		return fmt.Errorf("create mention notification: %w", err)
+	}
+
+	log.Printf("[Worker] UserMentioned DONE: notification created")
+	return nil
+}
+
+// handleDataExportRequested builds a user's data export archive.
+func (h *Handler) handleDataExportRequested(ctx context.Context, event queue.FeedEvent) error {
+	log.Printf("[Worker] DataExportRequested: export=%d user=%d", event.ExportID, event.ActorID)
+
+	if h.exportBuilder == nil {
+		log.Printf("[Worker] DataExportRequested: export builder not configured, skipping")
+		return nil
+	}
+
+	if err := h.exportBuilder.BuildExport(ctx, event.ExportID); err != nil {
+		return fmt.Errorf("build export: %w", err)
+	}
+
+	log.Printf("[Worker] DataExportRequested DONE: export=%d", event.ExportID)
+	return nil
+}
+
+// handlePostMediaAdded fills in dominant colors, video metadata and poster frames for a new post.
+func (h *Handler) handlePostMediaAdded(ctx context.Context, event queue.FeedEvent) error {
+	log.Printf("[Worker] PostMediaAdded: post=%d author=%d", event.PostID, event.AuthorID)
+
+	if h.mediaProcessor == nil {
+		log.Printf("[Worker] PostMediaAdded: media processor not configured, skipping")
+		return nil
+	}
+
+	if err := h.mediaProcessor.ProcessPostMedia(ctx, event.PostID); err != nil {
+		return fmt.Errorf("process post media: %w", err)
+	}
+
+	log.Printf("[Worker] PostMediaAdded DONE: post=%d", event.PostID)
+	return nil
+}
@@ -18,12 +18,20 @@ const (
 
 	// DefaultBlockTimeout is how long to block waiting for new messages
 	DefaultBlockTimeout = 5 * time.Second
+
+	// ExportWorkerCount is the number of goroutines building data exports
+	ExportWorkerCount = 1
+
+	// MediaWorkerCount caps how many posts have their media processed (ffmpeg) at once
+	MediaWorkerCount = 2
 )
 
-// Manager orchestrates worker goroutines that consume from Redis Streams.
+// Manager orchestrates worker goroutines that consume from one Redis Stream.
 type Manager struct {
 	consumer    queue.Consumer
 	handler     *Handler
+	stream      string
+	group       string
 	workerCount int
 	batchSize   int64
 	blockTime   time.Duration
@@ -35,6 +43,8 @@ type Manager struct {
 
 // ManagerConfig holds configuration for the worker manager.
 type ManagerConfig struct {
+	Stream       string        // Stream to consume (default: feed stream)
+	Group        string        // Consumer group (default: feed workers)
 	WorkerCount  int           // Number of worker goroutines
 	BatchSize    int64         // Messages per read
 	BlockTimeout time.Duration // Block time for XREADGROUP
@@ -43,14 +53,41 @@ type ManagerConfig struct {
 // DefaultManagerConfig returns sensible defaults.
 func DefaultManagerConfig() ManagerConfig {
 	return ManagerConfig{
+		Stream:       queue.StreamFeed,
+		Group:        queue.ConsumerGroupFeed,
 		WorkerCount:  DefaultWorkerCount,
 		BatchSize:    DefaultBatchSize,
 		BlockTimeout: DefaultBlockTimeout,
 	}
 }
 
+// ExportManagerConfig consumes the data export stream, one export at a time.
+func ExportManagerConfig() ManagerConfig {
+	return ManagerConfig{
+		Stream:       queue.StreamExport,
+		Group:        queue.ConsumerGroupExport,
+		WorkerCount:  ExportWorkerCount,
+		BatchSize:    1,
+		BlockTimeout: DefaultBlockTimeout,
+	}
+}
+
+// MediaManagerConfig consumes the post media stream, one post per read.
+func MediaManagerConfig() ManagerConfig {
+	return ManagerConfig{
+		Stream:       queue.StreamMedia,
+		Group:        queue.ConsumerGroupMedia,
+		WorkerCount:  MediaWorkerCount,
+		BatchSize:    1,
+		BlockTimeout: DefaultBlockTimeout,
+	}
+}
+
 // NewManager creates a new worker manager.
 func NewManager(consumer queue.Consumer, handler *Handler, cfg ManagerConfig) *Manager {
+	if cfg.Stream == "" {
+		cfg.Stream, cfg.Group = queue.StreamFeed, queue.ConsumerGroupFeed
+	}
 	if cfg.WorkerCount <= 0 {
 		cfg.WorkerCount = DefaultWorkerCount
 	}
@@ -64,6 +101,8 @@ func NewManager(consumer queue.Consumer, handler *Handler, cfg ManagerConfig) *M
 	return &Manager{
 		consumer:    consumer,
 		handler:     handler,
+		stream:      cfg.Stream,
+		group:       cfg.Group,
 		workerCount: cfg.WorkerCount,
 		batchSize:   cfg.BatchSize,
 		blockTime:   cfg.BlockTimeout,
@@ -76,12 +115,12 @@ func (m *Manager) Start(ctx context.Context) error {
 	m.ctx, m.cancel = context.WithCancel(ctx)
 
 	// Ensure consumer group exists
-	if err := m.consumer.EnsureGroup(m.ctx, queue.StreamFeed, queue.ConsumerGroupFeed); err != nil {
+	if err := m.consumer.EnsureGroup(m.ctx, m.stream, m.group); err != nil {
 		return err
 	}
 
 	log.Printf("[Manager] Starting %d workers for stream=%s group=%s",
-		m.workerCount, queue.StreamFeed, queue.ConsumerGroupFeed)
+		m.workerCount, m.stream, m.group)
 
 	// Spin up worker goroutines
 	for i := 0; i < m.workerCount; i++ {
@@ -138,7 +177,7 @@ func (m *Manager) processPending(workerID int, consumerName string) {
 	}
 
 	for {
-		messages, err := rc.ReadPending(m.ctx, queue.StreamFeed, queue.ConsumerGroupFeed, consumerName, m.batchSize)
+		messages, err := rc.ReadPending(m.ctx, m.stream, m.group, consumerName, m.batchSize)
 		if err != nil {
 			log.Printf("[Worker-%d] Error reading pending: %v", workerID, err)
 			return
@@ -158,8 +197,8 @@ func (m *Manager) processPending(workerID int, consumerName string) {
 func (m *Manager) processMessages(workerID int, consumerName string) {
 	messages, err := m.consumer.Read(
 		m.ctx,
-		queue.StreamFeed,
-		queue.ConsumerGroupFeed,
+		m.stream,
+		m.group,
 		consumerName,
 		m.batchSize,
 		m.blockTime,
@@ -192,7 +231,7 @@ func (m *Manager) handleMessages(workerID int, messages []queue.Message) {
 		}
 
 		// Acknowledge the message
-		if err := m.consumer.Ack(m.ctx, queue.StreamFeed, queue.ConsumerGroupFeed, msg.ID); err != nil {
+		if err := m.consumer.Ack(m.ctx, m.stream, m.group, msg.ID); err != nil {
 			log.Printf("[Worker-%d] ACK error msgID=%s: %v", workerID, msg.ID, err)
 		}
 	}
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a periodic background task (e.g. recomputing trending hashtags).
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs on fixed intervals.
+// Each job runs once at startup, then every Interval. Runs of the same job never overlap.
+type Scheduler struct {
+	jobs []Job
+
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewScheduler creates an empty scheduler.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register adds a job. Must be called before Start.
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches one goroutine per job.
+// Call Stop() to gracefully shut down.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.runJob(job)
+	}
+
+	log.Printf("[Scheduler] Started %d jobs", len(s.jobs))
+}
+
+// Stop cancels all jobs and waits for running ones to finish.
+func (s *Scheduler) Stop() {
+	log.Printf("[Scheduler] Stopping jobs...")
+	s.cancel()
+	s.wg.Wait()
+	log.Printf("[Scheduler] All jobs stopped")
+}
+
+// runJob is the loop for a single job.
+func (s *Scheduler) runJob(job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	s.runOnce(job)
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(job)
+		}
+	}
+}
+
+// runOnce runs a job and logs the outcome. Errors don't stop future runs.
+func (s *Scheduler) runOnce(job Job) {
+	startTime := time.Now()
+	if err := job.Run(s.ctx); err != nil {
+		log.Printf("[Scheduler] Job %s FAILED: duration=%v err=%v", job.Name, time.Since(startTime), err)
+		return
+	}
+	log.Printf("[Scheduler] Job %s OK: duration=%v", job.Name, time.Since(startTime))
+}
@@ -76,6 +76,45 @@ func (m *MockPostsProvider) GetRecentPostsByUser(ctx context.Context, userID int
 	return posts, nil
 }
 
+// MockRepostProvider simulates the repost repository.
+type MockRepostProvider struct {
+	// reposts maps reposterID -> list of (postID, repost timestamp)
+	reposts map[int64][]cache.PostScore
+}
+
+func NewMockRepostProvider() *MockRepostProvider {
+	return &MockRepostProvider{
+		reposts: make(map[int64][]cache.PostScore),
+	}
+}
+
+func (m *MockRepostProvider) AddRepost(reposterID, postID int64, timestamp int64) {
+	m.reposts[reposterID] = append(m.reposts[reposterID], cache.PostScore{
+		PostID:    postID,
+		Timestamp: timestamp,
+	})
+}
+
+func (m *MockRepostProvider) GetReposterIDs(ctx context.Context, postID int64) ([]int64, error) {
+	var ids []int64
+	for reposterID, reposts := range m.reposts {
+		for _, r := range reposts {
+			if r.PostID == postID {
+				ids = append(ids, reposterID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (m *MockRepostProvider) GetRecentRepostsByUser(ctx context.Context, userID int64, limit int) ([]cache.PostScore, error) {
+	reposts := m.reposts[userID]
+	if len(reposts) > limit {
+		return reposts[:limit], nil
+	}
+	return reposts, nil
+}
+
 // =============================================================================
 // Test Helpers
 // =============================================================================
@@ -392,6 +431,94 @@ func TestUserUnfollowedRemoval(t *testing.T) {
 	t.Log("✓ User unfollowed removal works correctly")
 }
 
+// TestPostRepostedFanout tests that a repost reaches the reposter's followers
+// with attribution, skips feeds that already have the original, and is removed
+// everywhere when the original is deleted.
+func TestPostRepostedFanout(t *testing.T) {
+	// Setup
+	client := setupTestRedis(t)
+	defer cleanupTestRedis(client)
+
+	ctx := context.Background()
+	feedCache := cache.NewFeedCache(client)
+	mockFollowers := NewMockFollowerProvider()
+	mockPosts := NewMockPostsProvider()
+	mockReposts := NewMockRepostProvider()
+	handler := worker.NewHandler(feedCache, mockFollowers, mockPosts)
+	handler.SetRepostProvider(mockReposts)
+
+	// Scenario: User 1 (author), User 5 (reposter)
+	// User 2 follows both, User 3 follows only the reposter
+	authorID := int64(1)
+	reposterID := int64(5)
+	follower2 := int64(2)
+	follower3 := int64(3)
+
+	mockFollowers.AddFollower(authorID, follower2)
+	mockFollowers.AddFollower(reposterID, follower2)
+	mockFollowers.AddFollower(reposterID, follower3)
+
+	// User 2 already has the original
+	postID := int64(100)
+	postTime := time.Now().Add(-1 * time.Hour).Unix()
+	feedCache.AddPost(ctx, follower2, postID, postTime)
+
+	// User 5 reposts it
+	repostTime := time.Now().Unix()
+	mockReposts.AddRepost(reposterID, postID, repostTime)
+	err := handler.HandleEvent(ctx, queue.FeedEvent{
+		Type:      queue.EventPostReposted,
+		PostID:    postID,
+		AuthorID:  authorID,
+		ActorID:   reposterID,
+		Timestamp: repostTime,
+	})
+	if err != nil {
+		t.Fatalf("HandleEvent failed: %v", err)
+	}
+
+	// Verify: User 2 keeps the original (no bump, no attribution)
+	score, _, _ := feedCache.GetScore(ctx, follower2, postID)
+	if score != postTime {
+		t.Errorf("User 2's post score changed: got %d, want %d", score, postTime)
+	}
+	attr, _ := feedCache.GetRepostAttributions(ctx, follower2, []int64{postID})
+	if _, ok := attr[postID]; ok {
+		t.Errorf("User 2 should not have a repost attribution")
+	}
+
+	// Verify: User 3 gets the repost, attributed to User 5
+	score, found, _ := feedCache.GetScore(ctx, follower3, postID)
+	if !found || score != repostTime {
+		t.Errorf("User 3's feed: found=%v score=%d, want repost at %d", found, score, repostTime)
+	}
+	attr, _ = feedCache.GetRepostAttributions(ctx, follower3, []int64{postID})
+	if attr[postID] != reposterID {
+		t.Errorf("User 3's attribution: got %d, want %d", attr[postID], reposterID)
+	}
+
+	// User 1 deletes the original
+	err = handler.HandleEvent(ctx, queue.FeedEvent{
+		Type:      queue.EventPostDeleted,
+		PostID:    postID,
+		AuthorID:  authorID,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		t.Fatalf("HandleEvent failed: %v", err)
+	}
+
+	// Verify: post is gone from both feeds
+	for _, userID := range []int64{follower2, follower3} {
+		_, found, _ := feedCache.GetScore(ctx, userID, postID)
+		if found {
+			t.Errorf("Post %d should have been removed from user %d's feed", postID, userID)
+		}
+	}
+
+	t.Log("✓ Repost fan-out works correctly")
+}
+
 // TestFullWorkflow tests a complete user journey through the feed system.
 func TestFullWorkflow(t *testing.T) {
 	// Setup
@@ -636,3 +763,74 @@ func TestStreamToWorkerIntegration(t *testing.T) {
 
 	t.Log("✓ Stream to worker integration test passed")
 }
+
+// MockExportBuilder records the exports it was asked to build.
+type MockExportBuilder struct {
+	built chan int64
+}
+
+func (m *MockExportBuilder) BuildExport(ctx context.Context, exportID int64) error {
+	m.built <- exportID
+	return nil
+}
+
+func TestExportManagerConsumesExportStream(t *testing.T) {
+	client := setupTestRedis(t)
+	defer cleanupTestRedis(client)
+
+	ctx := context.Background()
+	publisher := queue.NewPublisher(client)
+	consumer := queue.NewConsumer(client)
+	handler := worker.NewHandler(cache.NewFeedCache(client), NewMockFollowerProvider(), NewMockPostsProvider())
+	builder := &MockExportBuilder{built: make(chan int64, 1)}
+	handler.SetExportBuilder(builder)
+
+	manager := worker.NewManager(consumer, handler, worker.ExportManagerConfig())
+	if err := manager.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer manager.Stop()
+
+	if _, err := publisher.Publish(ctx, queue.StreamExport, queue.NewDataExportRequestedEvent(42, 1)); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	select {
+	case id := <-builder.built:
+		if id != 42 {
+			t.Errorf("built export %d, want 42", id)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("export was not built")
+	}
+
+	// Exports must not queue behind (or in front of) feed events
+	if n, _ := client.XLen(ctx, queue.StreamFeed).Result(); n != 0 {
+		t.Errorf("feed stream has %d messages, want 0", n)
+	}
+
+	t.Log("✓ Export manager test passed")
+}
+
+// MockMediaProcessor records the posts it was asked to process.
+type MockMediaProcessor struct {
+	processed []int64
+}
+
+func (m *MockMediaProcessor) ProcessPostMedia(ctx context.Context, postID int64) error {
+	m.processed = append(m.processed, postID)
+	return nil
+}
+
+func TestPostMediaAddedProcessesMedia(t *testing.T) {
+	processor := &MockMediaProcessor{}
+	handler := worker.NewHandler(nil, NewMockFollowerProvider(), NewMockPostsProvider())
+	handler.SetMediaProcessor(processor)
+
+	if err := handler.HandleEvent(context.Background(), queue.NewPostMediaAddedEvent(100, 1)); err != nil {
+		t.Fatalf("HandleEvent failed: %v", err)
+	}
+	if len(processor.processed) != 1 || processor.processed[0] != 100 {
+		t.Errorf("processed %v, want [100]", processor.processed)
+	}
+}
@@ -0,0 +1,14 @@
+-- Media uploads: every presigned post upload is recorded so POST /posts can verify
+-- that media keys were issued to the same user and are only attached to one post.
+CREATE TABLE media_uploads (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    object_key VARCHAR(255) NOT NULL UNIQUE,
+    content_type VARCHAR(100) NOT NULL,
+    post_id BIGINT REFERENCES posts(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMPTZ
+);
+
+-- Lookup of a user's pending (not yet attached) uploads
+CREATE INDEX idx_media_uploads_user_pending ON media_uploads(user_id, created_at DESC) WHERE post_id IS NULL;
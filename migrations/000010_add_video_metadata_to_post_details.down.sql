@@ -0,0 +1,6 @@
+ALTER TABLE post_details
+DROP COLUMN IF EXISTS thumbnail_key,
+DROP COLUMN IF EXISTS thumbnail_url,
+DROP COLUMN IF EXISTS duration_ms,
+DROP COLUMN IF EXISTS height,
+DROP COLUMN IF EXISTS width;
@@ -0,0 +1,7 @@
+-- Media metadata for videos: dimensions, duration and a server-generated poster frame
+ALTER TABLE post_details
+ADD COLUMN width INT,
+ADD COLUMN height INT,
+ADD COLUMN duration_ms INT,
+ADD COLUMN thumbnail_url VARCHAR(512),
+ADD COLUMN thumbnail_key VARCHAR(255);
@@ -0,0 +1,3 @@
+ALTER TABLE post_details
+DROP COLUMN IF EXISTS dominant_color,
+DROP COLUMN IF EXISTS alt_text;
@@ -0,0 +1,4 @@
+-- Accessibility and layout hints per media item
+ALTER TABLE post_details
+ADD COLUMN alt_text TEXT,
+ADD COLUMN dominant_color VARCHAR(7);
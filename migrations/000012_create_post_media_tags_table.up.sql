@@ -0,0 +1,13 @@
+-- User tags placed on a specific media item; x/y are normalized (0..1) from the top-left corner
+CREATE TABLE post_media_tags (
+    id BIGSERIAL PRIMARY KEY,
+    media_id BIGINT NOT NULL REFERENCES post_details(id) ON DELETE CASCADE,
+    tagged_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    x REAL NOT NULL CHECK (x >= 0 AND x <= 1),
+    y REAL NOT NULL CHECK (y >= 0 AND y <= 1),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (media_id, tagged_user_id)
+);
+
+-- "Tagged" tab on profiles
+CREATE INDEX idx_post_media_tags_user ON post_media_tags(tagged_user_id);
@@ -0,0 +1,21 @@
+-- Hashtags: normalized (lowercase, without '#') names extracted from captions
+CREATE TABLE hashtags (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    post_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Prefix search (LIKE 'abc%')
+CREATE INDEX idx_hashtags_name_prefix ON hashtags(name varchar_pattern_ops);
+
+CREATE TABLE post_hashtags (
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    hashtag_id BIGINT NOT NULL REFERENCES hashtags(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, hashtag_id)
+);
+
+-- Hashtag pages and trending (usage in a time window)
+CREATE INDEX idx_post_hashtags_hashtag ON post_hashtags(hashtag_id, created_at DESC);
+CREATE INDEX idx_post_hashtags_created_at ON post_hashtags(created_at);
@@ -0,0 +1,20 @@
+-- @username references in captions (comment_id IS NULL) and comments.
+-- start_offset/length are in Unicode code points and include the '@'.
+CREATE TABLE mentions (
+    id BIGSERIAL PRIMARY KEY,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    comment_id BIGINT REFERENCES post_comments(id) ON DELETE CASCADE,
+    mentioned_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_offset INT NOT NULL,
+    length INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Caption mentions of a post
+CREATE INDEX idx_mentions_post ON mentions(post_id) WHERE comment_id IS NULL;
+
+-- Mentions of a comment
+CREATE INDEX idx_mentions_comment ON mentions(comment_id) WHERE comment_id IS NOT NULL;
+
+-- Where a user is mentioned
+CREATE INDEX idx_mentions_user ON mentions(mentioned_user_id);
@@ -0,0 +1,31 @@
+-- Private bookmarks
+CREATE TABLE saved_posts (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, post_id)
+);
+
+-- "Saved" list (cursor-friendly)
+CREATE INDEX idx_saved_posts_user_created ON saved_posts(user_id, created_at DESC, post_id DESC);
+
+-- Named groups of saved posts
+CREATE TABLE collections (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+-- A post in a collection is always also in saved_posts; unsaving removes it from every collection
+CREATE TABLE collection_posts (
+    collection_id BIGINT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, post_id)
+);
+
+CREATE INDEX idx_collection_posts_collection_created ON collection_posts(collection_id, created_at DESC, post_id DESC);
+CREATE INDEX idx_collection_posts_post ON collection_posts(post_id);
@@ -0,0 +1,15 @@
+-- Reposts: a user shares someone else's post with their own followers
+CREATE TABLE reposts (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, post_id)
+);
+
+-- Who reposted a post (removal when the original is deleted)
+CREATE INDEX idx_reposts_post ON reposts(post_id);
+
+-- A user's recent reposts (feed backfill / warm)
+CREATE INDEX idx_reposts_user_created ON reposts(user_id, created_at DESC);
+
+ALTER TABLE posts ADD COLUMN repost_count INT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS comments_disabled;
+ALTER TABLE posts DROP COLUMN IF EXISTS hide_like_count;
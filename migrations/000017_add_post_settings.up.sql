@@ -0,0 +1,3 @@
+-- Per-post settings chosen by the author
+ALTER TABLE posts ADD COLUMN hide_like_count BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE posts ADD COLUMN comments_disabled BOOLEAN NOT NULL DEFAULT FALSE;
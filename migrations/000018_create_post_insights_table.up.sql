@@ -0,0 +1,10 @@
+-- Post insights rolled up from Redis counters by the scheduler
+CREATE TABLE post_insights (
+    post_id BIGINT PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    impressions_feed BIGINT NOT NULL DEFAULT 0,
+    impressions_profile BIGINT NOT NULL DEFAULT 0,
+    impressions_other BIGINT NOT NULL DEFAULT 0,
+    reach BIGINT NOT NULL DEFAULT 0,          -- Unique viewers (HyperLogLog estimate)
+    profile_visits BIGINT NOT NULL DEFAULT 0, -- Author profile opened from this post
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+ALTER TABLE post_comments DROP COLUMN IF EXISTS like_count;
+DROP TABLE IF EXISTS comment_likes;
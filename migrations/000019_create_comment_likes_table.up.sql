@@ -0,0 +1,9 @@
+-- Comment likes: one like per user per comment
+CREATE TABLE comment_likes (
+    comment_id BIGINT NOT NULL REFERENCES post_comments(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id, user_id)
+);
+
+ALTER TABLE post_comments ADD COLUMN like_count INT NOT NULL DEFAULT 0;
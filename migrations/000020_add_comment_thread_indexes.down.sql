@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_post_comments_parent_created;
+DROP INDEX IF EXISTS idx_post_comments_post_top_level;
@@ -0,0 +1,7 @@
+-- Top-level comments of a post (thread listing)
+CREATE INDEX idx_post_comments_post_top_level ON post_comments(post_id, created_at DESC, id DESC)
+    WHERE parent_comment_id IS NULL;
+
+-- Replies of a comment, oldest first (reply pages + reply_count)
+CREATE INDEX idx_post_comments_parent_created ON post_comments(parent_comment_id, created_at, id)
+    WHERE parent_comment_id IS NOT NULL;
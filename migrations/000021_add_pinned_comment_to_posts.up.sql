@@ -0,0 +1,2 @@
+-- One pinned comment per post, unpinned automatically when the comment is deleted
+ALTER TABLE posts ADD COLUMN pinned_comment_id BIGINT REFERENCES post_comments(id) ON DELETE SET NULL;
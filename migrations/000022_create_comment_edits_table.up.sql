@@ -0,0 +1,12 @@
+ALTER TABLE post_comments ADD COLUMN edited_at TIMESTAMPTZ;
+
+-- Previous versions of edited comments (for moderation by the post author)
+CREATE TABLE comment_edits (
+    id BIGSERIAL PRIMARY KEY,
+    comment_id BIGINT NOT NULL REFERENCES post_comments(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    written_at TIMESTAMPTZ NOT NULL, -- When this version was written (created_at or previous edited_at)
+    replaced_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_comment_edits_comment ON comment_edits(comment_id, id DESC);
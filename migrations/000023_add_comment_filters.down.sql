@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_post_comments_hidden;
+ALTER TABLE post_comments DROP COLUMN IF EXISTS hidden_reason;
+DROP TABLE IF EXISTS comment_filter_settings;
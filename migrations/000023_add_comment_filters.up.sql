@@ -0,0 +1,14 @@
+-- Per-author comment filters, applied to comments on all of the author's posts
+CREATE TABLE comment_filter_settings (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    hidden_words TEXT[] NOT NULL DEFAULT '{}',
+    hide_offensive BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- NULL = visible; otherwise why the comment was hidden (hidden_word, offensive, spam)
+ALTER TABLE post_comments ADD COLUMN hidden_reason VARCHAR(20);
+
+-- Hidden comments of a post, for review by the post author
+CREATE INDEX idx_post_comments_hidden ON post_comments(post_id, created_at DESC, id DESC)
+    WHERE hidden_reason IS NOT NULL;
@@ -0,0 +1,10 @@
+-- Previous usernames, used to rate-limit changes and to resolve/reserve old handles
+CREATE TABLE username_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_username VARCHAR(50) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_username_history_user ON username_history(user_id, changed_at DESC);
+CREATE INDEX idx_username_history_old_username ON username_history(old_username, changed_at DESC);
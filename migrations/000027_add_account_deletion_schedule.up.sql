@@ -0,0 +1,5 @@
+-- Account deletion: DELETE /me sets the time after which the purge job removes the account.
+-- Logging in before then clears it.
+ALTER TABLE users ADD COLUMN deletion_scheduled_at TIMESTAMPTZ;
+
+CREATE INDEX idx_users_deletion_scheduled_at ON users(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
@@ -0,0 +1,19 @@
+-- Personal data exports: a ZIP built by the worker and stored in R2 until expires_at
+CREATE TABLE data_exports (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    object_key VARCHAR(255),
+    size_bytes BIGINT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_data_exports_user ON data_exports(user_id, created_at DESC);
+
+-- At most one export being built per user
+CREATE UNIQUE INDEX idx_data_exports_user_pending ON data_exports(user_id) WHERE status = 'pending';
+
+-- Cleanup job: ready archives past their expiry
+CREATE INDEX idx_data_exports_expires ON data_exports(expires_at) WHERE status = 'ready';
@@ -0,0 +1,14 @@
+-- Private accounts: following one creates a request the owner approves or declines
+ALTER TABLE users ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE follow_requests (
+    requester_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (requester_id, target_id)
+);
+
+CREATE INDEX idx_follow_requests_target ON follow_requests(target_id, created_at DESC);
+
+ALTER TABLE follow_requests ADD CONSTRAINT no_self_follow_request
+    CHECK (requester_id != target_id);
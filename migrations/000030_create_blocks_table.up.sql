@@ -0,0 +1,14 @@
+-- Blocks hide both users from each other everywhere; the row is one-directional
+-- (only the blocker can unblock) but checks look at both directions
+CREATE TABLE blocks (
+    blocker_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (blocker_id, blocked_id)
+);
+
+CREATE INDEX idx_blocks_blocker ON blocks(blocker_id, created_at DESC);
+CREATE INDEX idx_blocks_blocked ON blocks(blocked_id);
+
+ALTER TABLE blocks ADD CONSTRAINT no_self_block
+    CHECK (blocker_id != blocked_id);
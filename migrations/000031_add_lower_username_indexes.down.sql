@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_username_history_old_username_lower;
+CREATE INDEX idx_username_history_old_username ON username_history(old_username, changed_at DESC);
+
+DROP INDEX IF EXISTS idx_users_username_lower;
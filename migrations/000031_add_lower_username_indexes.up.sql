@@ -0,0 +1,5 @@
+-- Username availability and old-handle lookups ignore case
+CREATE INDEX idx_users_username_lower ON users(LOWER(username));
+
+DROP INDEX IF EXISTS idx_username_history_old_username;
+CREATE INDEX idx_username_history_old_username_lower ON username_history(LOWER(old_username), changed_at DESC);
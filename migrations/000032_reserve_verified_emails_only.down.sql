@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_email_lookup;
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX idx_users_email ON users(LOWER(email)) WHERE email IS NOT NULL;
@@ -0,0 +1,5 @@
+-- An address is only reserved once it's verified: an unverified claim can't block the
+-- real owner, who takes it over by verifying (see MarkEmailVerified)
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX idx_users_email ON users(LOWER(email)) WHERE email_verified;
+CREATE INDEX idx_users_email_lookup ON users(LOWER(email)) WHERE email IS NOT NULL;